@@ -0,0 +1,106 @@
+// accumulator.go
+//
+// Address balances: funds held by an address outside any coin object.
+//
+// A transaction draws on them through a funds-withdrawal input
+// (TxBuilder.InputFundsWithdrawal), and every balance it changes shows up in
+// the effects as an accumulator write rather than an object write:
+//
+//	w, err := b.InputFundsWithdrawal(gosuisdk.FundsWithdrawal{Amount: 1_000_000, CoinType: typetag.SUI})
+//	…
+//	writes, err := gosuisdk.AccumulatorWrites(resp.GetTransaction().GetEffects())
+//	for _, w := range writes {
+//		if coin, ok := w.CoinType(); ok {
+//			fmt.Println(w.Address, coin, w.Delta())
+//		}
+//	}
+
+package gosuisdk
+
+import (
+	"fmt"
+	"math/big"
+
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+// AccumulatorOperation is how an accumulator write changes the value.
+type AccumulatorOperation = pb.AccumulatorWrite_AccumulatorOperation
+
+const (
+	AccumulatorMerge = pb.AccumulatorWrite_MERGE // adds Value
+	AccumulatorSplit = pb.AccumulatorWrite_SPLIT // subtracts Value
+)
+
+// AccumulatorWrite is one accumulator changed by a transaction.
+type AccumulatorWrite struct {
+	// Object is the ID of the accumulator's field object.
+	Object ObjectID
+	// Address owns the accumulated value.
+	Address Address
+	// Type is the accumulated type: 0x2::balance::Balance<T> for an
+	// address balance of coin type T.
+	Type      typetag.TypeTag
+	Operation AccumulatorOperation
+	Value     uint64
+}
+
+// AccumulatorWrites reads the accumulator writes recorded in effects, in
+// the order of effects' changed objects.
+func AccumulatorWrites(effects *pb.TransactionEffects) ([]AccumulatorWrite, error) {
+	var writes []AccumulatorWrite
+	for _, c := range effects.GetChangedObjects() {
+		if c.GetOutputState() != pb.ChangedObject_OUTPUT_OBJECT_STATE_ACCUMULATOR_WRITE {
+			continue
+		}
+		acc := c.GetAccumulatorWrite()
+		if acc == nil {
+			return nil, fmt.Errorf("accumulator write %s: missing from effects", c.GetObjectId())
+		}
+		id, err := ParseObjectID(c.GetObjectId())
+		if err != nil {
+			return nil, fmt.Errorf("accumulator write: %w", err)
+		}
+		addr, err := ParseAddress(acc.GetAddress())
+		if err != nil {
+			return nil, fmt.Errorf("accumulator write %s: %w", id.Short(), err)
+		}
+		t, err := typetag.Parse(acc.GetAccumulatorType())
+		if err != nil {
+			return nil, fmt.Errorf("accumulator write %s: %w", id.Short(), err)
+		}
+		switch acc.GetOperation() {
+		case AccumulatorMerge, AccumulatorSplit:
+		default:
+			return nil, fmt.Errorf("accumulator write %s: unknown operation %s", id.Short(), acc.GetOperation())
+		}
+		writes = append(writes, AccumulatorWrite{
+			Object:    id,
+			Address:   addr,
+			Type:      t,
+			Operation: acc.GetOperation(),
+			Value:     acc.GetValue(),
+		})
+	}
+	return writes, nil
+}
+
+// CoinType returns T when w is an address balance, a Balance<T>.
+func (w AccumulatorWrite) CoinType() (typetag.TypeTag, bool) {
+	s := w.Type.Struct
+	if w.Type.Kind != typetag.Struct || len(s.TypeParams) != 1 ||
+		s.Address != SuiFrameworkPackageID || s.Module != "balance" || s.Name != "Balance" {
+		return typetag.TypeTag{}, false
+	}
+	return s.TypeParams[0], true
+}
+
+// Delta returns the signed change: Value for a merge, -Value for a split.
+func (w AccumulatorWrite) Delta() *big.Int {
+	d := new(big.Int).SetUint64(w.Value)
+	if w.Operation == AccumulatorSplit {
+		d.Neg(d)
+	}
+	return d
+}
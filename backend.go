@@ -0,0 +1,106 @@
+// backend.go
+//
+// Backend selection for the transaction builder.
+//
+// Every higher-level helper in this package builds transactions through the
+// TxBuilder interface and obtains fresh builders from a Backend, so the same
+// code runs on the WASM module (builder.go) or the CGo static library
+// (v2/builder_cgo.go, compiled with -tags txbuilder_cgo), or in pure Go
+// (gobuilder).
+//
+//	backend, err := gosuisdk.NewBackend(ctx, gosuisdk.DefaultBackendKind, mod)
+//	defer backend.Close(ctx)
+//	resp, err := split.SignExecuteTx(conn, backend, account, ctx)
+
+package gosuisdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pictorx/go-sui-sdk/gobuilder"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// TxBuilder is the API shared by all transaction builder backends.
+type TxBuilder = txbuilder.Builder
+
+// Tx is the typed, fluent layer over a TxBuilder; see txbuilder.Tx.
+type Tx = txbuilder.Tx
+
+// NewTx wraps b in a Tx, which takes ownership of it.
+func NewTx(b TxBuilder) *Tx { return txbuilder.NewTx(b) }
+
+// BackendKind names a transaction builder implementation.
+type BackendKind string
+
+const (
+	BackendWASM BackendKind = "wasm" // wazero + transaction_builder.wasm
+	BackendCGo  BackendKind = "cgo"  // libtransaction_builder.a via CGo
+	BackendGo   BackendKind = "go"   // gobuilder, no Rust artefact
+)
+
+// Backend hands out fresh builders of one implementation.
+type Backend interface {
+	Kind() BackendKind
+	NewBuilder() (TxBuilder, error)
+	// Close releases what the backend holds, such as a WASMPool's
+	// runtime.  Builders still in use fail afterwards.
+	Close(ctx context.Context) error
+}
+
+type wasmBackend struct {
+	ctx context.Context
+	in  *wasmInstance
+}
+
+// WASMBackend returns a Backend creating builders inside mod.
+// Builders from the same module must not be used concurrently.  Once a
+// builder traps, NewBuilder fails with ErrPoisoned; use a WASMPool to have
+// poisoned instances replaced.
+func WASMBackend(ctx context.Context, mod api.Module) Backend {
+	return &wasmBackend{ctx: ctx, in: instanceFor(mod)}
+}
+
+func (w *wasmBackend) Kind() BackendKind { return BackendWASM }
+
+func (w *wasmBackend) NewBuilder() (TxBuilder, error) {
+	return newBuilder(w.ctx, w.in)
+}
+
+// Close is a no-op: the module belongs to the caller.
+func (w *wasmBackend) Close(context.Context) error { return nil }
+
+type goBackend struct{}
+
+// GoBackend returns a Backend creating pure-Go builders.  It produces the
+// same transaction bytes as the WASM and CGo backends.
+func GoBackend() Backend { return goBackend{} }
+
+func (goBackend) Kind() BackendKind { return BackendGo }
+
+func (goBackend) NewBuilder() (TxBuilder, error) {
+	return gobuilder.NewBuilder(), nil
+}
+
+func (goBackend) Close(context.Context) error { return nil }
+
+// NewBackend selects a backend at runtime.  mod is only used by BackendWASM;
+// when nil, a WASMPool over the embedded module is returned.  Close the
+// backend when done with it.
+func NewBackend(ctx context.Context, kind BackendKind, mod api.Module) (Backend, error) {
+	switch kind {
+	case BackendWASM:
+		if mod == nil {
+			return NewWASMPool(ctx, WASMPoolConfig{})
+		}
+		return WASMBackend(ctx, mod), nil
+	case BackendCGo:
+		return CGoBackend()
+	case BackendGo:
+		return GoBackend(), nil
+	default:
+		return nil, fmt.Errorf("unknown builder backend %q", kind)
+	}
+}
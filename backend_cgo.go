@@ -0,0 +1,27 @@
+//go:build txbuilder_cgo
+
+package gosuisdk
+
+import (
+	"context"
+
+	v2 "github.com/pictorx/go-sui-sdk/v2"
+)
+
+// DefaultBackendKind is the backend selected by the build tags.
+var DefaultBackendKind = BackendCGo
+
+type cgoBackend struct{}
+
+// CGoBackend returns the Backend backed by the static library.
+func CGoBackend() (Backend, error) {
+	return cgoBackend{}, nil
+}
+
+func (cgoBackend) Kind() BackendKind { return BackendCGo }
+
+func (cgoBackend) NewBuilder() (TxBuilder, error) {
+	return v2.NewBuilder(nil, nil), nil
+}
+
+func (cgoBackend) Close(context.Context) error { return nil }
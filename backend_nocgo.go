@@ -0,0 +1,22 @@
+//go:build !txbuilder_cgo
+
+package gosuisdk
+
+import "fmt"
+
+// DefaultBackendKind is the backend selected by the build tags: the WASM
+// module when one is embedded, the pure-Go builder otherwise.
+var DefaultBackendKind = defaultBackendKind()
+
+func defaultBackendKind() BackendKind {
+	if _, err := EmbeddedWASM(); err != nil {
+		return BackendGo
+	}
+	return BackendWASM
+}
+
+// CGoBackend returns the Backend backed by the static library.  It is only
+// available when built with -tags txbuilder_cgo.
+func CGoBackend() (Backend, error) {
+	return nil, fmt.Errorf("cgo builder backend not compiled in; build with -tags txbuilder_cgo")
+}
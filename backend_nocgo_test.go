@@ -0,0 +1,32 @@
+//go:build !txbuilder_cgo
+
+package gosuisdk
+
+import (
+	"context"
+	"testing"
+)
+
+// The default backend must work for a module consumer without an embedded
+// WASM module.
+func TestDefaultBackendKind(t *testing.T) {
+	want := BackendWASM
+	if _, err := EmbeddedWASM(); err != nil {
+		want = BackendGo
+	}
+	if DefaultBackendKind != want {
+		t.Fatalf("DefaultBackendKind = %s, want %s", DefaultBackendKind, want)
+	}
+
+	ctx := context.Background()
+	backend, err := NewBackend(ctx, DefaultBackendKind, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close(ctx)
+	b, err := backend.NewBuilder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Free()
+}
@@ -0,0 +1,119 @@
+// Package bcs implements Binary Canonical Serialization, the encoding Sui
+// uses for transactions, Move values and object contents.
+//
+// Go types map to BCS as follows:
+//
+//	bool                     bool (0x00 / 0x01)
+//	uint8 … uint64           u8 … u64, little-endian
+//	int8 … int64             i8 … i64, two's complement little-endian
+//	U128, U256               u128, u256
+//	string                   ULEB128 length + UTF-8 bytes
+//	[]T                      ULEB128 length + elements
+//	[N]T                     N elements, no length ([32]byte is an address)
+//	*T                       Option<T>: 0x00, or 0x01 followed by T
+//	struct                   exported fields in declaration order
+//	interface (RegisterEnum) ULEB128 variant index + variant
+//
+// Struct fields accept a `bcs` tag:
+//
+//	bcs:"-"            skip the field
+//	bcs:"u128"         encode a *big.Int or big.Int field as u128
+//	bcs:"u256"         encode a *big.Int or big.Int field as u256
+//
+// Types implementing Marshaler / Unmarshaler control their own encoding.
+// int, uint, maps, floats, channels and functions are not supported.
+package bcs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Marshaler is implemented by types that encode themselves.
+type Marshaler interface {
+	MarshalBCS(e *Encoder) error
+}
+
+// Unmarshaler is implemented by types that decode themselves.  It is
+// called on a pointer to the value being decoded.
+type Unmarshaler interface {
+	UnmarshalBCS(d *Decoder) error
+}
+
+// Marshal returns the BCS encoding of v.  A top-level pointer is
+// dereferenced; nested pointers are Options.
+func Marshal(v any) ([]byte, error) {
+	e := NewEncoder()
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+// Unmarshal decodes data into the value pointed to by v.  All of data must
+// be consumed.
+func Unmarshal(data []byte, v any) error {
+	d := NewDecoder(data)
+	if err := d.Decode(v); err != nil {
+		return err
+	}
+	if n := d.Remaining(); n > 0 {
+		return fmt.Errorf("bcs: %d trailing bytes", n)
+	}
+	return nil
+}
+
+// ── Struct tags ───────────────────────────────────────────────────────────────
+
+type fieldTag string
+
+const (
+	tagNone fieldTag = ""
+	tagU128 fieldTag = "u128"
+	tagU256 fieldTag = "u256"
+)
+
+// field is an encoded struct field.
+type field struct {
+	index int
+	name  string
+	tag   fieldTag
+}
+
+// structFields lists the fields of t that take part in encoding.
+func structFields(t reflect.Type) ([]field, error) {
+	var out []field
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := fieldTag(f.Tag.Get("bcs"))
+		switch tag {
+		case "-":
+			continue
+		case tagNone:
+		case tagU128, tagU256:
+			if f.Type != bigIntType && f.Type != bigIntPtrType {
+				return nil, fmt.Errorf("bcs: %s.%s: tag %q needs a big.Int or *big.Int field", t, f.Name, tag)
+			}
+		default:
+			return nil, fmt.Errorf("bcs: %s.%s: unknown tag %q", t, f.Name, tag)
+		}
+		out = append(out, field{index: i, name: f.Name, tag: tag})
+	}
+	return out, nil
+}
+
+var (
+	marshalerType   = reflect.TypeFor[Marshaler]()
+	unmarshalerType = reflect.TypeFor[Unmarshaler]()
+)
+
+// isPlainByte reports whether t is a byte type with no custom encoding, so
+// sequences of it can be copied in bulk.
+func isPlainByte(t reflect.Type) bool {
+	return t.Kind() == reflect.Uint8 &&
+		!t.Implements(marshalerType) && !reflect.PointerTo(t).Implements(marshalerType) &&
+		!reflect.PointerTo(t).Implements(unmarshalerType)
+}
@@ -0,0 +1,253 @@
+package bcs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Golden vectors are the outputs of the Rust bcs crate (bcs::to_bytes) for
+// the equivalent Rust values, mostly taken from the crate's documentation.
+
+type ip [4]byte
+
+type port uint16
+
+type service struct {
+	IP            ip
+	Port          []port
+	ConnectionMax *uint32
+	Enabled       bool
+}
+
+// E mirrors `enum E { Variant0(u16), Variant1(u8), Variant2(String) }`.
+type E interface{ isE() }
+
+type variant0 struct{ V uint16 }
+type variant1 struct{ V uint8 }
+type variant2 struct{ V string }
+
+func (variant0) isE() {}
+func (variant1) isE() {}
+func (variant2) isE() {}
+
+// Unit mirrors `enum Unit { A, B(u64) }` with a pointer variant.
+type Unit interface{ isUnit() }
+
+type unitA struct{}
+type unitB struct{ V uint64 }
+
+type unitC struct{} // never registered
+
+func (unitA) isUnit()  {}
+func (*unitB) isUnit() {}
+func (unitC) isUnit()  {}
+
+func init() {
+	RegisterEnum[E](variant0{}, variant1{}, variant2{})
+	RegisterEnum[Unit](unitA{}, &unitB{})
+}
+
+// coin mirrors the contents of a 0x2::coin::Coin<T> object.
+type coin struct {
+	ID      [32]byte
+	Balance uint64
+}
+
+type tagged struct {
+	A    uint8
+	Skip string   `bcs:"-"`
+	Big  *big.Int `bcs:"u128"`
+	Huge big.Int  `bcs:"u256"`
+	priv int
+}
+
+func u32p(v uint32) *uint32 { return &v }
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func bigFromHex(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 16)
+	return n
+}
+
+func TestGoldenRoundTrip(t *testing.T) {
+	maxU128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	var sui [32]byte
+	sui[31] = 2
+
+	tests := []struct {
+		name  string
+		value any // pointer to the value
+		hex   string
+	}{
+		{"bool", ptr(true), "01"},
+		{"u8", ptr(uint8(1)), "01"},
+		{"u16", ptr(uint16(0x1234)), "3412"},
+		{"u32", ptr(uint32(0x12345678)), "78563412"},
+		{"u64", ptr(uint64(0x1234567890abcdef)), "efcdab9078563412"},
+		{"i8", ptr(int8(-1)), "ff"},
+		{"i16", ptr(int16(-4660)), "cced"},
+		{"i32", ptr(int32(-305419896)), "88a9cbed"},
+		{"i64", ptr(int64(-1)), "ffffffffffffffff"},
+		{"u128 max", &U128{maxU128}, strings.Repeat("ff", 16)},
+		{"u256", &U256{bigFromHex("8000000000000000000000000000000000000000000000000000000000000001")},
+			"01" + strings.Repeat("00", 30) + "80"},
+		{"string", ptr("çå∞≠¢õß∂ƒ∫"), "18 c3a7c3a5e2889ee289a0c2a2c3b5c39fe28882c692e288ab"},
+		{"empty string", ptr(""), "00"},
+		{"vector<u8>", &[]byte{1, 2, 3}, "03 010203"},
+		{"vector<u64>", &[]uint64{1, 2}, "02 0100000000000000 0200000000000000"},
+		{"vector<vector<u8>>", &[][]byte{{1}, {}}, "02 0101 00"},
+		{"[u16; 3]", &[3]uint16{1, 2, 3}, "010002000300"},
+		{"address", &sui, strings.Repeat("00", 31) + "02"},
+		{"Option<u8> Some", ptr(ptr(uint8(8))), "01 08"},
+		{"Option<u8> None", ptr((*uint8)(nil)), "00"},
+		{"Option<Option<u8>>", ptr(ptr((*uint8)(nil))), "01 00"},
+		{"service", &service{
+			IP:            ip{192, 168, 1, 1},
+			Port:          []port{8001, 8002, 8003},
+			ConnectionMax: u32p(5000),
+			Enabled:       false,
+		}, "c0a80101 03 411f 421f 431f 01 88130000 00"},
+		{"enum variant0", ptr[E](variant0{8000}), "00 401f"},
+		{"enum variant1", ptr[E](variant1{255}), "01 ff"},
+		{"enum variant2", ptr[E](variant2{"e"}), "02 01 65"},
+		{"enum unit", ptr[Unit](unitA{}), "00"},
+		{"enum pointer variant", ptr[Unit](&unitB{7}), "01 0700000000000000"},
+		{"vector<enum>", &[]E{variant1{1}, variant0{2}}, "02 01 01 00 0200"},
+		{"coin", &coin{ID: sui, Balance: 1_000_000_000},
+			strings.Repeat("00", 31) + "02 00ca9a3b00000000"},
+		{"tags", &tagged{A: 1, Big: big.NewInt(2), Huge: *big.NewInt(3)},
+			"01 02" + strings.Repeat("00", 15) + "03" + strings.Repeat("00", 31)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := mustHex(t, tt.hex)
+			got, err := Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("Marshal = %x, want %x", got, want)
+			}
+
+			out := reflect.New(reflect.TypeOf(tt.value).Elem())
+			if err := Unmarshal(want, out.Interface()); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !equal(out.Elem().Interface(), reflect.ValueOf(tt.value).Elem().Interface()) {
+				t.Fatalf("Unmarshal = %#v, want %#v", out.Elem().Interface(), reflect.ValueOf(tt.value).Elem().Interface())
+			}
+		})
+	}
+}
+
+func TestULEB128(t *testing.T) {
+	tests := []struct {
+		v   uint64
+		hex string
+	}{
+		{0, "00"},
+		{127, "7f"},
+		{128, "8001"},
+		{9487, "8f4a"},
+		{16384, "808001"},
+		{1<<32 - 1, "ffffffff0f"},
+	}
+	for _, tt := range tests {
+		e := NewEncoder()
+		e.WriteULEB128(tt.v)
+		if got := hex.EncodeToString(e.Bytes()); got != tt.hex {
+			t.Errorf("WriteULEB128(%d) = %s, want %s", tt.v, got, tt.hex)
+		}
+		got, err := NewDecoder(e.Bytes()).ReadULEB128()
+		if err != nil || got != tt.v {
+			t.Errorf("ReadULEB128(%s) = %d, %v", tt.hex, got, err)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		hex   string
+		into  any
+		match string
+	}{
+		{"non-canonical uleb", "8000", new([]byte), "non-canonical"},
+		{"uleb overflow", "ffffffff1f", new([]byte), "overflows"},
+		{"bool", "02", new(bool), "invalid bool"},
+		{"option tag", "02", new(*uint8), "invalid Option"},
+		{"trailing", "0100", new(uint8), "trailing"},
+		{"eof", "0100", new(uint32), "unexpected end"},
+		{"short vector", "05 01", new([]uint16), "unexpected end"},
+		{"utf8", "01 ff", new(string), "UTF-8"},
+		{"variant index", "03", new(E), "out of range"},
+		{"unregistered enum", "00", new(error), "not a registered enum"},
+		{"unsupported", "00", new(int), "unsupported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal(mustHex(t, tt.hex), tt.into)
+			if err == nil || !strings.Contains(err.Error(), tt.match) {
+				t.Fatalf("Unmarshal error = %v, want %q", err, tt.match)
+			}
+		})
+	}
+	if err := Unmarshal(nil, new(uint8)); !errors.Is(err, ErrUnexpectedEOF) {
+		t.Fatalf("Unmarshal(nil) = %v, want ErrUnexpectedEOF", err)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	tooBig := new(big.Int).Lsh(big.NewInt(1), 128)
+	tests := []struct {
+		name  string
+		value any
+		match string
+	}{
+		{"u128 range", U128{tooBig}, "out of range"},
+		{"negative", U256{big.NewInt(-1)}, "out of range"},
+		{"nil enum", ptr[E](nil), "nil"},
+		{"foreign variant", ptr[Unit](unitC{}), "not a variant"},
+		{"map", map[string]int{}, "unsupported"},
+		{"bad tag", struct {
+			A uint8 `bcs:"u128"`
+		}{}, "needs a big.Int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Marshal(tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.match) {
+				t.Fatalf("Marshal error = %v, want %q", err, tt.match)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// equal is reflect.DeepEqual with big.Int compared by value.
+func equal(a, b any) bool {
+	switch x := a.(type) {
+	case U128:
+		return x.Cmp(b.(U128).Int) == 0
+	case U256:
+		return x.Cmp(b.(U256).Int) == 0
+	case tagged:
+		y := b.(tagged)
+		return x.A == y.A && x.Big.Cmp(y.Big) == 0 && x.Huge.Cmp(&y.Huge) == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,76 @@
+// bigint.go
+//
+// u128 and u256 support.
+
+package bcs
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// U128 is a Move u128.  A nil Int encodes as zero.
+type U128 struct{ *big.Int }
+
+// U256 is a Move u256.  A nil Int encodes as zero.
+type U256 struct{ *big.Int }
+
+// MarshalBCS implements Marshaler.
+func (u U128) MarshalBCS(e *Encoder) error { return e.WriteBigInt(u.Int, 16) }
+
+// UnmarshalBCS implements Unmarshaler.
+func (u *U128) UnmarshalBCS(d *Decoder) (err error) {
+	u.Int, err = d.ReadBigInt(16)
+	return err
+}
+
+// MarshalBCS implements Marshaler.
+func (u U256) MarshalBCS(e *Encoder) error { return e.WriteBigInt(u.Int, 32) }
+
+// UnmarshalBCS implements Unmarshaler.
+func (u *U256) UnmarshalBCS(d *Decoder) (err error) {
+	u.Int, err = d.ReadBigInt(32)
+	return err
+}
+
+// WriteBigInt writes v as an unsigned little-endian integer of size bytes.
+func (e *Encoder) WriteBigInt(v *big.Int, size int) error {
+	if v == nil {
+		v = new(big.Int)
+	}
+	if v.Sign() < 0 || v.BitLen() > size*8 {
+		return fmt.Errorf("bcs: %v out of range for u%d", v, size*8)
+	}
+	buf := v.FillBytes(make([]byte, size))
+	for i, j := 0, size-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	e.buf = append(e.buf, buf...)
+	return nil
+}
+
+// ReadBigInt reads an unsigned little-endian integer of size bytes.
+func (d *Decoder) ReadBigInt(size int) (*big.Int, error) {
+	raw, err := d.ReadFixed(size)
+	if err != nil {
+		return nil, err
+	}
+	be := make([]byte, size)
+	for i, b := range raw {
+		be[size-1-i] = b
+	}
+	return new(big.Int).SetBytes(be), nil
+}
+
+var (
+	bigIntType    = reflect.TypeFor[big.Int]()
+	bigIntPtrType = reflect.TypeFor[*big.Int]()
+)
+
+func tagSize(tag fieldTag) int {
+	if tag == tagU128 {
+		return 16
+	}
+	return 32
+}
@@ -0,0 +1,294 @@
+// decode.go
+
+package bcs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"unicode/utf8"
+)
+
+// MaxSequenceLength is the largest vector or string length accepted, as in
+// the Rust bcs crate.
+const MaxSequenceLength = math.MaxInt32
+
+var byteType = reflect.TypeFor[byte]()
+
+// ErrUnexpectedEOF is returned when the input ends inside a value.
+var ErrUnexpectedEOF = errors.New("bcs: unexpected end of input")
+
+// Decoder reads BCS values from a byte slice.
+type Decoder struct {
+	data []byte
+	pos  int
+}
+
+// NewDecoder returns a Decoder reading data.
+func NewDecoder(data []byte) *Decoder { return &Decoder{data: data} }
+
+// Remaining returns the number of unread bytes.
+func (d *Decoder) Remaining() int { return len(d.data) - d.pos }
+
+// ReadFixed reads n bytes.  The result aliases the input.
+func (d *Decoder) ReadFixed(n int) ([]byte, error) {
+	if n < 0 || n > d.Remaining() {
+		return nil, ErrUnexpectedEOF
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *Decoder) ReadBool() (bool, error) {
+	b, err := d.ReadU8()
+	if err != nil {
+		return false, err
+	}
+	switch b {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	}
+	return false, fmt.Errorf("bcs: invalid bool byte 0x%02x", b)
+}
+
+func (d *Decoder) ReadU8() (uint8, error) {
+	b, err := d.ReadFixed(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *Decoder) ReadU16() (uint16, error) {
+	b, err := d.ReadFixed(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+func (d *Decoder) ReadU32() (uint32, error) {
+	b, err := d.ReadFixed(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (d *Decoder) ReadU64() (uint64, error) {
+	b, err := d.ReadFixed(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+// ReadULEB128 reads a canonical ULEB128 value that fits in a u32, as BCS
+// requires for lengths and enum indices.
+func (d *Decoder) ReadULEB128() (uint64, error) {
+	var v uint64
+	for shift := 0; shift < 32; shift += 7 {
+		b, err := d.ReadU8()
+		if err != nil {
+			return 0, err
+		}
+		v |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if shift > 0 && b == 0 {
+				return 0, errors.New("bcs: non-canonical ULEB128 encoding")
+			}
+			if v > math.MaxUint32 {
+				return 0, errors.New("bcs: ULEB128 value overflows u32")
+			}
+			return v, nil
+		}
+	}
+	return 0, errors.New("bcs: ULEB128 value overflows u32")
+}
+
+// ReadLength reads a sequence length.
+func (d *Decoder) ReadLength() (int, error) {
+	n, err := d.ReadULEB128()
+	if err != nil {
+		return 0, err
+	}
+	if n > MaxSequenceLength {
+		return 0, fmt.Errorf("bcs: sequence length %d exceeds %d", n, MaxSequenceLength)
+	}
+	return int(n), nil
+}
+
+// ReadBytes reads a vector<u8>.  The result aliases the input.
+func (d *Decoder) ReadBytes() ([]byte, error) {
+	n, err := d.ReadLength()
+	if err != nil {
+		return nil, err
+	}
+	return d.ReadFixed(n)
+}
+
+// ReadString reads a length-prefixed UTF-8 string.
+func (d *Decoder) ReadString() (string, error) {
+	b, err := d.ReadBytes()
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", errors.New("bcs: string is not valid UTF-8")
+	}
+	return string(b), nil
+}
+
+// Decode decodes into the value pointed to by v.
+func (d *Decoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("bcs: Decode needs a non-nil pointer, got %T", v)
+	}
+	return d.decode(rv.Elem(), tagNone)
+}
+
+func (d *Decoder) decode(v reflect.Value, tag fieldTag) error {
+	t := v.Type()
+	if tag != tagNone {
+		n, err := d.ReadBigInt(tagSize(tag))
+		if err != nil {
+			return err
+		}
+		if t.Kind() == reflect.Pointer {
+			v.Set(reflect.ValueOf(n))
+		} else {
+			v.Set(reflect.ValueOf(n).Elem())
+		}
+		return nil
+	}
+	if t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface &&
+		reflect.PointerTo(t).Implements(unmarshalerType) {
+		return v.Addr().Interface().(Unmarshaler).UnmarshalBCS(d)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := d.ReadBool()
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Uint8, reflect.Int8:
+		x, err := d.ReadU8()
+		return setInt(v, uint64(x), err, 8)
+	case reflect.Uint16, reflect.Int16:
+		x, err := d.ReadU16()
+		return setInt(v, uint64(x), err, 16)
+	case reflect.Uint32, reflect.Int32:
+		x, err := d.ReadU32()
+		return setInt(v, uint64(x), err, 32)
+	case reflect.Uint64, reflect.Int64:
+		x, err := d.ReadU64()
+		return setInt(v, x, err, 64)
+	case reflect.String:
+		s, err := d.ReadString()
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+
+	case reflect.Slice:
+		n, err := d.ReadLength()
+		if err != nil {
+			return err
+		}
+		if isPlainByte(t.Elem()) {
+			b, err := d.ReadFixed(n)
+			if err != nil {
+				return err
+			}
+			if t.Elem() == byteType {
+				v.SetBytes(append([]byte{}, b...))
+				return nil
+			}
+			s := reflect.MakeSlice(t, n, n)
+			for i, c := range b {
+				s.Index(i).SetUint(uint64(c))
+			}
+			v.Set(s)
+			return nil
+		}
+		// Every element takes at least one byte, which bounds the
+		// allocation by the input size.
+		if n > d.Remaining() {
+			return ErrUnexpectedEOF
+		}
+		s := reflect.MakeSlice(t, n, n)
+		if err := d.decodeElems(s); err != nil {
+			return err
+		}
+		v.Set(s)
+
+	case reflect.Array:
+		return d.decodeElems(v)
+
+	case reflect.Pointer: // Option<T>
+		tag, err := d.ReadU8()
+		if err != nil {
+			return err
+		}
+		switch tag {
+		case 0:
+			v.SetZero()
+		case 1:
+			p := reflect.New(t.Elem())
+			if err := d.decode(p.Elem(), tagNone); err != nil {
+				return err
+			}
+			v.Set(p)
+		default:
+			return fmt.Errorf("bcs: invalid Option tag 0x%02x", tag)
+		}
+
+	case reflect.Struct:
+		fields, err := structFields(t)
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			if err := d.decode(v.Field(f.index), f.tag); err != nil {
+				return fmt.Errorf("%s.%s: %w", t.Name(), f.name, err)
+			}
+		}
+
+	case reflect.Interface:
+		return d.decodeEnum(v)
+
+	default:
+		return fmt.Errorf("bcs: unsupported type %s", t)
+	}
+	return nil
+}
+
+func (d *Decoder) decodeElems(v reflect.Value) error {
+	for i := range v.Len() {
+		if err := d.decode(v.Index(i), tagNone); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// setInt stores the low bits of x in an unsigned or signed integer value.
+func setInt(v reflect.Value, x uint64, err error, bits uint) error {
+	if err != nil {
+		return err
+	}
+	if v.CanUint() {
+		v.SetUint(x)
+	} else {
+		v.SetInt(int64(x<<(64-bits)) >> (64 - bits)) // sign-extend
+	}
+	return nil
+}
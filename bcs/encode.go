@@ -0,0 +1,176 @@
+// encode.go
+
+package bcs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// Encoder appends BCS values to a buffer.
+type Encoder struct {
+	buf []byte
+}
+
+// NewEncoder returns an empty Encoder.
+func NewEncoder() *Encoder { return &Encoder{} }
+
+// Bytes returns the encoded bytes.
+func (e *Encoder) Bytes() []byte { return e.buf }
+
+func (e *Encoder) WriteBool(v bool) {
+	if v {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *Encoder) WriteU8(v uint8)   { e.buf = append(e.buf, v) }
+func (e *Encoder) WriteU16(v uint16) { e.buf = binary.LittleEndian.AppendUint16(e.buf, v) }
+func (e *Encoder) WriteU32(v uint32) { e.buf = binary.LittleEndian.AppendUint32(e.buf, v) }
+func (e *Encoder) WriteU64(v uint64) { e.buf = binary.LittleEndian.AppendUint64(e.buf, v) }
+
+// WriteULEB128 writes v as an unsigned LEB128 (lengths, enum indices).
+func (e *Encoder) WriteULEB128(v uint64) {
+	for v >= 0x80 {
+		e.buf = append(e.buf, byte(v)|0x80)
+		v >>= 7
+	}
+	e.buf = append(e.buf, byte(v))
+}
+
+// WriteFixed writes b with no length prefix.
+func (e *Encoder) WriteFixed(b []byte) { e.buf = append(e.buf, b...) }
+
+// WriteBytes writes b as a vector<u8>.
+func (e *Encoder) WriteBytes(b []byte) {
+	e.WriteULEB128(uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// WriteString writes s as a length-prefixed string.
+func (e *Encoder) WriteString(s string) {
+	e.WriteULEB128(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// Encode appends the encoding of v.  A top-level pointer is dereferenced.
+func (e *Encoder) Encode(v any) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return fmt.Errorf("bcs: cannot encode nil")
+	}
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("bcs: cannot encode nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	return e.encode(rv, tagNone)
+}
+
+func (e *Encoder) encode(v reflect.Value, tag fieldTag) error {
+	t := v.Type()
+	if tag != tagNone {
+		return e.encodeTaggedBigInt(v, tag)
+	}
+	if t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface {
+		if t.Implements(marshalerType) {
+			return v.Interface().(Marshaler).MarshalBCS(e)
+		}
+		if reflect.PointerTo(t).Implements(marshalerType) {
+			p := reflect.New(t)
+			p.Elem().Set(v)
+			return p.Interface().(Marshaler).MarshalBCS(e)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		e.WriteBool(v.Bool())
+	case reflect.Uint8:
+		e.WriteU8(uint8(v.Uint()))
+	case reflect.Uint16:
+		e.WriteU16(uint16(v.Uint()))
+	case reflect.Uint32:
+		e.WriteU32(uint32(v.Uint()))
+	case reflect.Uint64:
+		e.WriteU64(v.Uint())
+	case reflect.Int8:
+		e.WriteU8(uint8(v.Int()))
+	case reflect.Int16:
+		e.WriteU16(uint16(v.Int()))
+	case reflect.Int32:
+		e.WriteU32(uint32(v.Int()))
+	case reflect.Int64:
+		e.WriteU64(uint64(v.Int()))
+	case reflect.String:
+		e.WriteString(v.String())
+
+	case reflect.Slice:
+		if isPlainByte(t.Elem()) {
+			e.WriteBytes(v.Bytes())
+			return nil
+		}
+		e.WriteULEB128(uint64(v.Len()))
+		return e.encodeElems(v)
+
+	case reflect.Array:
+		if isPlainByte(t.Elem()) {
+			for i := range v.Len() {
+				e.buf = append(e.buf, uint8(v.Index(i).Uint()))
+			}
+			return nil
+		}
+		return e.encodeElems(v)
+
+	case reflect.Pointer: // Option<T>
+		if v.IsNil() {
+			e.WriteU8(0)
+			return nil
+		}
+		e.WriteU8(1)
+		return e.encode(v.Elem(), tagNone)
+
+	case reflect.Struct:
+		fields, err := structFields(t)
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			if err := e.encode(v.Field(f.index), f.tag); err != nil {
+				return fmt.Errorf("%s.%s: %w", t.Name(), f.name, err)
+			}
+		}
+
+	case reflect.Interface:
+		return e.encodeEnum(v)
+
+	default:
+		return fmt.Errorf("bcs: unsupported type %s", t)
+	}
+	return nil
+}
+
+func (e *Encoder) encodeElems(v reflect.Value) error {
+	for i := range v.Len() {
+		if err := e.encode(v.Index(i), tagNone); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (e *Encoder) encodeTaggedBigInt(v reflect.Value, tag fieldTag) error {
+	var n *big.Int
+	if v.Kind() == reflect.Pointer {
+		n = v.Interface().(*big.Int)
+	} else {
+		x := v.Interface().(big.Int)
+		n = &x
+	}
+	return e.WriteBigInt(n, tagSize(tag))
+}
@@ -0,0 +1,113 @@
+// enum.go
+//
+// Rust-style enums, modelled as Go interfaces with registered variants.
+
+package bcs
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// enumInfo lists the variants of one enum interface in index order.
+type enumInfo struct {
+	variants []reflect.Type
+	index    map[reflect.Type]int
+}
+
+var enums sync.Map // reflect.Type (interface) → *enumInfo
+
+// RegisterEnum declares the interface type T as an enum whose variants, in
+// BCS index order, have the dynamic types of variants.  A unit variant is an
+// empty struct.
+//
+//	type CallArg interface{ isCallArg() }
+//	type Pure struct{ Bytes []byte }
+//	type Object struct{ Arg ObjectArg }
+//
+//	func init() { bcs.RegisterEnum[CallArg](Pure{}, Object{}) }
+//
+// Variants may also be pointers (&Pure{}); the pointer is then not an Option.
+// RegisterEnum panics on misuse, like gob.Register.
+func RegisterEnum[T any](variants ...T) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("bcs: RegisterEnum: %s is not an interface", t))
+	}
+	info := &enumInfo{index: make(map[reflect.Type]int, len(variants))}
+	for i, v := range variants {
+		vt := reflect.TypeOf(v)
+		if vt == nil {
+			panic(fmt.Sprintf("bcs: RegisterEnum[%s]: variant %d is nil", t, i))
+		}
+		if _, dup := info.index[vt]; dup {
+			panic(fmt.Sprintf("bcs: RegisterEnum[%s]: duplicate variant %s", t, vt))
+		}
+		info.index[vt] = i
+		info.variants = append(info.variants, vt)
+	}
+	if _, loaded := enums.LoadOrStore(t, info); loaded {
+		panic(fmt.Sprintf("bcs: RegisterEnum: %s registered twice", t))
+	}
+}
+
+func lookupEnum(t reflect.Type) (*enumInfo, error) {
+	info, ok := enums.Load(t)
+	if !ok {
+		return nil, fmt.Errorf("bcs: interface %s is not a registered enum", t)
+	}
+	return info.(*enumInfo), nil
+}
+
+func (e *Encoder) encodeEnum(v reflect.Value) error {
+	info, err := lookupEnum(v.Type())
+	if err != nil {
+		return err
+	}
+	if v.IsNil() {
+		return fmt.Errorf("bcs: nil %s enum", v.Type())
+	}
+	inner := v.Elem()
+	idx, ok := info.index[inner.Type()]
+	if !ok {
+		return fmt.Errorf("bcs: %s is not a variant of %s", inner.Type(), v.Type())
+	}
+	e.WriteULEB128(uint64(idx))
+	if inner.Kind() == reflect.Pointer {
+		if inner.IsNil() {
+			return fmt.Errorf("bcs: nil %s variant", inner.Type())
+		}
+		inner = inner.Elem()
+	}
+	return e.encode(inner, tagNone)
+}
+
+func (d *Decoder) decodeEnum(v reflect.Value) error {
+	info, err := lookupEnum(v.Type())
+	if err != nil {
+		return err
+	}
+	idx, err := d.ReadULEB128()
+	if err != nil {
+		return err
+	}
+	if idx >= uint64(len(info.variants)) {
+		return fmt.Errorf("bcs: variant index %d out of range for %s", idx, v.Type())
+	}
+	vt := info.variants[idx]
+	if vt.Kind() == reflect.Pointer {
+		p := reflect.New(vt.Elem())
+		if err := d.decode(p.Elem(), tagNone); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+	nv := reflect.New(vt).Elem()
+	if err := d.decode(nv, tagNone); err != nil {
+		return err
+	}
+	v.Set(nv)
+	return nil
+}
@@ -0,0 +1,601 @@
+// Package bindgen generates typed Go bindings for a Move package.
+//
+// Generate turns the package returned by GetPackage into Go source with:
+//
+//   - a struct per Move struct and an interface per Move enum (variants
+//     registered with bcs.RegisterEnum), decodable with the bcs package
+//   - a function per public or entry Move function that appends the
+//     MoveCall to a txbuilder.Builder
+//
+// Names join the module and the item in CamelCase: pool::Pool becomes
+// PoolPool and pool::swap_exact becomes PoolSwapExact.  A function whose
+// name collides with a type gets a Call suffix.  Datatypes from other
+// packages that appear in fields are fetched with the Fetch callback and
+// generated alongside.
+//
+// Move types map to Go as in the bcs package; addresses are
+// txbuilder.Address, object::ID and object::UID are txbuilder.ObjectID,
+// String and ascii::String are string, and
+// Option<T> is *T.  Phantom type parameters are dropped.  Generic enums are
+// not supported; they, and datatypes containing them, are left out with a
+// comment.
+package bindgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+)
+
+// Options controls code generation.
+type Options struct {
+	// Package is the Go package name of the generated file.
+	Package string
+	// Network is recorded in the file header, e.g. "testnet".
+	Network string
+	// Fetch returns a datatype outside the package by its full type name
+	// ("0x…::module::Name").  When nil, such datatypes are not supported.
+	Fetch func(typeName string) (*pb.DatatypeDescriptor, error)
+}
+
+// Generate returns gofmt-ed Go bindings for pkg.
+func Generate(pkg *pb.Package, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		return nil, fmt.Errorf("bindgen: Options.Package is empty")
+	}
+	g := &generator{
+		pkg:   pkg,
+		opts:  opts,
+		local: map[string]*pb.DatatypeDescriptor{},
+		types: map[string]*goType{},
+		names: map[string]bool{},
+	}
+	for _, m := range pkg.GetModules() {
+		for _, d := range m.GetDatatypes() {
+			g.local[typeKey(d.GetTypeName())] = d
+		}
+	}
+	return g.generate()
+}
+
+type generator struct {
+	pkg   *pb.Package
+	opts  Options
+	local map[string]*pb.DatatypeDescriptor // by typeKey
+	types map[string]*goType                // by typeKey
+	order []*goType
+	names map[string]bool // Go identifiers in use
+
+	usesPure    bool // pureArg is needed
+	usesTypeTag bool // a generic function takes type arguments
+}
+
+// goType is a generated datatype.
+type goType struct {
+	desc    *pb.DatatypeDescriptor
+	name    string
+	local   bool
+	params  []int  // non-phantom type parameters, kept as Go type parameters
+	skip    string // why the datatype is not generated
+	decl    string // Go declaration
+	ordinal int    // position among local datatypes
+}
+
+func (g *generator) generate() ([]byte, error) {
+	ordinal := 0
+	for _, m := range g.pkg.GetModules() {
+		for _, d := range m.GetDatatypes() {
+			t := g.declare(typeKey(d.GetTypeName()))
+			t.ordinal = ordinal
+			ordinal++
+		}
+	}
+
+	var funcs bytes.Buffer
+	for _, m := range g.pkg.GetModules() {
+		for _, f := range m.GetFunctions() {
+			if f.GetVisibility() != pb.FunctionDescriptor_PUBLIC && !f.GetIsEntry() {
+				continue
+			}
+			g.function(&funcs, m.GetName(), f)
+		}
+	}
+
+	sort.SliceStable(g.order, func(i, j int) bool {
+		a, b := g.order[i], g.order[j]
+		if a.local != b.local {
+			return a.local
+		}
+		if a.local {
+			return a.ordinal < b.ordinal
+		}
+		return a.desc.GetTypeName() < b.desc.GetTypeName()
+	})
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by sui-bindgen from package %s (version %d)", g.pkg.GetStorageId(), g.pkg.GetVersion())
+	if g.opts.Network != "" {
+		fmt.Fprintf(&out, " on %s", g.opts.Network)
+	}
+	fmt.Fprintf(&out, ". DO NOT EDIT.\n\npackage %s\n\n", g.opts.Package)
+
+	// Every emitted datatype decodes with the bcs package, and pureArg
+	// encodes with it; skipped datatypes leave nothing behind.
+	usesBCS := g.usesPure
+	for _, t := range g.order {
+		usesBCS = usesBCS || t.skip == ""
+	}
+	var imports []string
+	if usesBCS {
+		imports = append(imports, `"github.com/pictorx/go-sui-sdk/bcs"`)
+	}
+	imports = append(imports, `"github.com/pictorx/go-sui-sdk/txbuilder"`)
+	if g.usesTypeTag {
+		imports = append(imports, `"github.com/pictorx/go-sui-sdk/typetag"`)
+	}
+	if len(imports) > 0 {
+		fmt.Fprintf(&out, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+
+	fmt.Fprintf(&out, "// PackageID is the package Move calls are sent to.  Set it to the latest\n")
+	fmt.Fprintf(&out, "// storage ID after an upgrade.\n")
+	fmt.Fprintf(&out, "var PackageID = txbuilder.MustParseObjectID(%q)\n\n", g.pkg.GetStorageId())
+
+	var enums []string
+	for _, t := range g.order {
+		if t.skip != "" {
+			fmt.Fprintf(&out, "// %s is not generated: %s.\n\n", t.desc.GetTypeName(), t.skip)
+			continue
+		}
+		out.WriteString(t.decl)
+		if t.desc.GetKind() == pb.DatatypeDescriptor_ENUM {
+			enums = append(enums, t.name)
+		}
+	}
+	if len(enums) > 0 {
+		out.WriteString("func init() {\n")
+		for _, name := range enums {
+			fmt.Fprintf(&out, "\tregister%s()\n", name)
+		}
+		out.WriteString("}\n\n")
+	}
+
+	out.Write(funcs.Bytes())
+	if g.usesPure {
+		out.WriteString(`// pureArg encodes v as a pure Move call argument.
+func pureArg(v any) (txbuilder.MoveCallArg, error) {
+	b, err := bcs.Marshal(v)
+	return txbuilder.ArgBCS(b), err
+}
+`)
+	}
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("bindgen: formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// ── Datatypes ─────────────────────────────────────────────────────────────────
+
+// declare returns the generated type for the datatype key, generating it
+// (and the datatypes its fields use) on first use.
+func (g *generator) declare(key string) *goType {
+	if t := g.types[key]; t != nil {
+		return t
+	}
+	desc, local := g.local[key]
+	if !local {
+		var err error
+		if g.opts.Fetch == nil {
+			err = fmt.Errorf("no Fetch callback for datatypes outside the package")
+		} else {
+			desc, err = g.opts.Fetch(key)
+		}
+		if err != nil {
+			t := &goType{desc: &pb.DatatypeDescriptor{TypeName: &key}, skip: err.Error()}
+			g.types[key] = t
+			g.order = append(g.order, t)
+			return t
+		}
+	}
+
+	t := &goType{desc: desc, local: local, name: g.ident(camel(desc.GetModule()) + camel(desc.GetName()))}
+	for i, p := range desc.GetTypeParameters() {
+		if !p.GetIsPhantom() {
+			t.params = append(t.params, i)
+		}
+	}
+	g.types[key] = t
+	g.order = append(g.order, t)
+
+	var err error
+	switch desc.GetKind() {
+	case pb.DatatypeDescriptor_ENUM:
+		if len(desc.GetTypeParameters()) > 0 {
+			err = fmt.Errorf("generic enums are not supported")
+		} else {
+			t.decl, err = g.enumDecl(t)
+		}
+	default:
+		t.decl, err = g.structDecl(t)
+	}
+	if err != nil {
+		t.skip = err.Error()
+	}
+	return t
+}
+
+// typeParams renders "[T0 any, T2 any]" for the kept type parameters.
+func (t *goType) typeParams() (decl, use string) {
+	if len(t.params) == 0 {
+		return "", ""
+	}
+	ds := make([]string, len(t.params))
+	us := make([]string, len(t.params))
+	for i, p := range t.params {
+		ds[i] = fmt.Sprintf("T%d any", p)
+		us[i] = fmt.Sprintf("T%d", p)
+	}
+	return "[" + strings.Join(ds, ", ") + "]", "[" + strings.Join(us, ", ") + "]"
+}
+
+func (g *generator) structDecl(t *goType) (string, error) {
+	fields, err := g.fields(t.desc.GetFields())
+	if err != nil {
+		return "", err
+	}
+	decl, use := t.typeParams()
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s mirrors the Move struct %s.\n", t.name, t.desc.GetTypeName())
+	fmt.Fprintf(&b, "type %s%s struct {\n%s}\n\n", t.name, decl, fields)
+	fmt.Fprintf(&b, "// DecodeBCS decodes a BCS-encoded %s::%s, e.g. object contents.\n", t.desc.GetModule(), t.desc.GetName())
+	fmt.Fprintf(&b, "func (v *%s%s) DecodeBCS(data []byte) error { return bcs.Unmarshal(data, v) }\n\n", t.name, use)
+	return b.String(), nil
+}
+
+func (g *generator) enumDecl(t *goType) (string, error) {
+	variants := append([]*pb.VariantDescriptor(nil), t.desc.GetVariants()...)
+	sort.Slice(variants, func(i, j int) bool { return variants[i].GetPosition() < variants[j].GetPosition() })
+
+	var b strings.Builder
+	marker := "is" + t.name
+	fmt.Fprintf(&b, "// %s mirrors the Move enum %s.  Its variants are the %s* types.\n", t.name, t.desc.GetTypeName(), t.name)
+	fmt.Fprintf(&b, "type %s interface{ %s() }\n\n", t.name, marker)
+
+	names := make([]string, len(variants))
+	for i, v := range variants {
+		fields, err := g.fields(v.GetFields())
+		if err != nil {
+			return "", fmt.Errorf("variant %s: %w", v.GetName(), err)
+		}
+		names[i] = g.ident(t.name + camel(v.GetName()))
+		fmt.Fprintf(&b, "// %s is the %s variant of %s.\n", names[i], v.GetName(), t.name)
+		if fields == "" {
+			fmt.Fprintf(&b, "type %s struct{}\n\n", names[i])
+		} else {
+			fmt.Fprintf(&b, "type %s struct {\n%s}\n\n", names[i], fields)
+		}
+		fmt.Fprintf(&b, "func (%s) %s() {}\n\n", names[i], marker)
+	}
+
+	fmt.Fprintf(&b, "func register%s() {\n\tbcs.RegisterEnum[%s](", t.name, t.name)
+	for i, n := range names {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(n + "{}")
+	}
+	b.WriteString(")\n}\n\n")
+
+	fmt.Fprintf(&b, "// Decode%s decodes a BCS-encoded %s::%s.\n", t.name, t.desc.GetModule(), t.desc.GetName())
+	fmt.Fprintf(&b, "func Decode%s(data []byte) (%s, error) {\n", t.name, t.name)
+	fmt.Fprintf(&b, "\tvar v %s\n\terr := bcs.Unmarshal(data, &v)\n\treturn v, err\n}\n\n", t.name)
+	return b.String(), nil
+}
+
+// fields renders struct fields in declaration order.
+func (g *generator) fields(fs []*pb.FieldDescriptor) (string, error) {
+	fs = append([]*pb.FieldDescriptor(nil), fs...)
+	sort.Slice(fs, func(i, j int) bool { return fs[i].GetPosition() < fs[j].GetPosition() })
+	var b strings.Builder
+	for _, f := range fs {
+		typ, err := g.goType(f.GetType())
+		if err != nil {
+			return "", fmt.Errorf("field %s: %w", f.GetName(), err)
+		}
+		fmt.Fprintf(&b, "\t%s %s\n", camel(f.GetName()), typ)
+	}
+	return b.String(), nil
+}
+
+// goType returns the Go type of a Move value type.
+func (g *generator) goType(t *pb.OpenSignatureBody) (string, error) {
+	switch t.GetType() {
+	case pb.OpenSignatureBody_BOOL:
+		return "bool", nil
+	case pb.OpenSignatureBody_U8:
+		return "uint8", nil
+	case pb.OpenSignatureBody_U16:
+		return "uint16", nil
+	case pb.OpenSignatureBody_U32:
+		return "uint32", nil
+	case pb.OpenSignatureBody_U64:
+		return "uint64", nil
+	case pb.OpenSignatureBody_U128:
+		return "bcs.U128", nil
+	case pb.OpenSignatureBody_U256:
+		return "bcs.U256", nil
+	case pb.OpenSignatureBody_ADDRESS:
+		return "txbuilder.Address", nil
+	case pb.OpenSignatureBody_TYPE_PARAMETER:
+		return fmt.Sprintf("T%d", t.GetTypeParameter()), nil
+	case pb.OpenSignatureBody_VECTOR:
+		elem, err := g.typeArg(t, 0)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case pb.OpenSignatureBody_DATATYPE:
+		key := typeKey(t.GetTypeName())
+		switch key {
+		case stdString, stdASCII:
+			return "string", nil
+		case fwID, fwUID:
+			return "txbuilder.ObjectID", nil
+		case stdOption:
+			elem, err := g.typeArg(t, 0)
+			if err != nil {
+				return "", err
+			}
+			return "*" + elem, nil
+		}
+		dt := g.declare(key)
+		if dt.skip != "" {
+			return "", fmt.Errorf("%s: %s", t.GetTypeName(), dt.skip)
+		}
+		if len(dt.params) == 0 {
+			return dt.name, nil
+		}
+		args := make([]string, len(dt.params))
+		for i, p := range dt.params {
+			a, err := g.typeArg(t, p)
+			if err != nil {
+				return "", err
+			}
+			args[i] = a
+		}
+		return dt.name + "[" + strings.Join(args, ", ") + "]", nil
+	}
+	return "", fmt.Errorf("unsupported type %s", t.GetType())
+}
+
+func (g *generator) typeArg(t *pb.OpenSignatureBody, i int) (string, error) {
+	inst := t.GetTypeParameterInstantiation()
+	if i >= len(inst) {
+		return "", fmt.Errorf("%s: missing type argument %d", t.GetTypeName(), i)
+	}
+	return g.goType(inst[i])
+}
+
+// ── Functions ─────────────────────────────────────────────────────────────────
+
+// function writes the MoveCall wrapper for f.
+func (g *generator) function(w *bytes.Buffer, module string, f *pb.FunctionDescriptor) {
+	name := camel(module) + camel(f.GetName())
+	if g.names[name] {
+		name += "Call"
+	}
+	name = g.ident(name)
+
+	params := f.GetParameters()
+	if n := len(params); n > 0 && typeKey(params[n-1].GetBody().GetTypeName()) == fwTxContext {
+		params = params[:n-1]
+	}
+
+	sig := []string{"b txbuilder.Builder"}
+	for i := range f.GetTypeParameters() {
+		sig = append(sig, fmt.Sprintf("typeT%d typetag.TypeTag", i))
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "\targs := make([]txbuilder.MoveCallArg, 0, %d)\n", len(params))
+	for i, p := range params {
+		arg := fmt.Sprintf("arg%d", i)
+		switch typ, pure := g.pureType(p.GetBody()); {
+		case p.GetBody().GetType() == pb.OpenSignatureBody_TYPE_PARAMETER:
+			sig = append(sig, arg+" txbuilder.MoveCallArg")
+			fmt.Fprintf(&body, "\targs = append(args, %s)\n", arg)
+		case pure:
+			g.usesPure = true
+			sig = append(sig, arg+" "+typ)
+			fmt.Fprintf(&body, "\tp%d, err := pureArg(&%s)\n\tif err != nil {\n\t\treturn 0, err\n\t}\n\targs = append(args, p%d)\n", i, arg, i)
+		default:
+			sig = append(sig, arg+" uint64")
+			fmt.Fprintf(&body, "\targs = append(args, txbuilder.ArgID(%s))\n", arg)
+		}
+	}
+
+	typeArgs := "nil"
+	if n := len(f.GetTypeParameters()); n > 0 {
+		ts := make([]string, n)
+		for i := range ts {
+			ts[i] = fmt.Sprintf("typeT%d", i)
+		}
+		typeArgs = "[]typetag.TypeTag{" + strings.Join(ts, ", ") + "}"
+		g.usesTypeTag = true
+	}
+
+	fmt.Fprintf(w, "// %s appends a call to %s::%s and returns its result.  Object and\n", name, module, f.GetName())
+	fmt.Fprintf(w, "// result arguments are Argument IDs.\n//\n//\t%s\n", moveSignature(f))
+	fmt.Fprintf(w, "func %s(%s) (uint64, error) {\n", name, strings.Join(sig, ", "))
+	w.WriteString(body.String())
+	fmt.Fprintf(w, "\treturn b.MoveCall(PackageID, %q, %q, %s, args)\n}\n\n", module, f.GetName(), typeArgs)
+}
+
+// pureType returns the Go type of a parameter passed as a pure value, or
+// false when the parameter takes an object or result.
+func (g *generator) pureType(t *pb.OpenSignatureBody) (string, bool) {
+	switch t.GetType() {
+	case pb.OpenSignatureBody_TYPE_PARAMETER, pb.OpenSignatureBody_TYPE_UNKNOWN:
+		return "", false
+	case pb.OpenSignatureBody_VECTOR:
+		inst := t.GetTypeParameterInstantiation()
+		if len(inst) != 1 {
+			return "", false
+		}
+		elem, ok := g.pureType(inst[0])
+		return "[]" + elem, ok
+	case pb.OpenSignatureBody_DATATYPE:
+		switch typeKey(t.GetTypeName()) {
+		case stdString, stdASCII:
+			return "string", true
+		case fwID:
+			return "txbuilder.ObjectID", true
+		case stdOption:
+			inst := t.GetTypeParameterInstantiation()
+			if len(inst) != 1 {
+				return "", false
+			}
+			elem, ok := g.pureType(inst[0])
+			return "*" + elem, ok
+		}
+		return "", false
+	}
+	typ, err := g.goType(t)
+	return typ, err == nil
+}
+
+// ── Names ─────────────────────────────────────────────────────────────────────
+
+// Well-known datatypes, by typeKey.
+var (
+	stdString   = typeKey("0x1::string::String")
+	stdASCII    = typeKey("0x1::ascii::String")
+	stdOption   = typeKey("0x1::option::Option")
+	fwID        = typeKey("0x2::object::ID")
+	fwUID       = typeKey("0x2::object::UID")
+	fwTxContext = typeKey("0x2::tx_context::TxContext")
+)
+
+// typeKey normalises the address of "addr::module::Name" so type names
+// compare regardless of address formatting.
+func typeKey(name string) string {
+	addr, rest, ok := strings.Cut(name, "::")
+	if !ok {
+		return name
+	}
+	a, err := txbuilder.ParseAddress(addr)
+	if err != nil {
+		return name
+	}
+	return fmt.Sprintf("%#x::%s", a[:], rest)
+}
+
+// ident reserves a unique Go identifier based on name.
+func (g *generator) ident(name string) string {
+	out := name
+	for i := 2; g.names[out]; i++ {
+		out = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[out] = true
+	return out
+}
+
+// initialisms are upper-cased whole, as in Go naming.
+var initialisms = map[string]string{
+	"id": "ID", "uid": "UID", "url": "URL", "uri": "URI", "nft": "NFT", "api": "API",
+}
+
+// camel converts snake_case Move names to exported CamelCase.
+func camel(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		if up, ok := initialisms[part]; ok {
+			b.WriteString(up)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if b.Len() == 0 {
+		return "X"
+	}
+	return b.String()
+}
+
+// moveSignature renders f as Move source for doc comments.
+func moveSignature(f *pb.FunctionDescriptor) string {
+	var b strings.Builder
+	if f.GetVisibility() == pb.FunctionDescriptor_PUBLIC {
+		b.WriteString("public ")
+	}
+	if f.GetIsEntry() {
+		b.WriteString("entry ")
+	}
+	b.WriteString("fun " + f.GetName())
+	if n := len(f.GetTypeParameters()); n > 0 {
+		ts := make([]string, n)
+		for i := range ts {
+			ts[i] = fmt.Sprintf("T%d", i)
+		}
+		b.WriteString("<" + strings.Join(ts, ", ") + ">")
+	}
+	b.WriteString("(" + signatures(f.GetParameters()) + ")")
+	switch rs := f.GetReturns(); len(rs) {
+	case 0:
+	case 1:
+		b.WriteString(": " + signatures(rs))
+	default:
+		b.WriteString(": (" + signatures(rs) + ")")
+	}
+	return b.String()
+}
+
+func signatures(ss []*pb.OpenSignature) string {
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		switch s.GetReference() {
+		case pb.OpenSignature_IMMUTABLE:
+			out[i] = "&"
+		case pb.OpenSignature_MUTABLE:
+			out[i] = "&mut "
+		}
+		out[i] += moveType(s.GetBody())
+	}
+	return strings.Join(out, ", ")
+}
+
+// moveType renders a type with module-qualified datatype names.
+func moveType(t *pb.OpenSignatureBody) string {
+	var name string
+	switch t.GetType() {
+	case pb.OpenSignatureBody_TYPE_PARAMETER:
+		return fmt.Sprintf("T%d", t.GetTypeParameter())
+	case pb.OpenSignatureBody_VECTOR:
+		name = "vector"
+	case pb.OpenSignatureBody_DATATYPE:
+		name = t.GetTypeName()
+		if _, rest, ok := strings.Cut(name, "::"); ok {
+			name = rest
+		}
+	default:
+		return strings.ToLower(t.GetType().String())
+	}
+	inst := t.GetTypeParameterInstantiation()
+	if len(inst) == 0 {
+		return name
+	}
+	args := make([]string, len(inst))
+	for i, a := range inst {
+		args[i] = moveType(a)
+	}
+	return name + "<" + strings.Join(args, ", ") + ">"
+}
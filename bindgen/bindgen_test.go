@@ -0,0 +1,234 @@
+package bindgen
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"google.golang.org/protobuf/proto"
+)
+
+// Generated bindings are compared with testdata/*.go.golden and
+// type-checked against the bcs and txbuilder packages of this module.  After
+// an intended change to the output, regenerate the goldens with
+//
+//	go test ./bindgen -update
+
+var update = flag.Bool("update", false, "rewrite testdata/*.go.golden")
+
+// ── Fixtures ──────────────────────────────────────────────────────────────────
+
+const fixturePackage = "0xc0ffee"
+
+func prim(t pb.OpenSignatureBody_Type) *pb.OpenSignatureBody {
+	return &pb.OpenSignatureBody{Type: &t}
+}
+
+func datatype(name string, args ...*pb.OpenSignatureBody) *pb.OpenSignatureBody {
+	return &pb.OpenSignatureBody{Type: pb.OpenSignatureBody_DATATYPE.Enum(), TypeName: &name, TypeParameterInstantiation: args}
+}
+
+func vector(elem *pb.OpenSignatureBody) *pb.OpenSignatureBody {
+	return &pb.OpenSignatureBody{Type: pb.OpenSignatureBody_VECTOR.Enum(), TypeParameterInstantiation: []*pb.OpenSignatureBody{elem}}
+}
+
+func typeParam(i uint32) *pb.OpenSignatureBody {
+	return &pb.OpenSignatureBody{Type: pb.OpenSignatureBody_TYPE_PARAMETER.Enum(), TypeParameter: &i}
+}
+
+func field(name string, t *pb.OpenSignatureBody) *pb.FieldDescriptor {
+	return &pb.FieldDescriptor{Name: &name, Type: t}
+}
+
+func structType(module, name string, params []*pb.TypeParameter, fields ...*pb.FieldDescriptor) *pb.DatatypeDescriptor {
+	for i, f := range fields {
+		f.Position = proto.Uint32(uint32(i))
+	}
+	return &pb.DatatypeDescriptor{
+		TypeName:       proto.String(fixturePackage + "::" + module + "::" + name),
+		Module:         &module,
+		Name:           &name,
+		Kind:           pb.DatatypeDescriptor_STRUCT.Enum(),
+		TypeParameters: params,
+		Fields:         fields,
+	}
+}
+
+func enumType(module, name string, params []*pb.TypeParameter, variants ...*pb.VariantDescriptor) *pb.DatatypeDescriptor {
+	for i, v := range variants {
+		v.Position = proto.Uint32(uint32(i))
+	}
+	return &pb.DatatypeDescriptor{
+		TypeName:       proto.String(fixturePackage + "::" + module + "::" + name),
+		Module:         &module,
+		Name:           &name,
+		Kind:           pb.DatatypeDescriptor_ENUM.Enum(),
+		TypeParameters: params,
+		Variants:       variants,
+	}
+}
+
+func variant(name string, fields ...*pb.FieldDescriptor) *pb.VariantDescriptor {
+	for i, f := range fields {
+		f.Position = proto.Uint32(uint32(i))
+	}
+	return &pb.VariantDescriptor{Name: &name, Fields: fields}
+}
+
+func param(t *pb.OpenSignatureBody, ref pb.OpenSignature_Reference) *pb.OpenSignature {
+	return &pb.OpenSignature{Reference: &ref, Body: t}
+}
+
+func function(name string, entry bool, typeParams int, params ...*pb.OpenSignature) *pb.FunctionDescriptor {
+	return &pb.FunctionDescriptor{
+		Name:           &name,
+		Visibility:     pb.FunctionDescriptor_PUBLIC.Enum(),
+		IsEntry:        &entry,
+		TypeParameters: make([]*pb.TypeParameter, typeParams),
+		Parameters:     params,
+	}
+}
+
+var (
+	u64       = prim(pb.OpenSignatureBody_U64)
+	u128      = prim(pb.OpenSignatureBody_U128)
+	txContext = param(datatype("0x2::tx_context::TxContext"), pb.OpenSignature_MUTABLE)
+	byValue   = pb.OpenSignature_REFERENCE_UNKNOWN
+)
+
+// poolPackage covers every mapping: primitives, vectors, Option, strings,
+// IDs, generics with a phantom parameter, a plain enum, and a generic enum
+// with the struct that contains it left out.
+func poolPackage() *pb.Package {
+	phantom := []*pb.TypeParameter{{IsPhantom: proto.Bool(true)}}
+	generic := []*pb.TypeParameter{{}}
+	return &pb.Package{
+		StorageId: proto.String(fixturePackage),
+		Version:   proto.Uint64(3),
+		Modules: []*pb.Module{{
+			Name: proto.String("pool"),
+			Datatypes: []*pb.DatatypeDescriptor{
+				structType("pool", "Pool", phantom,
+					field("id", datatype("0x2::object::UID")),
+					field("reserve", u64),
+					field("shares", u128),
+					field("owner", prim(pb.OpenSignatureBody_ADDRESS)),
+					field("name", datatype("0x1::string::String")),
+					field("fees", vector(u64)),
+					field("limit", datatype("0x1::option::Option", u64)),
+					field("state", datatype(fixturePackage+"::pool::State")),
+				),
+				enumType("pool", "State", nil,
+					variant("Open"),
+					variant("Paused", field("until", u64)),
+				),
+				structType("pool", "Wrapper", generic, field("value", typeParam(0))),
+				enumType("pool", "Either", generic, variant("Left", field("pos0", typeParam(0)))),
+				structType("pool", "Pending", nil,
+					field("total", u128),
+					field("side", datatype(fixturePackage+"::pool::Either", u64)),
+				),
+			},
+			Functions: []*pb.FunctionDescriptor{
+				function("deposit", false, 1,
+					param(datatype(fixturePackage+"::pool::Pool", typeParam(0)), pb.OpenSignature_MUTABLE),
+					param(datatype("0x2::coin::Coin", typeParam(0)), byValue),
+					param(u128, byValue),
+					param(datatype("0x1::option::Option", datatype("0x1::string::String")), byValue),
+					txContext,
+				),
+				function("pool", true, 0, param(vector(u64), byValue)),
+			},
+		}},
+	}
+}
+
+// skippedPackage emits no datatype and no pure parameter: the u128 field of
+// the skipped struct must not pull in the bcs import.
+func skippedPackage() *pb.Package {
+	generic := []*pb.TypeParameter{{}}
+	return &pb.Package{
+		StorageId: proto.String(fixturePackage),
+		Version:   proto.Uint64(1),
+		Modules: []*pb.Module{{
+			Name: proto.String("vault"),
+			Datatypes: []*pb.DatatypeDescriptor{
+				enumType("vault", "Slot", generic, variant("Full", field("pos0", typeParam(0)))),
+				structType("vault", "Vault", nil,
+					field("total", u128),
+					field("slot", datatype(fixturePackage+"::vault::Slot", u64)),
+				),
+			},
+			Functions: []*pb.FunctionDescriptor{
+				function("close", false, 0,
+					param(datatype(fixturePackage+"::vault::Vault"), byValue),
+					txContext,
+				),
+			},
+		}},
+	}
+}
+
+// ── Tests ─────────────────────────────────────────────────────────────────────
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  *pb.Package
+	}{
+		{"pool", poolPackage()},
+		{"skipped", skippedPackage()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := Generate(tt.pkg, Options{Package: tt.name, Network: "testnet"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			typeCheck(t, tt.name, src)
+
+			golden := filepath.Join("testdata", tt.name+".go.golden")
+			if *update {
+				if err := os.WriteFile(golden, src, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(src, want) {
+				t.Errorf("output differs from %s (rerun with -update after an intended change):\n%s", golden, src)
+			}
+		})
+	}
+}
+
+// typeCheck fails t unless src compiles, unused imports included.
+func typeCheck(t *testing.T, name string, src []byte) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name+".go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check(name, fset, []*ast.File{f}, nil); err != nil {
+		t.Fatalf("generated code does not compile: %v\n%s", err, src)
+	}
+}
+
+func TestGenerateRequiresPackage(t *testing.T) {
+	if _, err := Generate(poolPackage(), Options{}); err == nil {
+		t.Error("empty Options.Package accepted")
+	}
+}
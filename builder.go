@@ -4,16 +4,21 @@
 //
 // Usage:
 //
-//	b, err := txbuilder.NewBuilder(ctx, mod)
+//	b, err := gosuisdk.NewBuilder(ctx, mod)
 //	b.SetConfig(sender, 10_000_000, 1_000)
 //	b.AddGasObject(id, version, digest)
-//	gasID  := b.GasArgument()
-//	amtID  := b.PureU64(100_000_000)
-//	baseID := b.SplitCoins(gasID, []uint64{amtID})
-//	coinID := b.NestedResult(baseID, 0)
-//	recID  := b.PureAddress("0xabc…")
+//	gasID, _  := b.GasArgument()
+//	amtID, _  := b.PureU64(100_000_000)
+//	baseID, _ := b.SplitCoins(gasID, []uint64{amtID})
+//	coinID, _ := b.NestedResult(baseID, 0)
+//	recID, _  := b.PureAddress(gosuisdk.MustParseAddress("0xabc…"))
 //	b.TransferObjects([]uint64{coinID}, recID)
 //	bcsBytes, err := b.Build()
+//
+// Every guest call is checked: a trap is returned as a *TrapError and
+// poisons the module instance, after which every builder in it fails with
+// ErrPoisoned.  A rejected call returns a *txbuilder.Error read from the
+// module's last_error record.
 
 package gosuisdk
 
@@ -22,54 +27,158 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
-	"unsafe"
+	"math/big"
+	"sync"
 
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
 	"github.com/tetratelabs/wazero/api"
 )
 
 // ── internal memory helpers ───────────────────────────────────────────────────
 
-func writeBytes(ctx context.Context, mod api.Module, data []byte) (ptr uint64, size uint64) {
+// wasmInstance is a module instance shared by the builders created in it.
+// A trap leaves guest memory in an unknown state, so the first one poisons
+// the instance for good.
+type wasmInstance struct {
+	mod  api.Module
+	trap *TrapError
+}
+
+// instances holds the wasmInstance of every module builders were created
+// in, so NewBuilder, WASMBackend and WASMPool share poison state per module.
+var instances = struct {
+	sync.Mutex
+	m map[api.Module]*wasmInstance
+}{m: map[api.Module]*wasmInstance{}}
+
+// instanceFor returns the shared wasmInstance of mod.  Entries of closed
+// modules are dropped when a new module is registered.
+func instanceFor(mod api.Module) *wasmInstance {
+	instances.Lock()
+	defer instances.Unlock()
+	if in, ok := instances.m[mod]; ok {
+		return in
+	}
+	for m := range instances.m {
+		if m.IsClosed() {
+			delete(instances.m, m)
+		}
+	}
+	in := &wasmInstance{mod: mod}
+	instances.m[mod] = in
+	return in
+}
+
+// forgetInstance drops mod's entry once it is closed.
+func forgetInstance(mod api.Module) {
+	instances.Lock()
+	delete(instances.m, mod)
+	instances.Unlock()
+}
+
+// call invokes an exported function, turning traps and panics into errors.
+func (in *wasmInstance) call(ctx context.Context, name string, args ...uint64) (res []uint64, err error) {
+	if in.trap != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPoisoned, in.trap)
+	}
+	fn := in.mod.ExportedFunction(name)
+	if fn == nil {
+		return nil, fmt.Errorf("txbuilder: module does not export %s", name)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if rerr, ok := r.(error); ok {
+				err = in.poison(name, rerr)
+			} else {
+				err = in.poison(name, fmt.Errorf("%v", r))
+			}
+		}
+	}()
+	res, err = fn.Call(ctx, args...)
+	if err != nil {
+		return nil, in.poison(name, err)
+	}
+	return res, nil
+}
+
+func (in *wasmInstance) poison(name string, err error) *TrapError {
+	trap := newTrapError(name, err)
+	if in.trap == nil {
+		in.trap = trap
+	}
+	return trap
+}
+
+func (in *wasmInstance) writeBytes(ctx context.Context, data []byte) (ptr uint64, size uint64, err error) {
 	if len(data) == 0 {
-		return 0, 0
+		return 0, 0, nil
 	}
-	res, err := mod.ExportedFunction("alloc").Call(ctx, uint64(len(data)))
+	res, err := in.call(ctx, "alloc", uint64(len(data)))
 	if err != nil {
-		panic(fmt.Sprintf("txbuilder alloc: %v", err))
+		return 0, 0, err
 	}
 	ptr = res[0]
-	if !mod.Memory().Write(uint32(ptr), data) {
-		panic("txbuilder: Memory.Write failed")
+	if !in.mod.Memory().Write(uint32(ptr), data) {
+		return 0, 0, in.poison("alloc", errMemoryAccess("write", ptr, len(data)))
 	}
-	return ptr, uint64(len(data))
+	return ptr, uint64(len(data)), nil
 }
 
-func freeBytes_(ctx context.Context, mod api.Module, ptr, size uint64) {
+// freeBytes releases a buffer from writeBytes.  A failure poisons the
+// instance; the caller's result is still valid.
+func (in *wasmInstance) freeBytes(ctx context.Context, ptr, size uint64) {
 	if ptr == 0 {
 		return
 	}
-	mod.ExportedFunction("dealloc").Call(ctx, ptr, size) //nolint:errcheck
+	in.call(ctx, "dealloc", ptr, size) //nolint:errcheck // recorded in in.trap
 }
 
-func callFn(ctx context.Context, mod api.Module, name string, args ...uint64) []uint64 {
-	res, err := mod.ExportedFunction(name).Call(ctx, args...)
-	if err != nil {
-		panic(fmt.Sprintf("txbuilder %s: %v", name, err))
+// u64SlicePtr writes a []uint64 into WASM memory as a C uint64_t array and
+// returns (wasmPtr, byteSize).  Caller must free with freeBytes(ptr, size).
+func (in *wasmInstance) u64SlicePtr(ctx context.Context, ids []uint64) (uint64, uint64, error) {
+	if len(ids) == 0 {
+		return 0, 0, nil
 	}
-	return res
+	buf := make([]byte, 0, len(ids)*8)
+	for _, v := range ids {
+		buf = binary.LittleEndian.AppendUint64(buf, v)
+	}
+	return in.writeBytes(ctx, buf)
 }
 
-// u64SlicePtr writes a []uint64 into WASM memory as a C uint64_t array and
-// returns (wasmPtr, byteSize).  Caller must free with dealloc(ptr, size).
-func u64SlicePtr(ctx context.Context, mod api.Module, ids []uint64) (uint64, uint64) {
-	if len(ids) == 0 {
-		return 0, 0
+// lastError reads the last_error record left by a failing call.  Modules
+// built before the error channel existed fall back to the bare code.
+func (in *wasmInstance) lastError(ctx context.Context, fn string, code int64) error {
+	if in.mod.ExportedFunction("last_error_length") == nil {
+		return txbuilder.DecodeError(fn, code, nil)
+	}
+	res, err := in.call(ctx, "last_error_length")
+	if err != nil {
+		return err
 	}
-	buf := make([]byte, len(ids)*8)
-	for i, v := range ids {
-		*(*uint64)(unsafe.Pointer(&buf[i*8])) = v
+	n := uint64(uint32(res[0]))
+	if n == 0 {
+		return txbuilder.DecodeError(fn, code, nil)
+	}
+	res, err = in.call(ctx, "alloc", n)
+	if err != nil {
+		return err
 	}
-	return writeBytes(ctx, mod, buf)
+	ptr := res[0]
+	defer in.freeBytes(ctx, ptr, n)
+	res, err = in.call(ctx, "last_error_message", ptr, n)
+	if err != nil {
+		return err
+	}
+	if int64(res[0]) < 0 {
+		return txbuilder.DecodeError(fn, code, nil)
+	}
+	data, ok := in.mod.Memory().Read(uint32(ptr), uint32(res[0]))
+	if !ok {
+		return in.poison("last_error_message", errMemoryAccess("read", ptr, int(res[0])))
+	}
+	return txbuilder.DecodeError(fn, code, data)
 }
 
 // ── Builder ───────────────────────────────────────────────────────────────────
@@ -78,93 +187,162 @@ func u64SlicePtr(ctx context.Context, mod api.Module, ids []uint64) (uint64, uin
 // It is NOT safe for concurrent use.
 type Builder struct {
 	ctx context.Context
-	mod api.Module
+	in  *wasmInstance
 	ptr uint64 // opaque pointer into WASM linear memory
 }
 
+var _ txbuilder.Cloner = (*Builder)(nil)
+
 // NewBuilder instantiates a fresh TransactionBuilder inside the WASM module.
-func NewBuilder(ctx context.Context, mod api.Module) *Builder {
-	ptr := callFn(ctx, mod, "new_builder")[0]
-	return &Builder{ctx: ctx, mod: mod, ptr: ptr}
+// Builders created in one module share its poison state.
+func NewBuilder(ctx context.Context, mod api.Module) (*Builder, error) {
+	return newBuilder(ctx, instanceFor(mod))
+}
+
+func newBuilder(ctx context.Context, in *wasmInstance) (*Builder, error) {
+	res, err := in.call(ctx, "new_builder")
+	if err != nil {
+		return nil, err
+	}
+	return &Builder{ctx: ctx, in: in, ptr: res[0]}, nil
+}
+
+// Poisoned reports the trap that poisoned the builder's module instance, or
+// nil.
+func (b *Builder) Poisoned() *TrapError {
+	return b.in.trap
 }
 
 // Free releases a builder that was NOT consumed by Build().
-// After a successful Build() call the builder is already freed — do not call
-// Free() in that case.
+// After a successful Build() call the builder is already freed — calling
+// Free() then is a no-op.
 func (b *Builder) Free() {
 	if b.ptr != 0 {
-		callFn(b.ctx, b.mod, "free_builder", b.ptr)
+		b.in.call(b.ctx, "free_builder", b.ptr) //nolint:errcheck // recorded in in.trap
 		b.ptr = 0
 	}
 }
 
+// call invokes a builder function with the builder pointer prepended.
+func (b *Builder) call(name string, args ...uint64) ([]uint64, error) {
+	if b.ptr == 0 {
+		return nil, txbuilder.ErrConsumed
+	}
+	return b.in.call(b.ctx, name, append([]uint64{b.ptr}, args...)...)
+}
+
+// callBytes copies data into guest memory and calls name(builder, ptr, len).
+func (b *Builder) callBytes(name string, data []byte) (int64, error) {
+	if b.ptr == 0 {
+		return 0, txbuilder.ErrConsumed
+	}
+	ptr, size, err := b.in.writeBytes(b.ctx, data)
+	if err != nil {
+		return 0, err
+	}
+	defer b.in.freeBytes(b.ctx, ptr, size)
+	res, err := b.call(name, ptr, size)
+	if err != nil {
+		return 0, err
+	}
+	return int64(res[0]), nil
+}
+
+// callJSON marshals payload and passes it to name via callBytes.
+func (b *Builder) callJSON(name string, payload any) (int64, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return b.callBytes(name, data)
+}
+
+// fail builds the error for a call to name that returned code.
+func (b *Builder) fail(name string, code int64) error {
+	return b.in.lastError(b.ctx, name, code)
+}
+
+// argID converts an Argument ID result, fetching the error for a negative one.
+func (b *Builder) argID(name string, res int64, err error) (uint64, error) {
+	if err != nil {
+		return 0, err
+	}
+	if res < 0 {
+		return 0, b.fail(name, res)
+	}
+	return uint64(res), nil
+}
+
+// status checks a 1-on-success result returned as a C int32_t.
+func (b *Builder) status(name string, code int64, err error) error {
+	if err != nil {
+		return err
+	}
+	if int32(code) != 1 {
+		return b.fail(name, int64(int32(code)))
+	}
+	return nil
+}
+
+// callID calls a function returning an Argument ID.
+func (b *Builder) callID(name string, args ...uint64) (uint64, error) {
+	res, err := b.call(name, args...)
+	if err != nil {
+		return 0, err
+	}
+	return b.argID(name, int64(res[0]), nil)
+}
+
 // ── Configuration ─────────────────────────────────────────────────────────────
 
 // SetConfig sets the sender address, gas budget, and gas price.
-// sender must be a 0x-prefixed 32-byte hex string.
-func (b *Builder) SetConfig(sender string, gasBudget, gasPrice uint64) error {
-	payload, _ := json.Marshal(map[string]any{
+func (b *Builder) SetConfig(sender Address, gasBudget, gasPrice uint64) error {
+	code, err := b.callJSON("set_config", map[string]any{
 		"sender":     sender,
 		"gas_budget": gasBudget,
 		"gas_price":  gasPrice,
 	})
-	ptr, size := writeBytes(b.ctx, b.mod, payload)
-	defer freeBytes_(b.ctx, b.mod, ptr, size)
-	code := int32(callFn(b.ctx, b.mod, "set_config", b.ptr, ptr, size)[0])
-	if code != 1 {
-		return fmt.Errorf("set_config failed (code %d) — check sender address format", code)
-	}
-	return nil
+	return b.status("set_config", code, err)
 }
 
 // ── Gas objects ───────────────────────────────────────────────────────────────
 
 // AddGasObject adds an owned gas coin identified by its object ID, version,
-// and base-58 digest string.
-func (b *Builder) AddGasObject(id string, version uint64, digest string) error {
-	payload, _ := json.Marshal(map[string]any{
+// and digest.
+func (b *Builder) AddGasObject(id ObjectID, version uint64, digest Digest) error {
+	code, err := b.callJSON("add_gas_object", map[string]any{
 		"id":      id,
 		"version": version,
 		"digest":  digest,
 	})
-	ptr, size := writeBytes(b.ctx, b.mod, payload)
-	defer freeBytes_(b.ctx, b.mod, ptr, size)
-	code := int32(callFn(b.ctx, b.mod, "add_gas_object", b.ptr, ptr, size)[0])
-	switch code {
-	case 1:
-		return nil
-	case -2:
-		return fmt.Errorf("add_gas_object: invalid digest %q", digest)
-	default:
-		return fmt.Errorf("add_gas_object failed (code %d)", code)
-	}
+	return b.status("add_gas_object", code, err)
 }
 
 // ── Gas pseudo-input ──────────────────────────────────────────────────────────
 
 // GasArgument returns the Argument ID for the transaction's gas coin.
 // Idempotent — always returns the same ID within one builder.
-func (b *Builder) GasArgument() uint64 {
-	return callFn(b.ctx, b.mod, "gas_argument", b.ptr)[0]
+func (b *Builder) GasArgument() (uint64, error) {
+	return b.callID("gas_argument")
 }
 
 // ── Object inputs ─────────────────────────────────────────────────────────────
 
 // ObjectKind describes how an object is used as an input.
-type ObjectKind string
+type ObjectKind = txbuilder.ObjectKind
 
 const (
-	ObjectKindOwned     ObjectKind = "owned"
-	ObjectKindImmutable ObjectKind = "immutable"
-	ObjectKindReceiving ObjectKind = "receiving"
-	ObjectKindShared    ObjectKind = "shared"
+	ObjectKindOwned     = txbuilder.ObjectKindOwned
+	ObjectKindImmutable = txbuilder.ObjectKindImmutable
+	ObjectKindReceiving = txbuilder.ObjectKindReceiving
+	ObjectKindShared    = txbuilder.ObjectKindShared
 )
 
 // InputObject pushes an object input and returns its Argument ID.
 //
 // For owned / immutable / receiving: supply id, version, digest, kind.
 // For shared: supply id, version, mutable, kind="shared" (digest is ignored).
-func (b *Builder) InputObject(id string, version uint64, digest string, kind ObjectKind, mutable bool) (uint64, error) {
+func (b *Builder) InputObject(id ObjectID, version uint64, digest Digest, kind ObjectKind, mutable bool) (uint64, error) {
 	m := map[string]any{
 		"id":      id,
 		"version": version,
@@ -175,72 +353,117 @@ func (b *Builder) InputObject(id string, version uint64, digest string, kind Obj
 	} else {
 		m["digest"] = digest
 	}
-	payload, _ := json.Marshal(m)
-	ptr, size := writeBytes(b.ctx, b.mod, payload)
-	defer freeBytes_(b.ctx, b.mod, ptr, size)
-	res := int64(callFn(b.ctx, b.mod, "input_object", b.ptr, ptr, size)[0])
-	if res < 0 {
-		return 0, fmt.Errorf("input_object failed (code %d)", res)
+	res, err := b.callJSON("input_object", m)
+	return b.argID("input_object", res, err)
+}
+
+// FundsWithdrawal reserves an amount from an address balance.
+type FundsWithdrawal = txbuilder.FundsWithdrawal
+
+// WithdrawalSource names the address balance a FundsWithdrawal draws from.
+type WithdrawalSource = txbuilder.WithdrawalSource
+
+const (
+	WithdrawFromSender  = txbuilder.WithdrawFromSender
+	WithdrawFromSponsor = txbuilder.WithdrawFromSponsor
+)
+
+// InputFundsWithdrawal pushes a withdrawal from an address balance and
+// returns its Argument ID.  Every call adds a new input.
+func (b *Builder) InputFundsWithdrawal(w FundsWithdrawal) (uint64, error) {
+	if err := txbuilder.CheckTypeTag("input_funds_withdrawal", -2, "coin_type", w.CoinType); err != nil {
+		return 0, err
 	}
-	return uint64(res), nil
+	res, err := b.callJSON("input_funds_withdrawal", w)
+	return b.argID("input_funds_withdrawal", res, err)
 }
 
 // ── Pure-value helpers ────────────────────────────────────────────────────────
 
 // PureBool pushes a BCS-encoded bool and returns its Argument ID.
-func (b *Builder) PureBool(v bool) uint64 {
+func (b *Builder) PureBool(v bool) (uint64, error) {
 	var u uint64
 	if v {
 		u = 1
 	}
-	return uint64(callFn(b.ctx, b.mod, "pure_bool", b.ptr, u)[0])
+	return b.callID("pure_bool", u)
 }
 
 // PureU8 pushes a BCS-encoded u8 and returns its Argument ID.
-func (b *Builder) PureU8(v uint8) uint64 {
-	return uint64(callFn(b.ctx, b.mod, "pure_u8", b.ptr, uint64(v))[0])
+func (b *Builder) PureU8(v uint8) (uint64, error) {
+	return b.callID("pure_u8", uint64(v))
 }
 
 // PureU16 pushes a BCS-encoded u16 and returns its Argument ID.
-func (b *Builder) PureU16(v uint16) uint64 {
-	return uint64(callFn(b.ctx, b.mod, "pure_u16", b.ptr, uint64(v))[0])
+func (b *Builder) PureU16(v uint16) (uint64, error) {
+	return b.callID("pure_u16", uint64(v))
 }
 
 // PureU32 pushes a BCS-encoded u32 and returns its Argument ID.
-func (b *Builder) PureU32(v uint32) uint64 {
-	return uint64(callFn(b.ctx, b.mod, "pure_u32", b.ptr, uint64(v))[0])
+func (b *Builder) PureU32(v uint32) (uint64, error) {
+	return b.callID("pure_u32", uint64(v))
 }
 
 // PureU64 pushes a BCS-encoded u64 and returns its Argument ID.
-func (b *Builder) PureU64(v uint64) uint64 {
-	return uint64(callFn(b.ctx, b.mod, "pure_u64", b.ptr, v)[0])
+func (b *Builder) PureU64(v uint64) (uint64, error) {
+	return b.callID("pure_u64", v)
 }
 
 // PureU128 pushes a BCS-encoded u128 (supplied as high/low uint64 halves)
 // and returns its Argument ID.
-func (b *Builder) PureU128(hi, lo uint64) uint64 {
-	return uint64(callFn(b.ctx, b.mod, "pure_u128", b.ptr, lo, hi)[0])
+func (b *Builder) PureU128(hi, lo uint64) (uint64, error) {
+	return b.callID("pure_u128", lo, hi)
 }
 
-// PureAddress pushes a BCS-encoded Sui address (bare 0x-prefixed hex string)
-// and returns its Argument ID.
-func (b *Builder) PureAddress(addr string) (uint64, error) {
-	ptr, size := writeBytes(b.ctx, b.mod, []byte(addr))
-	defer freeBytes_(b.ctx, b.mod, ptr, size)
-	res := int64(callFn(b.ctx, b.mod, "pure_address", b.ptr, ptr, size)[0])
-	if res < 0 {
-		return 0, fmt.Errorf("pure_address: invalid address %q", addr)
-	}
-	return uint64(res), nil
+// PureAddress pushes a BCS-encoded Sui address and returns its Argument ID.
+func (b *Builder) PureAddress(addr Address) (uint64, error) {
+	res, err := b.callBytes("pure_address", []byte(addr.String()))
+	return b.argID("pure_address", res, err)
 }
 
 // PureRawBCS pushes already-BCS-encoded bytes as a pure argument and returns
 // its Argument ID.  Use this when you need a type not covered by the helpers
 // above and you have encoded it yourself.
-func (b *Builder) PureRawBCS(bcsBytes []byte) uint64 {
-	ptr, size := writeBytes(b.ctx, b.mod, bcsBytes)
-	defer freeBytes_(b.ctx, b.mod, ptr, size)
-	return uint64(callFn(b.ctx, b.mod, "pure_raw_bcs", b.ptr, ptr, size)[0])
+func (b *Builder) PureRawBCS(bcsBytes []byte) (uint64, error) {
+	res, err := b.callBytes("pure_raw_bcs", bcsBytes)
+	return b.argID("pure_raw_bcs", res, err)
+}
+
+// PureString pushes a UTF-8 Move String and returns its Argument ID.
+func (b *Builder) PureString(s string) (uint64, error) {
+	return txbuilder.PureString(b, s)
+}
+
+// PureASCIIString pushes a Move ascii::String and returns its Argument ID.
+func (b *Builder) PureASCIIString(s string) (uint64, error) {
+	return txbuilder.PureASCIIString(b, s)
+}
+
+// PureBytes pushes a vector<u8> and returns its Argument ID.
+func (b *Builder) PureBytes(v []byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeBytes(v))
+}
+
+// PureU256 pushes a u256 and returns its Argument ID.
+func (b *Builder) PureU256(v *big.Int) (uint64, error) {
+	return txbuilder.PureU256(b, v)
+}
+
+// PureID pushes an object ID (0x2::object::ID) and returns its Argument ID.
+func (b *Builder) PureID(id ObjectID) (uint64, error) {
+	return txbuilder.PureID(b, id)
+}
+
+// PureOption pushes an Option<T> built from T's BCS encoding (nil for None)
+// and returns its Argument ID.
+func (b *Builder) PureOption(value []byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeOption(value))
+}
+
+// PureVector pushes a vector<T> built from its elements' BCS encodings and
+// returns its Argument ID.  Use txbuilder.PureVec for vectors of primitives.
+func (b *Builder) PureVector(elems [][]byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeVectorOf(elems))
 }
 
 // ── Nested result ─────────────────────────────────────────────────────────────
@@ -248,25 +471,25 @@ func (b *Builder) PureRawBCS(bcsBytes []byte) uint64 {
 // NestedResult returns the Argument ID for the Nth sub-result of a
 // multi-output command (e.g. the Kth coin from SplitCoins).
 // baseID is the value returned by SplitCoins; subIndex is 0-based.
-func (b *Builder) NestedResult(baseID, subIndex uint64) uint64 {
-	return uint64(callFn(b.ctx, b.mod, "nested_result", b.ptr, baseID, subIndex)[0])
+func (b *Builder) NestedResult(baseID, subIndex uint64) (uint64, error) {
+	return b.callID("nested_result", baseID, subIndex)
 }
 
 // ── Commands ──────────────────────────────────────────────────────────────────
 
-// MoveCallArgs describes a single argument to a Move call.
+// MoveCallArg describes a single argument to a Move call.
 // Supply exactly one of ArgID (existing Argument) or PureBCS (raw bytes).
-type MoveCallArg struct {
-	ArgID   *uint64 // reference an existing Argument by ID
-	PureBCS []byte  // pre-encoded BCS bytes
-}
+type MoveCallArg = txbuilder.MoveCallArg
 
 // MoveCall executes an entry or public Move function and returns the result
 // Argument ID.
-func (b *Builder) MoveCall(pkg, module, function string, typeArgs []string, args []MoveCallArg) (uint64, error) {
+func (b *Builder) MoveCall(pkg ObjectID, module, function string, typeArgs []typetag.TypeTag, args []MoveCallArg) (uint64, error) {
+	if err := txbuilder.CheckTypeArgs("command_move_call", -1, typeArgs); err != nil {
+		return 0, err
+	}
 	type callArgJSON struct {
-		ID      *uint64 `json:"id,omitempty"`
-		PureBCS []byte  `json:"pure_bcs,omitempty"`
+		ID      *uint64         `json:"id,omitempty"`
+		PureBCS txbuilder.Bytes `json:"pure_bcs,omitempty"`
 	}
 	jsonArgs := make([]callArgJSON, len(args))
 	for i, a := range args {
@@ -276,118 +499,108 @@ func (b *Builder) MoveCall(pkg, module, function string, typeArgs []string, args
 			jsonArgs[i] = callArgJSON{PureBCS: a.PureBCS}
 		}
 	}
-	payload, _ := json.Marshal(map[string]any{
+	res, err := b.callJSON("command_move_call", map[string]any{
 		"package":   pkg,
 		"module":    module,
 		"function":  function,
 		"type_args": typeArgs,
 		"arguments": jsonArgs,
 	})
-	ptr, size := writeBytes(b.ctx, b.mod, payload)
-	defer freeBytes_(b.ctx, b.mod, ptr, size)
-	res := int64(callFn(b.ctx, b.mod, "command_move_call", b.ptr, ptr, size)[0])
-	if res < 0 {
-		return 0, fmt.Errorf("command_move_call failed (code %d)", res)
-	}
-	return uint64(res), nil
+	return b.argID("command_move_call", res, err)
 }
 
 // ArgID is a convenience constructor for a MoveCallArg that references an
 // existing Argument by ID.
-func ArgID(id uint64) MoveCallArg { return MoveCallArg{ArgID: &id} }
+func ArgID(id uint64) MoveCallArg { return txbuilder.ArgID(id) }
 
 // ArgBCS is a convenience constructor for a MoveCallArg that passes raw
 // pre-encoded BCS bytes.
-func ArgBCS(bcs []byte) MoveCallArg { return MoveCallArg{PureBCS: bcs} }
+func ArgBCS(bcs []byte) MoveCallArg { return txbuilder.ArgBCS(bcs) }
+
+// callIDs writes ids as a C array and calls name(builder, pre…, ptr, len, post…).
+func (b *Builder) callIDs(name string, pre []uint64, ids []uint64, post ...uint64) (int64, error) {
+	if b.ptr == 0 {
+		return 0, txbuilder.ErrConsumed
+	}
+	ptr, size, err := b.in.u64SlicePtr(b.ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	defer b.in.freeBytes(b.ctx, ptr, size)
+	args := append(append(pre, ptr, uint64(len(ids))), post...)
+	res, err := b.call(name, args...)
+	if err != nil {
+		return 0, err
+	}
+	return int64(res[0]), nil
+}
 
 // SplitCoins splits coinArgID into len(amountArgIDs) new coins.
 // amountArgIDs must be Argument IDs returned by PureU64.
 // Returns the base Argument ID; use NestedResult(base, i) to get coin i.
 func (b *Builder) SplitCoins(coinArgID uint64, amountArgIDs []uint64) (uint64, error) {
 	if len(amountArgIDs) == 0 {
-		return 0, fmt.Errorf("SplitCoins: at least one amount required")
-	}
-	amidsPtr, amidsSize := u64SlicePtr(b.ctx, b.mod, amountArgIDs)
-	defer freeBytes_(b.ctx, b.mod, amidsPtr, amidsSize)
-	res := int64(callFn(b.ctx, b.mod, "command_split_coins",
-		b.ptr, coinArgID, amidsPtr, uint64(len(amountArgIDs)),
-	)[0])
-	if res < 0 {
-		return 0, fmt.Errorf("command_split_coins failed (code %d)", res)
+		return 0, errEmptyList("command_split_coins", "amounts", "at least one amount required")
 	}
-	return uint64(res), nil
+	res, err := b.callIDs("command_split_coins", []uint64{coinArgID}, amountArgIDs)
+	return b.argID("command_split_coins", res, err)
 }
 
 // MergeCoins merges sourceArgIDs into targetCoinArgID.
 // Produces no result; the target coin absorbs all sources.
 func (b *Builder) MergeCoins(targetCoinArgID uint64, sourceArgIDs []uint64) error {
 	if len(sourceArgIDs) == 0 {
-		return fmt.Errorf("MergeCoins: at least one source required")
-	}
-	srcsPtr, srcsSize := u64SlicePtr(b.ctx, b.mod, sourceArgIDs)
-	defer freeBytes_(b.ctx, b.mod, srcsPtr, srcsSize)
-	code := int32(callFn(b.ctx, b.mod, "command_merge_coins",
-		b.ptr, targetCoinArgID, srcsPtr, uint64(len(sourceArgIDs)),
-	)[0])
-	if code != 1 {
-		return fmt.Errorf("command_merge_coins failed (code %d)", code)
+		return errEmptyList("command_merge_coins", "sources", "at least one source required")
 	}
-	return nil
+	code, err := b.callIDs("command_merge_coins", []uint64{targetCoinArgID}, sourceArgIDs)
+	return b.status("command_merge_coins", code, err)
 }
 
 // TransferObjects sends objectArgIDs to the address identified by recipientArgID.
 // recipientArgID must be an Argument ID returned by PureAddress.
 func (b *Builder) TransferObjects(objectArgIDs []uint64, recipientArgID uint64) error {
 	if len(objectArgIDs) == 0 {
-		return fmt.Errorf("TransferObjects: at least one object required")
-	}
-	objsPtr, objsSize := u64SlicePtr(b.ctx, b.mod, objectArgIDs)
-	defer freeBytes_(b.ctx, b.mod, objsPtr, objsSize)
-	code := int32(callFn(b.ctx, b.mod, "command_transfer_objects",
-		b.ptr, objsPtr, uint64(len(objectArgIDs)), recipientArgID,
-	)[0])
-	if code != 1 {
-		return fmt.Errorf("command_transfer_objects failed (code %d)", code)
+		return errEmptyList("command_transfer_objects", "objects", "at least one object required")
 	}
-	return nil
+	code, err := b.callIDs("command_transfer_objects", nil, objectArgIDs, recipientArgID)
+	return b.status("command_transfer_objects", code, err)
 }
 
 // MakeMoveVec constructs a Move vector<T> from elemArgIDs.
-// typeTag is the element type as a string (e.g. "0x2::sui::SUI"); pass ""
-// when the type can be inferred from the elements.
+// elemType is the element type; pass nil when the type can be inferred
+// from the elements.
 // Returns the result Argument ID.
-func (b *Builder) MakeMoveVec(typeTag string, elemArgIDs []uint64) (uint64, error) {
-	ttPtr, ttSize := writeBytes(b.ctx, b.mod, []byte(typeTag))
-	defer freeBytes_(b.ctx, b.mod, ttPtr, ttSize)
-
-	elemsPtr, elemsSize := u64SlicePtr(b.ctx, b.mod, elemArgIDs)
-	defer freeBytes_(b.ctx, b.mod, elemsPtr, elemsSize)
-
-	res := int64(callFn(b.ctx, b.mod, "command_make_move_vec",
-		b.ptr, ttPtr, ttSize, elemsPtr, uint64(len(elemArgIDs)),
-	)[0])
-	if res < 0 {
-		return 0, fmt.Errorf("command_make_move_vec failed (code %d)", res)
+func (b *Builder) MakeMoveVec(elemType *typetag.TypeTag, elemArgIDs []uint64) (uint64, error) {
+	if b.ptr == 0 {
+		return 0, txbuilder.ErrConsumed
 	}
-	return uint64(res), nil
+	var typeTag string
+	if elemType != nil {
+		if err := txbuilder.CheckTypeTag("command_make_move_vec", -2, "type_tag", *elemType); err != nil {
+			return 0, err
+		}
+		typeTag = elemType.String()
+	}
+	ttPtr, ttSize, err := b.in.writeBytes(b.ctx, []byte(typeTag))
+	if err != nil {
+		return 0, err
+	}
+	defer b.in.freeBytes(b.ctx, ttPtr, ttSize)
+
+	res, err := b.callIDs("command_make_move_vec", []uint64{ttPtr, ttSize}, elemArgIDs)
+	return b.argID("command_make_move_vec", res, err)
 }
 
 // Publish publishes a new Move package.
 // modules is a slice of compiled module bytecodes.
-// dependencies is a slice of 0x-prefixed package IDs this package depends on.
+// dependencies lists the IDs of the packages this package depends on.
 // Returns the UpgradeCap Argument ID.
-func (b *Builder) Publish(modules [][]byte, dependencies []string) (uint64, error) {
-	payload, _ := json.Marshal(map[string]any{
-		"modules":      modules,
+func (b *Builder) Publish(modules [][]byte, dependencies []ObjectID) (uint64, error) {
+	res, err := b.callJSON("command_publish", map[string]any{
+		"modules":      txbuilder.ModuleBytes(modules),
 		"dependencies": dependencies,
 	})
-	ptr, size := writeBytes(b.ctx, b.mod, payload)
-	defer freeBytes_(b.ctx, b.mod, ptr, size)
-	res := int64(callFn(b.ctx, b.mod, "command_publish", b.ptr, ptr, size)[0])
-	if res < 0 {
-		return 0, fmt.Errorf("command_publish failed (code %d)", res)
-	}
-	return uint64(res), nil
+	return b.argID("command_publish", res, err)
 }
 
 // Upgrade upgrades an existing Move package.
@@ -395,53 +608,86 @@ func (b *Builder) Publish(modules [][]byte, dependencies []string) (uint64, erro
 // packageID is the on-chain ID of the package being upgraded.
 // ticketArgID is the Argument ID of the UpgradeTicket from authorize_upgrade.
 // Returns the UpgradeReceipt Argument ID.
-func (b *Builder) Upgrade(modules [][]byte, dependencies []string, packageID string, ticketArgID uint64) (uint64, error) {
-	payload, _ := json.Marshal(map[string]any{
-		"modules":       modules,
+func (b *Builder) Upgrade(modules [][]byte, dependencies []ObjectID, packageID ObjectID, ticketArgID uint64) (uint64, error) {
+	res, err := b.callJSON("command_upgrade", map[string]any{
+		"modules":       txbuilder.ModuleBytes(modules),
 		"dependencies":  dependencies,
 		"package":       packageID,
 		"ticket_arg_id": ticketArgID,
 	})
-	ptr, size := writeBytes(b.ctx, b.mod, payload)
-	defer freeBytes_(b.ctx, b.mod, ptr, size)
-	res := int64(callFn(b.ctx, b.mod, "command_upgrade", b.ptr, ptr, size)[0])
-	if res < 0 {
-		return 0, fmt.Errorf("command_upgrade failed (code %d)", res)
-	}
-	return uint64(res), nil
+	return b.argID("command_upgrade", res, err)
 }
 
 // ── Finalisation ─────────────────────────────────────────────────────────────
 
+// Clone copies the unbuilt builder inside the same module instance.
+// Argument IDs are the same in both; the copy is built or freed on its own.
+func (b *Builder) Clone() (txbuilder.Builder, error) {
+	return b.clone()
+}
+
+func (b *Builder) clone() (*Builder, error) {
+	res, err := b.call("clone_builder")
+	if err != nil {
+		return nil, err
+	}
+	if res[0] == 0 {
+		return nil, b.in.lastError(b.ctx, "clone_builder", 0)
+	}
+	return &Builder{ctx: b.ctx, in: b.in, ptr: res[0]}, nil
+}
+
+// BuildWith builds a copy with the gas budget and price replaced, and the
+// gas objects too when gas is non-nil.  b is not consumed.
+func (b *Builder) BuildWith(gasBudget, gasPrice uint64, gas []txbuilder.GasObject) ([]byte, error) {
+	c, err := b.clone()
+	if err != nil {
+		return nil, err
+	}
+	payload := map[string]any{"gas_budget": gasBudget, "gas_price": gasPrice}
+	if gas != nil {
+		payload["gas"] = gas
+	}
+	code, err := c.callJSON("set_gas", payload)
+	if err := c.status("set_gas", code, err); err != nil {
+		c.Free()
+		return nil, err
+	}
+	return c.Build()
+}
+
 // Build serialises the transaction to BCS bytes and returns them.
-// The builder is consumed — do NOT call Free() after a successful Build().
-// Returns an error if any required field (sender, gas, budget, price) is
-// missing or if there are no commands.
+// The builder is consumed — do NOT call Free() after Build().
+// A failed build returns a *txbuilder.Error naming the missing field or
+// incomplete object.
 func (b *Builder) Build() ([]byte, error) {
-	res := callFn(b.ctx, b.mod, "build_transaction", b.ptr)
+	res, err := b.call("build_transaction")
 	b.ptr = 0 // builder is consumed regardless
+	if err != nil {
+		return nil, err
+	}
 	resPtr := uint32(res[0])
 	if resPtr == 0 {
-		return nil, fmt.Errorf("build_transaction failed — ensure sender, gas object, gas_budget, gas_price and at least one command are set")
+		return nil, b.in.lastError(b.ctx, "build_transaction", 0)
 	}
 
 	// Read 4-byte LE length prefix.
-	lenBytes, ok := b.mod.Memory().Read(resPtr, 4)
+	mem := b.in.mod.Memory()
+	lenBytes, ok := mem.Read(resPtr, 4)
 	if !ok {
-		callFn(b.ctx, b.mod, "free_bytes", uint64(resPtr), 0)
-		return nil, fmt.Errorf("build_transaction: failed to read length prefix")
+		return nil, b.in.poison("build_transaction", errMemoryAccess("read", uint64(resPtr), 4))
 	}
 	dataLen := binary.LittleEndian.Uint32(lenBytes)
 
 	// Read BCS payload.
-	bcsData, ok := b.mod.Memory().Read(resPtr+4, dataLen)
-	callFn(b.ctx, b.mod, "free_bytes", uint64(resPtr), uint64(dataLen))
+	bcsData, ok := mem.Read(resPtr+4, dataLen)
 	if !ok {
-		return nil, fmt.Errorf("build_transaction: failed to read BCS payload")
+		return nil, b.in.poison("build_transaction", errMemoryAccess("read", uint64(resPtr)+4, int(dataLen)))
 	}
 
-	// Return a copy — WASM memory was freed above.
+	// Copy before freeing — the view aliases WASM memory.
 	out := make([]byte, len(bcsData))
 	copy(out, bcsData)
+	b.in.call(b.ctx, "free_bytes", uint64(resPtr), uint64(dataLen)) //nolint:errcheck // recorded in in.trap
 	return out, nil
 }
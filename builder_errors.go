@@ -0,0 +1,118 @@
+// builder_errors.go
+//
+// Errors reported by the WASM builder bridge.  Errors from the builder
+// itself are *txbuilder.Error values.
+
+package gosuisdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// ErrPoisoned is returned by every call into a module instance that trapped
+// earlier.  The instance must be discarded.
+var ErrPoisoned = errors.New("txbuilder: module instance poisoned by an earlier trap")
+
+// TrapKind classifies a guest trap.
+type TrapKind string
+
+const (
+	TrapUnreachable TrapKind = "unreachable" // Rust panic or abort
+	TrapMemory      TrapKind = "memory"      // out-of-bounds access or failed host read/write
+	TrapExit        TrapKind = "exit"        // proc_exit called by the guest
+	TrapOther       TrapKind = "other"
+)
+
+// TrapError reports a failed call into the transaction_builder module.
+// The module instance is poisoned once a TrapError is returned.
+type TrapError struct {
+	Func string // exported function being called
+	Kind TrapKind
+	Err  error // underlying wazero error
+}
+
+func (e *TrapError) Error() string {
+	return fmt.Sprintf("txbuilder %s trapped (%s): %v", e.Func, e.Kind, e.Err)
+}
+
+func (e *TrapError) Unwrap() error { return e.Err }
+
+func newTrapError(fn string, err error) *TrapError {
+	kind := TrapOther
+	var exit *sys.ExitError
+	traps := wasmTraps()
+	switch {
+	case errors.As(err, &exit):
+		kind = TrapExit
+	case traps.unreachable != nil && errors.Is(err, traps.unreachable):
+		kind = TrapUnreachable
+	case errors.Is(err, errHostMemory),
+		traps.outOfBounds != nil && errors.Is(err, traps.outOfBounds):
+		kind = TrapMemory
+	}
+	return &TrapError{Func: fn, Kind: kind, Err: err}
+}
+
+// errHostMemory marks a failed host-side read or write of guest memory.
+var errHostMemory = errors.New("guest memory access out of range")
+
+func errMemoryAccess(op string, ptr uint64, n int) error {
+	return fmt.Errorf("%w: %s of %d bytes at 0x%x", errHostMemory, op, n, ptr)
+}
+
+// wazero reports traps by wrapping sentinel errors of an internal package.
+// They are captured once, by running a probe module that executes
+// `unreachable` and an out-of-bounds load, so traps are matched with
+// errors.Is instead of by message text.
+var (
+	wasmTrapsOnce sync.Once
+	wasmTrapsVal  struct{ unreachable, outOfBounds error }
+)
+
+// probeWASM exports "u" (unreachable) and "m" (i32.load from a memory of
+// zero pages).
+var probeWASM = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x04, 0x01, 0x60, 0x00, 0x00, // type: () -> ()
+	0x03, 0x03, 0x02, 0x00, 0x00, // functions u, m
+	0x05, 0x03, 0x01, 0x00, 0x00, // memory: min 0 pages
+	0x07, 0x09, 0x02, 0x01, 'u', 0x00, 0x00, 0x01, 'm', 0x00, 0x01,
+	0x0a, 0x0e, 0x02,
+	0x03, 0x00, 0x00, 0x0b, // u: unreachable
+	0x08, 0x00, 0x41, 0x00, 0x28, 0x02, 0x00, 0x1a, 0x0b, // m: i32.load 0; drop
+}
+
+func wasmTraps() struct{ unreachable, outOfBounds error } {
+	wasmTrapsOnce.Do(func() {
+		ctx := context.Background()
+		rt := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfigInterpreter())
+		defer rt.Close(ctx) //nolint:errcheck
+		mod, err := rt.Instantiate(ctx, probeWASM)
+		if err != nil {
+			return
+		}
+		sentinel := func(name string) error {
+			_, err := mod.ExportedFunction(name).Call(ctx)
+			for err != nil && errors.Unwrap(err) != nil {
+				err = errors.Unwrap(err)
+			}
+			return err
+		}
+		wasmTrapsVal.unreachable = sentinel("u")
+		wasmTrapsVal.outOfBounds = sentinel("m")
+	})
+	return wasmTrapsVal
+}
+
+// errEmptyList mirrors the FFI's empty_list error for checks done in Go
+// before a call is made.
+func errEmptyList(fn, field, msg string) error {
+	return txbuilder.NewError(fn, -1, txbuilder.KindEmptyList, msg).WithField(field)
+}
@@ -0,0 +1,84 @@
+package gosuisdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+func probeModule(t *testing.T, cfg wazero.RuntimeConfig) api.Module {
+	t.Helper()
+	ctx := context.Background()
+	rt := wazero.NewRuntimeWithConfig(ctx, cfg)
+	t.Cleanup(func() { rt.Close(ctx) })
+	mod, err := rt.Instantiate(ctx, probeWASM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mod
+}
+
+func TestTrapKind(t *testing.T) {
+	engines := map[string]wazero.RuntimeConfig{
+		"default":     wazero.NewRuntimeConfig(),
+		"interpreter": wazero.NewRuntimeConfigInterpreter(),
+	}
+	for name, cfg := range engines {
+		t.Run(name, func(t *testing.T) {
+			mod := probeModule(t, cfg)
+			for fn, want := range map[string]TrapKind{"u": TrapUnreachable, "m": TrapMemory} {
+				_, err := mod.ExportedFunction(fn).Call(context.Background())
+				if err == nil {
+					t.Fatalf("%s did not trap", fn)
+				}
+				if got := newTrapError(fn, err).Kind; got != want {
+					t.Errorf("%s: kind %s, want %s (%v)", fn, got, want, err)
+				}
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		err  error
+		want TrapKind
+	}{
+		{errMemoryAccess("read", 0x10, 4), TrapMemory},
+		{sys.NewExitError(1), TrapExit},
+		{errors.New("memory is fine, this is something else"), TrapOther},
+	} {
+		if got := newTrapError("f", tt.err).Kind; got != tt.want {
+			t.Errorf("%v: kind %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+// A trap through one builder poisons every builder and backend created in
+// the same module.
+func TestPoisonSharedPerModule(t *testing.T) {
+	ctx := context.Background()
+	mod := probeModule(t, wazero.NewRuntimeConfigInterpreter())
+
+	if _, err := instanceFor(mod).call(ctx, "u"); err == nil {
+		t.Fatal("probe did not trap")
+	}
+	if _, err := NewBuilder(ctx, mod); !errors.Is(err, ErrPoisoned) {
+		t.Errorf("NewBuilder after trap: %v, want ErrPoisoned", err)
+	}
+	if _, err := WASMBackend(ctx, mod).NewBuilder(); !errors.Is(err, ErrPoisoned) {
+		t.Errorf("WASMBackend.NewBuilder after trap: %v, want ErrPoisoned", err)
+	}
+
+	// Closed modules leave the registry when another one registers.
+	mod.Close(ctx)
+	instanceFor(probeModule(t, wazero.NewRuntimeConfigInterpreter()))
+	instances.Lock()
+	_, kept := instances.m[mod]
+	instances.Unlock()
+	if kept {
+		t.Error("closed module still registered")
+	}
+}
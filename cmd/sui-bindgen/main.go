@@ -0,0 +1,117 @@
+// Command sui-bindgen writes typed Go bindings for an on-chain Move package.
+//
+// It reads the package with GetPackage, fetches the datatypes of other
+// packages its structs use with GetDatatype, and writes the output of
+// bindgen.Generate.  Typical use is a go:generate line next to the code
+// calling the package:
+//
+//	//go:generate go run github.com/pictorx/go-sui-sdk/cmd/sui-bindgen -network testnet -id 0x… -pkg pool -o pool_gen.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	gosuisdk "github.com/pictorx/go-sui-sdk"
+	"github.com/pictorx/go-sui-sdk/bindgen"
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var endpoints = map[string]string{
+	"mainnet":  "fullnode.mainnet.sui.io:443",
+	"testnet":  "fullnode.testnet.sui.io:443",
+	"devnet":   "fullnode.devnet.sui.io:443",
+	"localnet": "127.0.0.1:9000",
+}
+
+func main() {
+	id := flag.String("id", "", "package storage ID (required)")
+	network := flag.String("network", "testnet", "mainnet, testnet, devnet or localnet")
+	endpoint := flag.String("endpoint", "", "gRPC endpoint, overrides -network")
+	pkgName := flag.String("pkg", "", "Go package name (default $GOPACKAGE)")
+	out := flag.String("o", "", "output file (default stdout)")
+	timeout := flag.Duration("timeout", 60*time.Second, "RPC timeout")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("sui-bindgen: ")
+	if *id == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *pkgName == "" {
+		*pkgName = os.Getenv("GOPACKAGE")
+	}
+	if *pkgName == "" {
+		log.Fatal("-pkg is required outside go generate")
+	}
+
+	target := *endpoint
+	if target != "" {
+		*network = target
+	} else {
+		var ok bool
+		if target, ok = endpoints[*network]; !ok {
+			log.Fatalf("unknown network %q", *network)
+		}
+	}
+	creds := credentials.NewClientTLSFromCert(nil, "")
+	if strings.HasPrefix(target, "127.0.0.1:") || strings.HasPrefix(target, "localhost:") {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		log.Fatalf("dial %s: %v", target, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	pkgID, err := gosuisdk.ParseObjectID(*id)
+	if err != nil {
+		log.Fatal(err)
+	}
+	resp, err := gosuisdk.GetPackage(conn, pkgID, ctx)
+	if err != nil {
+		log.Fatalf("get package %s: %v", *id, err)
+	}
+	src, err := bindgen.Generate(resp.GetPackage(), bindgen.Options{
+		Package: *pkgName,
+		Network: *network,
+		Fetch: func(typeName string) (*pb.DatatypeDescriptor, error) {
+			parts := strings.SplitN(typeName, "::", 3)
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("malformed type name %q", typeName)
+			}
+			pkg, err := gosuisdk.ParseObjectID(parts[0])
+			if err != nil {
+				return nil, err
+			}
+			resp, err := gosuisdk.GetDatatype(conn, pkg, parts[1], parts[2], ctx)
+			if err != nil {
+				return nil, err
+			}
+			return resp.GetDatatype(), nil
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
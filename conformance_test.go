@@ -0,0 +1,474 @@
+package gosuisdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+// The conformance suite runs each scenario through every backend compiled
+// into the test binary and compares the transaction bytes with the golden
+// vector in testdata/conformance and with the other backends.  The WASM
+// backend is skipped when no module is embedded and the CGo backend unless
+// built with -tags txbuilder_cgo, so the pure-Go checks always run; CI runs
+// all three and requires them with CONFORMANCE_REQUIRE.
+//
+// The vectors are written by the Rust TransactionBuilder, which builds the
+// same scenarios in transaction/tests/conformance.rs; keep the two tables in
+// step.  funds_withdrawal.hex and inline_bcs.hex are labelled as derived by
+// hand until they are regenerated from Rust.  After an intended change to
+// the encoding, regenerate the vectors with
+//
+//	UPDATE_CONFORMANCE=1 cargo test --test conformance
+//
+// in transaction/.
+
+var conformanceBackends = []struct {
+	kind BackendKind
+	open func(t *testing.T) Backend
+}{
+	{BackendGo, func(*testing.T) Backend { return GoBackend() }},
+	{BackendWASM, func(t *testing.T) Backend {
+		if _, err := EmbeddedWASM(); err != nil {
+			skipBackend(t, BackendWASM, err)
+		}
+		pool, err := NewWASMPool(context.Background(), WASMPoolConfig{Size: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { pool.Close(context.Background()) })
+		return pool
+	}},
+	{BackendCGo, func(t *testing.T) Backend {
+		backend, err := CGoBackend()
+		if err != nil {
+			skipBackend(t, BackendCGo, err)
+		}
+		return backend
+	}},
+}
+
+// skipBackend skips a backend that is not available, unless it is listed in
+// CONFORMANCE_REQUIRE (comma separated backend kinds), which CI sets so that
+// a missing backend fails instead of passing vacuously.
+func skipBackend(t *testing.T, kind BackendKind, err error) {
+	t.Helper()
+	for _, required := range strings.Split(os.Getenv("CONFORMANCE_REQUIRE"), ",") {
+		if strings.TrimSpace(required) == string(kind) {
+			t.Fatal(err)
+		}
+	}
+	t.Skip(err)
+}
+
+// ── Fixtures ──────────────────────────────────────────────────────────────────
+
+var (
+	confSender    = mustAddress("0xa11ce")
+	confRecipient = mustAddress("0xb0b")
+	confPackage   = mustObjectID("0xc0ffee")
+	confGas       = mustObjectID("0x9a5")
+	confCoinA     = mustObjectID("0xc01a")
+	confCoinB     = mustObjectID("0xc01b")
+	confShared    = mustObjectID("0x5ba4ed")
+	confCap       = mustObjectID("0xca9")
+	confDigest    = Digest{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+		17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32}
+	confModules = [][]byte{{0xa1, 0x1c, 0xeb, 0x0b, 1}, {0xa1, 0x1c, 0xeb, 0x0b, 2}}
+	confDeps    = []ObjectID{mustObjectID("0x1"), mustObjectID("0x2")}
+	u64Type     = typetag.MustParse("u64")
+)
+
+const (
+	confBudget = 5_000_000
+	confPrice  = 1_000
+)
+
+func mustAddress(s string) Address {
+	a, err := ParseAddress(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func mustObjectID(s string) ObjectID {
+	id, err := ParseObjectID(s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// confBuild drives a builder for one scenario, keeping the first error.
+type confBuild struct {
+	b   TxBuilder
+	err error
+}
+
+func (c *confBuild) id(id uint64, err error) uint64 {
+	c.do(err)
+	return id
+}
+
+func (c *confBuild) do(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// pay sets the fixture sender, budget, price and gas coin.
+func (c *confBuild) pay(budget uint64) {
+	c.do(c.b.SetConfig(confSender, budget, confPrice))
+	c.do(c.b.AddGasObject(confGas, 7, confDigest))
+}
+
+// ── Scenarios ─────────────────────────────────────────────────────────────────
+
+type conformanceScenario struct {
+	name  string
+	build func(c *confBuild)
+	// contains are hex encodings that must appear in the transaction,
+	// checked independently of the golden vector.
+	contains []string
+}
+
+var conformanceScenarios = []conformanceScenario{
+	{name: "split_transfer", build: func(c *confBuild) {
+		b := c.b
+		gas := c.id(b.GasArgument())
+		split := c.id(b.SplitCoins(gas, []uint64{c.id(b.PureU64(100)), c.id(b.PureU64(200))}))
+		first := c.id(b.NestedResult(split, 0))
+		second := c.id(b.NestedResult(split, 1))
+		c.do(b.TransferObjects([]uint64{first, second}, c.id(b.PureAddress(confRecipient))))
+	}},
+	{name: "pure_values", build: func(c *confBuild) {
+		b := c.b
+		args := []uint64{
+			c.id(b.PureBool(true)),
+			c.id(b.PureU8(0x08)),
+			c.id(b.PureU16(0x1616)),
+			c.id(b.PureU32(0x32323232)),
+			c.id(b.PureU64(0x6464646464646464)),
+			c.id(b.PureU128(1, 2)),
+			c.id(b.PureU256(new(big.Int).Lsh(big.NewInt(1), 255))),
+			c.id(b.PureAddress(confRecipient)),
+			c.id(b.PureID(confShared)),
+			c.id(b.PureString("héllo")),
+			c.id(b.PureASCIIString("hello")),
+			c.id(b.PureBytes([]byte{0xde, 0xad})),
+			c.id(b.PureRawBCS([]byte{0x2a})),
+			c.id(b.PureOption([]byte{9, 0, 0, 0, 0, 0, 0, 0})),
+			c.id(b.PureOption(nil)),
+			c.id(b.PureVector([][]byte{{1}, {2}, {3}})),
+		}
+		callArgs := make([]txbuilder.MoveCallArg, len(args))
+		for i, id := range args {
+			callArgs[i] = txbuilder.ArgID(id)
+		}
+		c.id(b.MoveCall(confPackage, "pures", "all", nil, callArgs))
+	}, contains: []string{
+		"10" + "0200000000000000" + "0100000000000000", // u128 hi=1 lo=2: low half first
+		"20" + strings.Repeat("00", 31) + "80",         // u256 2^255
+		"07" + "06" + "68c3a96c6c6f",                   // string "héllo"
+		"09" + "01" + "0900000000000000",               // Some(9u64)
+		"01" + "00",                                    // None
+	}},
+	{name: "objects", build: func(c *confBuild) {
+		b := c.b
+		owned := c.id(b.InputObject(confCoinA, 3, confDigest, txbuilder.ObjectKindOwned, true))
+		imm := c.id(b.InputObject(confCoinB, 4, confDigest, txbuilder.ObjectKindImmutable, false))
+		recv := c.id(b.InputObject(confCap, 5, confDigest, txbuilder.ObjectKindReceiving, false))
+		shared := c.id(b.InputObject(confShared, 6, Digest{}, txbuilder.ObjectKindShared, false))
+		c.id(b.MoveCall(confPackage, "objects", "take",
+			[]typetag.TypeTag{typetag.SUI, typetag.MustParse("0x2::coin::Coin<0x0000000000000000000000000000000000000000000000000000000000000002::sui::SUI>")},
+			[]txbuilder.MoveCallArg{txbuilder.ArgID(owned), txbuilder.ArgID(imm), txbuilder.ArgID(recv), txbuilder.ArgID(shared)}))
+	}},
+	{name: "object_reuse", build: func(c *confBuild) {
+		b := c.b
+		// The same shared object taken immutably then mutably is one
+		// mutable input; the same owned object twice is one input.
+		first := c.id(b.InputObject(confShared, 6, Digest{}, txbuilder.ObjectKindShared, false))
+		second := c.id(b.InputObject(confShared, 6, Digest{}, txbuilder.ObjectKindShared, true))
+		coin := c.id(b.InputObject(confCoinA, 3, confDigest, txbuilder.ObjectKindOwned, true))
+		again := c.id(b.InputObject(confCoinA, 3, confDigest, txbuilder.ObjectKindOwned, true))
+		c.id(b.MoveCall(confPackage, "objects", "read", nil, []txbuilder.MoveCallArg{txbuilder.ArgID(first), txbuilder.ArgID(coin)}))
+		c.id(b.MoveCall(confPackage, "objects", "write", nil, []txbuilder.MoveCallArg{txbuilder.ArgID(second), txbuilder.ArgID(again)}))
+	}},
+	{name: "merge_coins", build: func(c *confBuild) {
+		b := c.b
+		a := c.id(b.InputObject(confCoinA, 3, confDigest, txbuilder.ObjectKindOwned, true))
+		bb := c.id(b.InputObject(confCoinB, 4, confDigest, txbuilder.ObjectKindOwned, true))
+		c.do(b.MergeCoins(c.id(b.GasArgument()), []uint64{a, bb}))
+	}},
+	{name: "make_move_vec", build: func(c *confBuild) {
+		b := c.b
+		typed := c.id(b.MakeMoveVec(&u64Type, []uint64{c.id(b.PureU64(1)), c.id(b.PureU64(2))}))
+		a := c.id(b.InputObject(confCoinA, 3, confDigest, txbuilder.ObjectKindOwned, true))
+		bb := c.id(b.InputObject(confCoinB, 4, confDigest, txbuilder.ObjectKindOwned, true))
+		untyped := c.id(b.MakeMoveVec(nil, []uint64{a, bb}))
+		c.id(b.MoveCall(confPackage, "vec", "take", nil, []txbuilder.MoveCallArg{txbuilder.ArgID(typed), txbuilder.ArgID(untyped)}))
+	}},
+	{name: "move_call_results", build: func(c *confBuild) {
+		b := c.b
+		// Raw BCS arguments mixed with Argument IDs, and results fed to
+		// later commands whole and by index.
+		pair := c.id(b.MoveCall(confPackage, "calls", "pair", []typetag.TypeTag{typetag.SUI},
+			[]txbuilder.MoveCallArg{txbuilder.ArgBCS([]byte{5, 0, 0, 0, 0, 0, 0, 0}), txbuilder.ArgID(c.id(b.GasArgument()))}))
+		one := c.id(b.MoveCall(confPackage, "calls", "one", nil, []txbuilder.MoveCallArg{txbuilder.ArgID(pair)}))
+		c.id(b.MoveCall(confPackage, "calls", "use", nil, []txbuilder.MoveCallArg{
+			txbuilder.ArgID(c.id(b.NestedResult(pair, 1))), txbuilder.ArgID(one), txbuilder.ArgBCS([]byte{1}),
+		}))
+	}},
+	{name: "inline_bcs", build: func(c *confBuild) {
+		b := c.b
+		// Inline BCS arguments travel to the FFI as JSON number arrays: a
+		// value equal to an existing pure input shares it, a vector<u8> with
+		// a two-byte length prefix, and bytes with the high bit set.
+		long := append([]byte{0xc8, 0x01}, make([]byte, 200)...)
+		for i := range 200 {
+			long[2+i] = byte(i)
+		}
+		c.id(b.MoveCall(confPackage, "bcs", "take", nil, []txbuilder.MoveCallArg{
+			txbuilder.ArgID(c.id(b.PureU64(7))),
+			txbuilder.ArgBCS([]byte{7, 0, 0, 0, 0, 0, 0, 0}),
+			txbuilder.ArgBCS(long),
+			txbuilder.ArgBCS([]byte{0xff, 0x80, 0x7f}),
+		}))
+	}, contains: []string{
+		// three Pure inputs, then MoveCall arguments Input(0) twice,
+		// Input(1), Input(2)
+		"0003" + "0008" + "0700000000000000" + "00ca01c801000102",
+		"0003ff807f",
+		"04" + "010000" + "010000" + "010100" + "010200",
+	}},
+	{name: "publish", build: func(c *confBuild) {
+		b := c.b
+		capArg := c.id(b.Publish(confModules, confDeps))
+		c.do(b.TransferObjects([]uint64{capArg}, c.id(b.PureAddress(confSender))))
+	}},
+	{name: "upgrade", build: func(c *confBuild) {
+		b := c.b
+		capArg := c.id(b.InputObject(confCap, 5, confDigest, txbuilder.ObjectKindOwned, true))
+		ticket := c.id(b.MoveCall(mustObjectID("0x2"), "package", "authorize_upgrade", nil, []txbuilder.MoveCallArg{
+			txbuilder.ArgID(capArg), txbuilder.ArgID(c.id(b.PureU8(0))), txbuilder.ArgID(c.id(b.PureBytes(confDigest[:]))),
+		}))
+		receipt := c.id(b.Upgrade(confModules, confDeps, confPackage, ticket))
+		c.id(b.MoveCall(mustObjectID("0x2"), "package", "commit_upgrade", nil, []txbuilder.MoveCallArg{
+			txbuilder.ArgID(capArg), txbuilder.ArgID(receipt),
+		}))
+	}},
+	{name: "funds_withdrawal", build: func(c *confBuild) {
+		b := c.b
+		// Identical withdrawals are separate inputs, unlike pure values.
+		w := txbuilder.FundsWithdrawal{Amount: 5, CoinType: typetag.SUI}
+		first := c.id(b.InputFundsWithdrawal(w))
+		second := c.id(b.InputFundsWithdrawal(w))
+		w.Source = txbuilder.WithdrawFromSponsor
+		sponsor := c.id(b.InputFundsWithdrawal(w))
+		c.id(b.MoveCall(confPackage, "funds", "take", []typetag.TypeTag{typetag.SUI}, []txbuilder.MoveCallArg{
+			txbuilder.ArgID(first), txbuilder.ArgID(second), txbuilder.ArgID(sponsor),
+		}))
+	}, contains: []string{
+		// CallArg::FundsWithdrawal, MaxAmountU64(5), Balance(0x2::sui::SUI), Sender
+		"02" + "01" + "0500000000000000" + "00" + "07" + strings.Repeat("00", 31) + "02" +
+			"03737569" + "03535549" + "00" + "00",
+	}},
+}
+
+// conformanceFailures must fail the same way on every backend.
+var conformanceFailures = []struct {
+	name  string
+	build func(c *confBuild)
+	kind  txbuilder.ErrorKind
+}{
+	{"missing_sender", func(c *confBuild) {
+		c.do(c.b.AddGasObject(confGas, 7, confDigest))
+	}, txbuilder.KindMissingSender},
+	{"missing_gas_objects", func(c *confBuild) {
+		c.do(c.b.SetConfig(confSender, confBudget, confPrice))
+	}, txbuilder.KindMissingGasObjects},
+	{"split_no_amounts", func(c *confBuild) {
+		c.pay(confBudget)
+		c.id(c.b.SplitCoins(c.id(c.b.GasArgument()), nil))
+	}, txbuilder.KindEmptyList},
+	{"unknown_argument", func(c *confBuild) {
+		c.pay(confBudget)
+		c.do(c.b.TransferObjects([]uint64{999}, c.id(c.b.PureAddress(confRecipient))))
+	}, txbuilder.KindUnknownArgument},
+	{"invalid_type_tag", func(c *confBuild) {
+		c.pay(confBudget)
+		c.id(c.b.MakeMoveVec(&typetag.TypeTag{Kind: typetag.Vector}, []uint64{c.id(c.b.PureU64(1))}))
+	}, txbuilder.KindInvalidTypeTag},
+	{"unknown_object_kind", func(c *confBuild) {
+		c.pay(confBudget)
+		c.id(c.b.InputObject(confCoinA, 3, confDigest, "borrowed", true))
+	}, txbuilder.KindUnknownObjectKind},
+	{"unknown_withdrawal_source", func(c *confBuild) {
+		c.pay(confBudget)
+		c.id(c.b.InputFundsWithdrawal(txbuilder.FundsWithdrawal{Amount: 1, CoinType: typetag.SUI, Source: "treasury"}))
+	}, txbuilder.KindUnknownSource},
+}
+
+// ── Runner ────────────────────────────────────────────────────────────────────
+
+func runScenario(t *testing.T, backend Backend, budget uint64, build func(c *confBuild)) (TxBuilder, error) {
+	t.Helper()
+	b, err := backend.NewBuilder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &confBuild{b: b}
+	if budget > 0 {
+		c.pay(budget)
+	}
+	build(c)
+	return b, c.err
+}
+
+func goldenPath(name string) string {
+	return filepath.Join("testdata", "conformance", name+".hex")
+}
+
+// readGolden reads a vector stored as hex, 32 bytes per line.  Lines
+// starting with # are comments.
+func readGolden(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(goldenPath(name))
+	if err != nil {
+		t.Fatalf("%v (written by transaction/tests/conformance.rs)", err)
+	}
+	var sb strings.Builder
+	for line := range strings.Lines(string(data)) {
+		if !strings.HasPrefix(line, "#") {
+			sb.WriteString(strings.TrimSpace(line))
+		}
+	}
+	want, err := hex.DecodeString(sb.String())
+	if err != nil {
+		t.Fatalf("%s: %v", goldenPath(name), err)
+	}
+	return want
+}
+
+func TestConformance(t *testing.T) {
+	// outputs[scenario][backend] collects bytes for the cross-backend check.
+	outputs := map[string]map[BackendKind][]byte{}
+
+	for _, be := range conformanceBackends {
+		t.Run(string(be.kind), func(t *testing.T) {
+			backend := be.open(t)
+			for _, sc := range conformanceScenarios {
+				t.Run(sc.name, func(t *testing.T) {
+					b, err := runScenario(t, backend, confBudget, sc.build)
+					if err != nil {
+						b.Free()
+						t.Fatal(err)
+					}
+					got, err := b.Build()
+					if err != nil {
+						t.Fatalf("Build: %v", err)
+					}
+					if outputs[sc.name] == nil {
+						outputs[sc.name] = map[BackendKind][]byte{}
+					}
+					outputs[sc.name][be.kind] = got
+
+					if want := readGolden(t, sc.name); !bytes.Equal(got, want) {
+						t.Errorf("differs from golden\n got %x\nwant %x", got, want)
+					}
+					for _, h := range sc.contains {
+						if !bytes.Contains(got, mustDecodeHex(t, h)) {
+							t.Errorf("missing encoding %s", h)
+						}
+					}
+				})
+			}
+		})
+	}
+
+	for _, sc := range conformanceScenarios {
+		want := outputs[sc.name][BackendGo]
+		for kind, got := range outputs[sc.name] {
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s: %s backend differs from go\n%s %x\n go %x", sc.name, kind, kind, got, want)
+			}
+		}
+	}
+}
+
+// TestConformanceBuildWith checks that rebuilding a clone with the fixture
+// budget matches the golden vector built with it from the start.
+func TestConformanceBuildWith(t *testing.T) {
+	for _, be := range conformanceBackends {
+		t.Run(string(be.kind), func(t *testing.T) {
+			backend := be.open(t)
+			for _, sc := range conformanceScenarios {
+				t.Run(sc.name, func(t *testing.T) {
+					b, err := runScenario(t, backend, 1, sc.build)
+					defer b.Free()
+					if err != nil {
+						t.Fatal(err)
+					}
+					cl, ok := b.(txbuilder.Cloner)
+					if !ok {
+						skipBackend(t, be.kind, fmt.Errorf("%T does not implement txbuilder.Cloner", b))
+					}
+					got, err := cl.BuildWith(confBudget, confPrice, nil)
+					if err != nil {
+						t.Fatalf("BuildWith: %v", err)
+					}
+					if want := readGolden(t, sc.name); !bytes.Equal(got, want) {
+						t.Errorf("differs from golden\n got %x\nwant %x", got, want)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestConformanceFailures(t *testing.T) {
+	for _, be := range conformanceBackends {
+		t.Run(string(be.kind), func(t *testing.T) {
+			backend := be.open(t)
+			for _, fc := range conformanceFailures {
+				t.Run(fc.name, func(t *testing.T) {
+					b, err := runScenario(t, backend, 0, fc.build)
+					if err == nil {
+						_, err = b.Build()
+					} else {
+						b.Free()
+					}
+					var e *txbuilder.Error
+					switch {
+					case err == nil:
+						t.Fatalf("succeeded, want %s", fc.kind)
+					case !errors.As(err, &e):
+						t.Fatalf("error %v (%T) is not a *txbuilder.Error", err, err)
+					case e.Kind != fc.kind:
+						t.Errorf("kind %s, want %s (%v)", e.Kind, fc.kind, err)
+					}
+				})
+			}
+		})
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,247 @@
+// consolidate.go
+//
+// Coin consolidation ("dust sweeping").
+//
+// Pages through every Coin<T> an owner holds and merges them into a single
+// coin, splitting the work into as many transactions as the per-transaction
+// input limits require.  Batches are executed one after another; the object
+// references of the target coin and the gas coin are refreshed from each
+// transaction's effects before the next batch is built.
+//
+// For SUI the coins are merged into the gas coin itself; for any other coin
+// type they are merged into the largest coin of that type and gas is paid
+// from GasCoin.
+
+package gosuisdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/block-vision/sui-go-sdk/signer"
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
+	"google.golang.org/grpc"
+)
+
+// Per-transaction limits the helpers plan batches with, taken from
+// txbuilder.DefaultLimits.  The node's actual limits are enforced by
+// LimitedBackend.
+const (
+	// MaxCommands is the maximum number of commands per transaction.
+	MaxCommands = txbuilder.DefaultMaxCommands
+	// MaxInputObjects is the maximum number of object inputs per transaction.
+	MaxInputObjects = txbuilder.DefaultMaxInputObjects
+	// MaxArguments is the maximum number of arguments per command.
+	MaxArguments = txbuilder.DefaultMaxArguments
+	// MaxGasObjects is the maximum number of coins in a gas payment.
+	MaxGasObjects = txbuilder.DefaultMaxGasPaymentObjects
+)
+
+// ConsolidateCoins merges all of Owner's coins of CoinType.
+type ConsolidateCoins struct {
+	Owner     Address
+	CoinType  typetag.TypeTag // e.g. typetag.SUI
+	Gasbudget uint64          // budget used to simulate each batch
+	Gasprice  uint64
+
+	// GasCoin pays for gas when CoinType is not SUI.  Ignored for SUI, where
+	// the largest coin being consolidated is used as the gas coin.
+	GasCoin *pb.Object
+
+	// BatchSize caps the number of coins merged per transaction.
+	// Zero means as many as the input limit allows.
+	BatchSize int
+}
+
+// ConsolidateResult reports the outcome of a consolidation run.
+type ConsolidateResult struct {
+	// Reclaimed is the number of coin objects merged away (deleted).
+	Reclaimed int
+	// StorageRebate is the sum of the storage rebates of all batches.
+	StorageRebate uint64
+	// GasUsed is the sum of computation and storage costs of all batches.
+	GasUsed uint64
+	// Digests lists the executed transactions in order.
+	Digests []Digest
+	// Coin is the reference of the coin everything was merged into.
+	Coin *pb.ObjectReference
+}
+
+// listAllCoins pages through all of owner's coins of cointype.
+func listAllCoins(conn *grpc.ClientConn, owner Address, coinType typetag.TypeTag, ctx context.Context) ([]*pb.Object, error) {
+	pageSize := uint32(1000)
+	var coins []*pb.Object
+	var token []byte
+	for {
+		resp, err := ListOwnedCoins(conn, owner, coinType, &pageSize, token, ctx)
+		if err != nil {
+			return nil, err
+		}
+		coins = append(coins, resp.GetObjects()...)
+		token = resp.GetNextPageToken()
+		if len(token) == 0 {
+			return coins, nil
+		}
+	}
+}
+
+// objectRef returns the reference of a listed object.
+func objectRef(obj *pb.Object) *pb.ObjectReference {
+	id, version, digest := obj.GetObjectId(), obj.GetVersion(), obj.GetDigest()
+	return &pb.ObjectReference{ObjectId: &id, Version: &version, Digest: &digest}
+}
+
+// updateRef refreshes ref from the output state recorded in effects.
+func updateRef(ref *pb.ObjectReference, effects *pb.TransactionEffects) {
+	for _, changed := range effects.GetChangedObjects() {
+		if changed.GetObjectId() != ref.GetObjectId() {
+			continue
+		}
+		version, digest := changed.GetOutputVersion(), changed.GetOutputDigest()
+		ref.Version, ref.Digest = &version, &digest
+		return
+	}
+}
+
+func (c *ConsolidateCoins) batchSize() int {
+	// One input slot is taken by the target coin when it is not the gas coin.
+	limit := MaxInputObjects - 1
+	if c.BatchSize > 0 && c.BatchSize < limit {
+		return c.BatchSize
+	}
+	return limit
+}
+
+// buildBatch builds a transaction merging sources into target.  When gas is
+// nil the target coin is the gas coin.
+func (c *ConsolidateCoins) buildBatch(backend Backend, budget uint64, gas, target *pb.ObjectReference, sources []*pb.ObjectReference) ([]byte, error) {
+	b, err := backend.NewBuilder()
+	if err != nil {
+		return nil, err
+	}
+	built := false
+	defer func() {
+		if !built {
+			b.Free()
+		}
+	}()
+
+	if err := b.SetConfig(c.Owner, budget, c.Gasprice); err != nil {
+		return nil, err
+	}
+
+	var targetArg uint64
+	if gas == nil {
+		if err := addGasObject(b, target); err != nil {
+			return nil, err
+		}
+		arg, err := b.GasArgument()
+		if err != nil {
+			return nil, err
+		}
+		targetArg = arg
+	} else {
+		if err := addGasObject(b, gas); err != nil {
+			return nil, err
+		}
+		arg, err := inputOwned(b, target)
+		if err != nil {
+			return nil, err
+		}
+		targetArg = arg
+	}
+
+	srcArgs := make([]uint64, 0, len(sources))
+	for _, src := range sources {
+		arg, err := inputOwned(b, src)
+		if err != nil {
+			return nil, err
+		}
+		srcArgs = append(srcArgs, arg)
+	}
+
+	for start := 0; start < len(srcArgs); start += MaxArguments {
+		end := min(start+MaxArguments, len(srcArgs))
+		if err := b.MergeCoins(targetArg, srcArgs[start:end]); err != nil {
+			return nil, err
+		}
+	}
+
+	built = true
+	return b.Build()
+}
+
+// SignExecuteTx consolidates the coins, executing one transaction per batch.
+// On error the result accumulated so far is returned alongside it.
+func (c *ConsolidateCoins) SignExecuteTx(conn *grpc.ClientConn, backend Backend, account *signer.Signer, ctx context.Context) (*ConsolidateResult, error) {
+	result := &ConsolidateResult{}
+
+	coins, err := listAllCoins(conn, c.Owner, c.CoinType, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(coins) < 2 {
+		if len(coins) == 1 {
+			result.Coin = objectRef(coins[0])
+		}
+		return result, nil
+	}
+
+	// Merge into the largest coin so the fewest balance updates are needed.
+	sort.Slice(coins, func(i, j int) bool {
+		return coins[i].GetBalance() > coins[j].GetBalance()
+	})
+	target := objectRef(coins[0])
+	result.Coin = target
+
+	var gas *pb.ObjectReference
+	if !isSuiType(c.CoinType) {
+		if c.GasCoin == nil {
+			return nil, fmt.Errorf("ConsolidateCoins: GasCoin is required for coin type %s", c.CoinType)
+		}
+		gas = objectRef(c.GasCoin)
+	}
+
+	sources := make([]*pb.ObjectReference, 0, len(coins)-1)
+	for _, coin := range coins[1:] {
+		sources = append(sources, objectRef(coin))
+	}
+
+	size := c.batchSize()
+	for start := 0; start < len(sources); start += size {
+		batch := sources[start:min(start+size, len(sources))]
+
+		resp, err := estimateSignExecute(conn, account, c.Gasbudget, func(budget uint64) ([]byte, error) {
+			return c.buildBatch(backend, budget, gas, target, batch)
+		}, []string{"digest", "effects"}, ctx)
+		if err != nil {
+			return result, err
+		}
+
+		effects := resp.GetTransaction().GetEffects()
+		if !effects.GetStatus().GetSuccess() {
+			return result, fmt.Errorf("consolidation batch %s failed: %s",
+				resp.GetTransaction().GetDigest(), effects.GetStatus().GetError())
+		}
+
+		digest, err := ParseDigest(resp.GetTransaction().GetDigest())
+		if err != nil {
+			return result, err
+		}
+		gasUsed := effects.GetGasUsed()
+		result.Reclaimed += len(batch)
+		result.StorageRebate += gasUsed.GetStorageRebate()
+		result.GasUsed += gasUsed.GetComputationCost() + gasUsed.GetStorageCost()
+		result.Digests = append(result.Digests, digest)
+
+		updateRef(target, effects)
+		if gas != nil {
+			updateRef(gas, effects)
+		}
+	}
+
+	return result, nil
+}
@@ -4,20 +4,20 @@ go 1.25.4
 
 require (
 	github.com/block-vision/sui-go-sdk v1.1.4
+	github.com/btcsuite/btcutil v1.0.2
 	github.com/golang/protobuf v1.5.4
 	github.com/tetratelabs/wazero v1.11.0
+	golang.org/x/crypto v0.46.0
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20260128011058-8636f8732409
 	google.golang.org/grpc v1.78.0
 	google.golang.org/protobuf v1.36.11
 )
 
 require (
-	github.com/btcsuite/btcutil v1.0.2 // indirect
 	github.com/cosmos/go-bip39 v1.0.0 // indirect
 	github.com/tidwall/gjson v1.14.4 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.0 // indirect
-	golang.org/x/crypto v0.46.0 // indirect
 	golang.org/x/net v0.47.0 // indirect
 	golang.org/x/sys v0.39.0 // indirect
 	golang.org/x/text v0.32.0 // indirect
@@ -0,0 +1,43 @@
+package gobuilder
+
+import (
+	"github.com/pictorx/go-sui-sdk/bcs"
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+// encoder is a bcs.Encoder extended with the transaction types gobuilder
+// writes.  Primitives and type tags go through the bcs and typetag packages.
+type encoder struct {
+	bcs.Encoder
+}
+
+// digest is serialised as a length-prefixed 32-byte vector.
+func (e *encoder) digest(d [32]byte) { e.WriteBytes(d[:]) }
+
+func (e *encoder) objectRef(r objectRef) {
+	e.WriteFixed(r.id[:])
+	e.WriteU64(r.version)
+	e.digest(r.digest)
+}
+
+func (e *encoder) argument(a argument) {
+	e.WriteU8(uint8(a.kind))
+	switch a.kind {
+	case argInput, argResult:
+		e.WriteU16(a.index)
+	case argNestedResult:
+		e.WriteU16(a.index)
+		e.WriteU16(a.sub)
+	}
+}
+
+func (e *encoder) arguments(args []argument) {
+	e.WriteULEB128(uint64(len(args)))
+	for _, a := range args {
+		e.argument(a)
+	}
+}
+
+func (e *encoder) typeTag(t typetag.TypeTag) error {
+	return t.MarshalBCS(&e.Encoder)
+}
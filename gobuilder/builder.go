@@ -0,0 +1,912 @@
+// Package gobuilder is a pure-Go implementation of txbuilder.Builder.
+//
+// It mirrors the Rust TransactionBuilder behind the WASM and CGo backends:
+// every input, command and nested result gets the next Argument ID, pure
+// inputs are deduplicated by their bytes, object inputs by object ID, and
+// Build emits the same BCS TransactionData byte for byte.  No Rust artefact
+// is needed at build or run time.
+//
+// Usage:
+//
+//	b := gobuilder.NewBuilder()
+//	b.SetConfig(sender, 10_000_000, 1_000)
+//	b.AddGasObject(id, version, digest)
+//	gasID, _  := b.GasArgument()
+//	amtID, _  := b.PureU64(100_000_000)
+//	baseID, _ := b.SplitCoins(gasID, []uint64{amtID})
+//	coinID, _ := b.NestedResult(baseID, 0)
+//	recID, _  := b.PureAddress(txbuilder.MustParseAddress("0xabc…"))
+//	b.TransferObjects([]uint64{coinID}, recID)
+//	bcsBytes, err := b.Build()
+package gobuilder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+// ── Argument table ────────────────────────────────────────────────────────────
+
+type slotKind uint8
+
+const (
+	slotGas slotKind = iota
+	slotPure
+	slotObject
+	slotCommand
+	slotNested // alias created by NestedResult
+	slotWithdrawal
+)
+
+// slot is one entry of the argument table, indexed by Argument ID.
+type slot struct {
+	kind    slotKind
+	pure    []byte
+	object  *objectInput
+	command *command
+
+	withdrawal *fundsWithdrawal
+
+	// slotNested: sub-result sub of the argument base.
+	base, sub uint64
+}
+
+// argKind is the BCS variant index of a resolved Argument.
+type argKind uint8
+
+const (
+	argGasCoin      argKind = 0
+	argInput        argKind = 1
+	argResult       argKind = 2
+	argNestedResult argKind = 3
+)
+
+type argument struct {
+	kind       argKind
+	index, sub uint16
+}
+
+// ── Object inputs ─────────────────────────────────────────────────────────────
+
+type objectArgKind uint8
+
+const (
+	objectImmOrOwned objectArgKind = iota + 1
+	objectShared
+	objectReceiving
+)
+
+// objectInput collects what is known about an object input.  Unset fields
+// are nil; repeated uses of the same object are merged field by field.
+type objectInput struct {
+	id      [32]byte
+	kind    objectArgKind
+	version *uint64
+	digest  *[32]byte
+	mutable *bool
+}
+
+// merge folds a later use of the same object into o.
+func (o *objectInput) merge(n *objectInput) {
+	switch {
+	case n.mutable != nil && o.mutable == nil:
+		o.mutable = n.mutable
+	case n.mutable != nil && *n.mutable && !*o.mutable:
+		o.mutable = n.mutable
+	}
+	if o.kind == 0 {
+		o.kind = n.kind
+	}
+	if o.version == nil {
+		o.version = n.version
+	}
+	if o.digest == nil {
+		o.digest = n.digest
+	}
+}
+
+type objectRef struct {
+	id      [32]byte
+	version uint64
+	digest  [32]byte
+}
+
+// encodeInput writes o as a CallArg::Object.
+func (o *objectInput) encodeInput(e *encoder) error {
+	switch {
+	case (o.kind == objectImmOrOwned || o.kind == 0 && o.mutable == nil) && o.version != nil && o.digest != nil:
+		e.WriteU8(1) // CallArg::Object
+		e.WriteU8(0) // ObjectArg::ImmOrOwnedObject
+		e.objectRef(objectRef{o.id, *o.version, *o.digest})
+	case o.kind == objectReceiving && o.version != nil && o.digest != nil:
+		e.WriteU8(1)
+		e.WriteU8(2) // ObjectArg::Receiving
+		e.objectRef(objectRef{o.id, *o.version, *o.digest})
+	case (o.kind == objectShared || o.kind == 0 && o.digest == nil) && o.version != nil && o.mutable != nil:
+		e.WriteU8(1)
+		e.WriteU8(1) // ObjectArg::SharedObject
+		e.WriteFixed(o.id[:])
+		e.WriteU64(*o.version)
+		e.WriteBool(*o.mutable)
+	default:
+		err := buildErr(txbuilder.KindIncompleteObject, "Conversion error due to input issue: Input object %s is incomplete", formatAddress(o.id))
+		err.ObjectID = formatAddress(o.id)
+		return err
+	}
+	return nil
+}
+
+// ── Commands ──────────────────────────────────────────────────────────────────
+
+type commandKind uint8
+
+// Command variant indices in BCS.
+const (
+	cmdMoveCall        commandKind = 0
+	cmdTransferObjects commandKind = 1
+	cmdSplitCoins      commandKind = 2
+	cmdMergeCoins      commandKind = 3
+	cmdPublish         commandKind = 4
+	cmdMakeMoveVec     commandKind = 5
+	cmdUpgrade         commandKind = 6
+)
+
+// command holds unresolved Argument IDs; they are turned into BCS
+// arguments by Build.
+type command struct {
+	kind commandKind
+
+	// MoveCall
+	pkg      [32]byte
+	module   string
+	function string
+	typeArgs []typetag.TypeTag
+
+	// coin is the SplitCoins/MergeCoins coin, the TransferObjects recipient
+	// or the Upgrade ticket.
+	coin uint64
+	// args are the MoveCall arguments, split amounts, merge sources,
+	// transferred objects or vector elements.
+	args []uint64
+
+	// MakeMoveVec
+	elemType *typetag.TypeTag
+
+	// Publish / Upgrade (pkg is the upgraded package)
+	modules [][]byte
+	deps    [][32]byte
+}
+
+// ── Builder ───────────────────────────────────────────────────────────────────
+
+// Errors carry the function name, code, kind and field the Rust FFI reports
+// for the same input, so callers see identical errors from every backend.
+
+func fail(fn string, code int64, kind txbuilder.ErrorKind, format string, a ...any) *txbuilder.Error {
+	return txbuilder.NewError(fn, code, kind, fmt.Sprintf(format, a...))
+}
+
+func buildErr(kind txbuilder.ErrorKind, format string, a ...any) *txbuilder.Error {
+	return fail("build_transaction", 0, kind, format, a...)
+}
+
+func inputErr(format string, a ...any) *txbuilder.Error {
+	return buildErr(txbuilder.KindInput, "Conversion error due to input issue: "+format, a...)
+}
+
+// checkArg rejects an Argument ID this builder never returned.
+func (b *Builder) checkArg(fn, field string, id uint64) error {
+	if id >= uint64(len(b.slots)) {
+		return fail(fn, -4, txbuilder.KindUnknownArgument, "argument %d does not exist", id).WithField(field)
+	}
+	return nil
+}
+
+// checkArgs is checkArg for a list field; the error names the index.
+func (b *Builder) checkArgs(fn, field string, ids ...uint64) error {
+	for i, id := range ids {
+		if id >= uint64(len(b.slots)) {
+			return fail(fn, -4, txbuilder.KindUnknownArgument, "argument %d does not exist", id).
+				WithField(field).WithIndex(i)
+		}
+	}
+	return nil
+}
+
+// Builder builds a programmable transaction in Go memory.
+// It is NOT safe for concurrent use.
+type Builder struct {
+	slots   []slot
+	gasSlot *uint64
+	pures   map[string]uint64   // pure bytes → Argument ID
+	objects map[[32]byte]uint64 // object ID → Argument ID
+
+	sender    *[32]byte
+	gasBudget *uint64
+	gasPrice  *uint64
+	gas       []objectRef
+
+	consumed bool
+}
+
+var _ txbuilder.Cloner = (*Builder)(nil)
+
+// NewBuilder returns an empty builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		pures:   make(map[string]uint64),
+		objects: make(map[[32]byte]uint64),
+	}
+}
+
+// Free releases a builder that was NOT consumed by Build().  Nothing is
+// held outside the Go heap, so this only marks the builder as consumed.
+func (b *Builder) Free() {
+	b.consumed = true
+}
+
+// Clone returns an independent copy of an unbuilt builder.  Argument IDs
+// are the same in both.
+func (b *Builder) Clone() (txbuilder.Builder, error) {
+	return b.clone()
+}
+
+func (b *Builder) clone() (*Builder, error) {
+	if b.consumed {
+		return nil, txbuilder.ErrConsumed
+	}
+	c := &Builder{
+		slots:     slices.Clone(b.slots),
+		gasSlot:   b.gasSlot,
+		pures:     maps.Clone(b.pures),
+		objects:   maps.Clone(b.objects),
+		sender:    b.sender,
+		gasBudget: b.gasBudget,
+		gasPrice:  b.gasPrice,
+		gas:       slices.Clone(b.gas),
+	}
+	// Later uses of an object are merged into its input in place.
+	for i, s := range c.slots {
+		if s.object != nil {
+			o := *s.object
+			c.slots[i].object = &o
+		}
+	}
+	return c, nil
+}
+
+// BuildWith builds a copy with the gas budget and price replaced, and the
+// gas objects too when gas is non-nil.  b is not consumed.
+func (b *Builder) BuildWith(gasBudget, gasPrice uint64, gas []txbuilder.GasObject) ([]byte, error) {
+	c, err := b.clone()
+	if err != nil {
+		return nil, err
+	}
+	c.gasBudget, c.gasPrice = &gasBudget, &gasPrice
+	if gas != nil {
+		c.gas = make([]objectRef, len(gas))
+		for i, g := range gas {
+			c.gas[i] = objectRef{id: g.ID, version: g.Version, digest: g.Digest}
+		}
+	}
+	return c.Build()
+}
+
+func (b *Builder) push(s slot) uint64 {
+	b.slots = append(b.slots, s)
+	return uint64(len(b.slots) - 1)
+}
+
+// ── Configuration ─────────────────────────────────────────────────────────────
+
+// SetConfig sets the sender address, gas budget, and gas price.
+func (b *Builder) SetConfig(sender txbuilder.Address, gasBudget, gasPrice uint64) error {
+	addr := [32]byte(sender)
+	b.sender = &addr
+	b.gasBudget = &gasBudget
+	b.gasPrice = &gasPrice
+	return nil
+}
+
+// ── Gas objects ───────────────────────────────────────────────────────────────
+
+// AddGasObject adds an owned gas coin identified by its object ID, version,
+// and digest.
+func (b *Builder) AddGasObject(id txbuilder.ObjectID, version uint64, digest txbuilder.Digest) error {
+	b.gas = append(b.gas, objectRef{id: id, version: version, digest: digest})
+	return nil
+}
+
+// GasArgument returns the Argument ID for the transaction's gas coin.
+// Idempotent — always returns the same ID within one builder.
+func (b *Builder) GasArgument() (uint64, error) {
+	if b.gasSlot == nil {
+		id := b.push(slot{kind: slotGas})
+		b.gasSlot = &id
+	}
+	return *b.gasSlot, nil
+}
+
+// ── Object inputs ─────────────────────────────────────────────────────────────
+
+// InputObject pushes an object input and returns its Argument ID.
+//
+// For owned / immutable / receiving: supply id, version, digest, kind.
+// For shared: supply id, version, mutable, kind="shared" (digest is ignored).
+// Passing the same object again returns the existing ID and merges the
+// new information into it.
+func (b *Builder) InputObject(id txbuilder.ObjectID, version uint64, digest txbuilder.Digest, kind txbuilder.ObjectKind, mutable bool) (uint64, error) {
+	oid := [32]byte(id)
+	obj := &objectInput{id: oid, version: &version}
+	switch kind {
+	case txbuilder.ObjectKindOwned, txbuilder.ObjectKindImmutable, txbuilder.ObjectKindReceiving:
+		d := [32]byte(digest)
+		obj.digest = &d
+		obj.kind = objectImmOrOwned
+		if kind == txbuilder.ObjectKindReceiving {
+			obj.kind = objectReceiving
+		}
+	case txbuilder.ObjectKindShared:
+		obj.kind = objectShared
+		obj.mutable = &mutable
+	default:
+		e := fail("input_object", -3, txbuilder.KindUnknownObjectKind, "unknown object kind `%s`", kind).WithField("kind")
+		e.ObjectID = formatAddress(oid)
+		return 0, e
+	}
+
+	if existing, ok := b.objects[oid]; ok {
+		b.slots[existing].object.merge(obj)
+		return existing, nil
+	}
+	argID := b.push(slot{kind: slotObject, object: obj})
+	b.objects[oid] = argID
+	return argID, nil
+}
+
+// ── Funds withdrawals ─────────────────────────────────────────────────────────
+
+// WithdrawFrom variants.
+const (
+	withdrawFromSender  uint8 = 0
+	withdrawFromSponsor uint8 = 1
+)
+
+// fundsWithdrawal is a CallArg::FundsWithdrawal.
+type fundsWithdrawal struct {
+	amount   uint64
+	coinType typetag.TypeTag
+	from     uint8 // WithdrawFrom variant
+}
+
+// encodeInput writes w as a CallArg::FundsWithdrawal.  Only a capped
+// reservation of a Balance<T> is produced:
+//
+//	FundsWithdrawal { reservation: MaxAmountU64(amount),
+//	                  type_arg: Balance(T), withdraw_from: Sender | Sponsor }
+func (w *fundsWithdrawal) encodeInput(e *encoder) error {
+	e.WriteU8(2) // CallArg::FundsWithdrawal
+	e.WriteU8(1) // Reservation::MaxAmountU64
+	e.WriteU64(w.amount)
+	e.WriteU8(0) // WithdrawalTypeArg::Balance
+	if err := e.typeTag(w.coinType); err != nil {
+		return err
+	}
+	e.WriteU8(w.from) // WithdrawFrom
+	return nil
+}
+
+// InputFundsWithdrawal pushes a withdrawal from an address balance and
+// returns its Argument ID.  Every call adds a new input.
+func (b *Builder) InputFundsWithdrawal(w txbuilder.FundsWithdrawal) (uint64, error) {
+	const fn = "input_funds_withdrawal"
+	if err := txbuilder.CheckTypeTag(fn, -2, "coin_type", w.CoinType); err != nil {
+		return 0, err
+	}
+	in := &fundsWithdrawal{amount: w.Amount, coinType: w.CoinType}
+	switch w.Source {
+	case "", txbuilder.WithdrawFromSender:
+		in.from = withdrawFromSender
+	case txbuilder.WithdrawFromSponsor:
+		in.from = withdrawFromSponsor
+	default:
+		return 0, fail(fn, -3, txbuilder.KindUnknownSource, "unknown withdrawal source `%s`", w.Source).
+			WithField("source")
+	}
+	return b.push(slot{kind: slotWithdrawal, withdrawal: in}), nil
+}
+
+// ── Pure-value helpers ────────────────────────────────────────────────────────
+
+// PureBool pushes a BCS-encoded bool and returns its Argument ID.
+func (b *Builder) PureBool(v bool) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodePrimitive(v))
+}
+
+// PureU8 pushes a BCS-encoded u8 and returns its Argument ID.
+func (b *Builder) PureU8(v uint8) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodePrimitive(v))
+}
+
+// PureU16 pushes a BCS-encoded u16 and returns its Argument ID.
+func (b *Builder) PureU16(v uint16) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodePrimitive(v))
+}
+
+// PureU32 pushes a BCS-encoded u32 and returns its Argument ID.
+func (b *Builder) PureU32(v uint32) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodePrimitive(v))
+}
+
+// PureU64 pushes a BCS-encoded u64 and returns its Argument ID.
+func (b *Builder) PureU64(v uint64) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodePrimitive(v))
+}
+
+// PureU128 pushes a BCS-encoded u128 (supplied as high/low uint64 halves)
+// and returns its Argument ID.
+func (b *Builder) PureU128(hi, lo uint64) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeU128(hi, lo))
+}
+
+// PureAddress pushes a BCS-encoded Sui address and returns its Argument ID.
+func (b *Builder) PureAddress(addr txbuilder.Address) (uint64, error) {
+	return b.PureRawBCS(addr[:])
+}
+
+// PureRawBCS pushes already-BCS-encoded bytes as a pure argument and returns
+// its Argument ID.  Identical bytes share one input.
+func (b *Builder) PureRawBCS(bcsBytes []byte) (uint64, error) {
+	return b.pure(bcsBytes), nil
+}
+
+// PureString pushes a UTF-8 Move String and returns its Argument ID.
+func (b *Builder) PureString(s string) (uint64, error) {
+	return txbuilder.PureString(b, s)
+}
+
+// PureASCIIString pushes a Move ascii::String and returns its Argument ID.
+func (b *Builder) PureASCIIString(s string) (uint64, error) {
+	return txbuilder.PureASCIIString(b, s)
+}
+
+// PureBytes pushes a vector<u8> and returns its Argument ID.
+func (b *Builder) PureBytes(v []byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeBytes(v))
+}
+
+// PureU256 pushes a u256 and returns its Argument ID.
+func (b *Builder) PureU256(v *big.Int) (uint64, error) {
+	return txbuilder.PureU256(b, v)
+}
+
+// PureID pushes an object ID (0x2::object::ID) and returns its Argument ID.
+func (b *Builder) PureID(id txbuilder.ObjectID) (uint64, error) {
+	return txbuilder.PureID(b, id)
+}
+
+// PureOption pushes an Option<T> built from T's BCS encoding (nil for None)
+// and returns its Argument ID.
+func (b *Builder) PureOption(value []byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeOption(value))
+}
+
+// PureVector pushes a vector<T> built from its elements' BCS encodings and
+// returns its Argument ID.  Use txbuilder.PureVec for vectors of primitives.
+func (b *Builder) PureVector(elems [][]byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeVectorOf(elems))
+}
+
+func (b *Builder) pure(bcsBytes []byte) uint64 {
+	if id, ok := b.pures[string(bcsBytes)]; ok {
+		return id
+	}
+	id := b.push(slot{kind: slotPure, pure: append([]byte(nil), bcsBytes...)})
+	b.pures[string(bcsBytes)] = id
+	return id
+}
+
+// ── Nested result ─────────────────────────────────────────────────────────────
+
+// NestedResult returns the Argument ID for the Nth sub-result of a
+// multi-output command (e.g. the Kth coin from SplitCoins).
+// baseID is the value returned by SplitCoins; subIndex is 0-based.
+func (b *Builder) NestedResult(baseID, subIndex uint64) (uint64, error) {
+	if err := b.checkArg("nested_result", "base_id", baseID); err != nil {
+		return 0, err
+	}
+	return b.push(slot{kind: slotNested, base: baseID, sub: subIndex}), nil
+}
+
+// ── Commands ──────────────────────────────────────────────────────────────────
+
+func (b *Builder) command(c *command) uint64 {
+	return b.push(slot{kind: slotCommand, command: c})
+}
+
+// MoveCall executes an entry or public Move function and returns the result
+// Argument ID.
+func (b *Builder) MoveCall(pkg txbuilder.ObjectID, module, function string, typeArgs []typetag.TypeTag, args []txbuilder.MoveCallArg) (uint64, error) {
+	const fn = "command_move_call"
+	if err := txbuilder.CheckTypeArgs(fn, -1, typeArgs); err != nil {
+		return 0, err
+	}
+	for i, a := range args {
+		if a.ArgID == nil && len(a.PureBCS) == 0 {
+			return 0, fail(fn, -1, txbuilder.KindJSON, "argument needs an ArgID or PureBCS bytes").
+				WithField("arguments").WithIndex(i)
+		}
+	}
+	if !typetag.IsValidIdentifier(module) {
+		return 0, fail(fn, -2, txbuilder.KindInvalidIdentifier, "invalid identifier `%s`", module).WithField("module")
+	}
+	if !typetag.IsValidIdentifier(function) {
+		return 0, fail(fn, -3, txbuilder.KindInvalidIdentifier, "invalid identifier `%s`", function).WithField("function")
+	}
+	for i, a := range args {
+		if a.ArgID != nil && *a.ArgID >= uint64(len(b.slots)) {
+			return 0, fail(fn, -4, txbuilder.KindUnknownArgument, "argument %d does not exist", *a.ArgID).
+				WithField("arguments").WithIndex(i)
+		}
+	}
+
+	ids := make([]uint64, len(args))
+	for i, a := range args {
+		if a.ArgID != nil {
+			ids[i] = *a.ArgID
+		} else {
+			ids[i] = b.pure(a.PureBCS)
+		}
+	}
+	return b.command(&command{
+		kind:     cmdMoveCall,
+		pkg:      pkg,
+		module:   module,
+		function: function,
+		typeArgs: slices.Clone(typeArgs),
+		args:     ids,
+	}), nil
+}
+
+// SplitCoins splits coinArgID into len(amountArgIDs) new coins.
+// Returns the base Argument ID; use NestedResult(base, i) to get coin i.
+func (b *Builder) SplitCoins(coinArgID uint64, amountArgIDs []uint64) (uint64, error) {
+	if len(amountArgIDs) == 0 {
+		return 0, fail("command_split_coins", -1, txbuilder.KindEmptyList, "at least one amount required").WithField("amounts")
+	}
+	if err := b.checkArg("command_split_coins", "coin", coinArgID); err != nil {
+		return 0, err
+	}
+	if err := b.checkArgs("command_split_coins", "amounts", amountArgIDs...); err != nil {
+		return 0, err
+	}
+	return b.command(&command{
+		kind: cmdSplitCoins,
+		coin: coinArgID,
+		args: append([]uint64(nil), amountArgIDs...),
+	}), nil
+}
+
+// MergeCoins merges sourceArgIDs into targetCoinArgID.
+func (b *Builder) MergeCoins(targetCoinArgID uint64, sourceArgIDs []uint64) error {
+	if len(sourceArgIDs) == 0 {
+		return fail("command_merge_coins", -1, txbuilder.KindEmptyList, "at least one source required").WithField("sources")
+	}
+	if err := b.checkArg("command_merge_coins", "coin", targetCoinArgID); err != nil {
+		return err
+	}
+	if err := b.checkArgs("command_merge_coins", "sources", sourceArgIDs...); err != nil {
+		return err
+	}
+	b.command(&command{
+		kind: cmdMergeCoins,
+		coin: targetCoinArgID,
+		args: append([]uint64(nil), sourceArgIDs...),
+	})
+	return nil
+}
+
+// TransferObjects sends objectArgIDs to the address identified by recipientArgID.
+func (b *Builder) TransferObjects(objectArgIDs []uint64, recipientArgID uint64) error {
+	if len(objectArgIDs) == 0 {
+		return fail("command_transfer_objects", -1, txbuilder.KindEmptyList, "at least one object required").WithField("objects")
+	}
+	if err := b.checkArgs("command_transfer_objects", "objects", objectArgIDs...); err != nil {
+		return err
+	}
+	if err := b.checkArg("command_transfer_objects", "recipient", recipientArgID); err != nil {
+		return err
+	}
+	b.command(&command{
+		kind: cmdTransferObjects,
+		coin: recipientArgID,
+		args: append([]uint64(nil), objectArgIDs...),
+	})
+	return nil
+}
+
+// MakeMoveVec constructs a Move vector<T> from elemArgIDs.
+// Pass a nil elemType when the type can be inferred from the elements.
+func (b *Builder) MakeMoveVec(elemType *typetag.TypeTag, elemArgIDs []uint64) (uint64, error) {
+	var tag *typetag.TypeTag
+	if elemType != nil {
+		if err := txbuilder.CheckTypeTag("command_make_move_vec", -2, "type_tag", *elemType); err != nil {
+			return 0, err
+		}
+		t := *elemType
+		tag = &t
+	}
+	if err := b.checkArgs("command_make_move_vec", "elements", elemArgIDs...); err != nil {
+		return 0, err
+	}
+	return b.command(&command{
+		kind:     cmdMakeMoveVec,
+		elemType: tag,
+		args:     append([]uint64(nil), elemArgIDs...),
+	}), nil
+}
+
+// Publish publishes a new Move package and returns the UpgradeCap Argument ID.
+func (b *Builder) Publish(modules [][]byte, dependencies []txbuilder.ObjectID) (uint64, error) {
+	return b.command(&command{
+		kind:    cmdPublish,
+		modules: copyModules(modules),
+		deps:    copyDeps(dependencies),
+	}), nil
+}
+
+// Upgrade upgrades packageID using the UpgradeTicket at ticketArgID and
+// returns the UpgradeReceipt Argument ID.
+func (b *Builder) Upgrade(modules [][]byte, dependencies []txbuilder.ObjectID, packageID txbuilder.ObjectID, ticketArgID uint64) (uint64, error) {
+	if err := b.checkArg("command_upgrade", "ticket_arg_id", ticketArgID); err != nil {
+		return 0, err
+	}
+	return b.command(&command{
+		kind:    cmdUpgrade,
+		modules: copyModules(modules),
+		deps:    copyDeps(dependencies),
+		pkg:     packageID,
+		coin:    ticketArgID,
+	}), nil
+}
+
+func copyDeps(deps []txbuilder.ObjectID) [][32]byte {
+	out := make([][32]byte, len(deps))
+	for i, d := range deps {
+		out[i] = d
+	}
+	return out
+}
+
+func copyModules(modules [][]byte) [][]byte {
+	out := make([][]byte, len(modules))
+	for i, m := range modules {
+		out[i] = append([]byte(nil), m...)
+	}
+	return out
+}
+
+// ── Finalisation ─────────────────────────────────────────────────────────────
+
+// Build serialises the transaction to BCS bytes and returns them.
+// The builder is consumed whether or not Build succeeds.
+func (b *Builder) Build() ([]byte, error) {
+	if b.consumed {
+		return nil, txbuilder.ErrConsumed
+	}
+	b.consumed = true
+
+	switch {
+	case b.sender == nil:
+		return nil, buildErr(txbuilder.KindMissingSender, "Missing sender")
+	case len(b.gas) == 0:
+		return nil, buildErr(txbuilder.KindMissingGasObjects, "Missing gas objects")
+	case b.gasBudget == nil:
+		return nil, buildErr(txbuilder.KindMissingGasBudget, "Missing gas budget")
+	case b.gasPrice == nil:
+		return nil, buildErr(txbuilder.KindMissingGasPrice, "Missing gas price")
+	}
+
+	resolved := make([]*argument, len(b.slots))
+
+	// Inputs, in Argument ID order.
+	inputs := &encoder{}
+	var nInputs int
+	for id, s := range b.slots {
+		switch s.kind {
+		case slotGas:
+			resolved[id] = &argument{kind: argGasCoin}
+		case slotPure:
+			inputs.WriteU8(0) // CallArg::Pure
+			inputs.WriteBytes(s.pure)
+		case slotObject:
+			if err := s.object.encodeInput(inputs); err != nil {
+				return nil, err
+			}
+		case slotWithdrawal:
+			if err := s.withdrawal.encodeInput(inputs); err != nil {
+				return nil, buildErr(txbuilder.KindSerialization, "%v", err)
+			}
+		default:
+			continue
+		}
+		if s.kind != slotGas {
+			resolved[id] = &argument{kind: argInput, index: uint16(nInputs)}
+			nInputs++
+		}
+	}
+
+	// Commands, in Argument ID order.
+	commands := &encoder{}
+	var nCommands int
+	for id, s := range b.slots {
+		if s.kind != slotCommand {
+			continue
+		}
+		if err := b.encodeCommand(commands, s.command, resolved); err != nil {
+			return nil, err
+		}
+		resolved[id] = &argument{kind: argResult, index: uint16(nCommands)}
+		nCommands++
+	}
+
+	e := &encoder{}
+	e.WriteU8(0) // TransactionData::V1
+	e.WriteU8(0) // TransactionKind::ProgrammableTransaction
+	e.WriteULEB128(uint64(nInputs))
+	e.WriteFixed(inputs.Bytes())
+	e.WriteULEB128(uint64(nCommands))
+	e.WriteFixed(commands.Bytes())
+	e.WriteFixed(b.sender[:])
+
+	// GasData; the sender pays.
+	e.WriteULEB128(uint64(len(b.gas)))
+	for _, g := range b.gas {
+		e.objectRef(g)
+	}
+	e.WriteFixed(b.sender[:])
+	e.WriteU64(*b.gasPrice)
+	e.WriteU64(*b.gasBudget)
+
+	e.WriteU8(0) // TransactionExpiration::None
+	return e.Bytes(), nil
+}
+
+// resolve maps an Argument ID to its BCS argument, following NestedResult
+// aliases.
+func (b *Builder) resolve(id uint64, resolved []*argument) (argument, error) {
+	var sub *uint64
+	visited := make(map[uint64]bool)
+	for {
+		if id >= uint64(len(b.slots)) {
+			return argument{}, inputErr("argument %d does not exist", id)
+		}
+		if visited[id] {
+			return argument{}, inputErr("argument %d is a cyclic nested result", id)
+		}
+		visited[id] = true
+
+		if s := b.slots[id]; s.kind == slotNested {
+			id, sub = s.base, &s.sub
+			continue
+		}
+		if resolved[id] == nil {
+			return argument{}, inputErr("argument %d is used before the command producing it", id)
+		}
+		arg := *resolved[id]
+		if sub == nil {
+			return arg, nil
+		}
+		if arg.kind != argResult {
+			return argument{}, inputErr("unable to create nested argument")
+		}
+		return argument{kind: argNestedResult, index: arg.index, sub: uint16(*sub)}, nil
+	}
+}
+
+func (b *Builder) resolveMany(ids []uint64, resolved []*argument) ([]argument, error) {
+	out := make([]argument, len(ids))
+	for i, id := range ids {
+		var err error
+		if out[i], err = b.resolve(id, resolved); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (b *Builder) encodeCommand(e *encoder, c *command, resolved []*argument) error {
+	e.WriteU8(uint8(c.kind))
+	switch c.kind {
+	case cmdMoveCall:
+		args, err := b.resolveMany(c.args, resolved)
+		if err != nil {
+			return err
+		}
+		e.WriteFixed(c.pkg[:])
+		e.WriteString(c.module)
+		e.WriteString(c.function)
+		e.WriteULEB128(uint64(len(c.typeArgs)))
+		for _, t := range c.typeArgs {
+			if err := e.typeTag(t); err != nil {
+				return buildErr(txbuilder.KindSerialization, "%v", err)
+			}
+		}
+		e.arguments(args)
+
+	case cmdTransferObjects:
+		objects, err := b.resolveMany(c.args, resolved)
+		if err != nil {
+			return err
+		}
+		recipient, err := b.resolve(c.coin, resolved)
+		if err != nil {
+			return err
+		}
+		e.arguments(objects)
+		e.argument(recipient)
+
+	case cmdSplitCoins, cmdMergeCoins:
+		coin, err := b.resolve(c.coin, resolved)
+		if err != nil {
+			return err
+		}
+		args, err := b.resolveMany(c.args, resolved)
+		if err != nil {
+			return err
+		}
+		e.argument(coin)
+		e.arguments(args)
+
+	case cmdPublish:
+		e.modulesAndDeps(c.modules, c.deps)
+
+	case cmdMakeMoveVec:
+		elems, err := b.resolveMany(c.args, resolved)
+		if err != nil {
+			return err
+		}
+		if c.elemType == nil {
+			e.WriteU8(0)
+		} else {
+			e.WriteU8(1)
+			if err := e.typeTag(*c.elemType); err != nil {
+				return buildErr(txbuilder.KindSerialization, "%v", err)
+			}
+		}
+		e.arguments(elems)
+
+	case cmdUpgrade:
+		ticket, err := b.resolve(c.coin, resolved)
+		if err != nil {
+			return err
+		}
+		e.modulesAndDeps(c.modules, c.deps)
+		e.WriteFixed(c.pkg[:])
+		e.argument(ticket)
+	}
+	return nil
+}
+
+func (e *encoder) modulesAndDeps(modules [][]byte, deps [][32]byte) {
+	e.WriteULEB128(uint64(len(modules)))
+	for _, m := range modules {
+		e.WriteBytes(m)
+	}
+	e.WriteULEB128(uint64(len(deps)))
+	for _, d := range deps {
+		e.WriteFixed(d[:])
+	}
+}
+
+// ── Formatting ────────────────────────────────────────────────────────────────
+
+func formatAddress(a [32]byte) string {
+	return "0x" + hex.EncodeToString(a[:])
+}
@@ -0,0 +1,99 @@
+// ids.go
+//
+// Address, ObjectID and Digest, re-exported from txbuilder, and their
+// conversion from the string fields of gRPC messages.
+
+package gosuisdk
+
+import (
+	"fmt"
+
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+)
+
+// Address is a Sui account address.
+type Address = txbuilder.Address
+
+// ObjectID is the ID of an object or package.
+type ObjectID = txbuilder.ObjectID
+
+// Digest is a transaction, object or checkpoint digest.
+type Digest = txbuilder.Digest
+
+// Well-known packages and system objects.
+var (
+	MoveStdlibPackageID    = txbuilder.MoveStdlibPackageID    // 0x1
+	SuiFrameworkPackageID  = txbuilder.SuiFrameworkPackageID  // 0x2
+	SuiSystemPackageID     = txbuilder.SuiSystemPackageID     // 0x3
+	SuiSystemStateObjectID = txbuilder.SuiSystemStateObjectID // 0x5
+	ClockObjectID          = txbuilder.ClockObjectID          // 0x6
+	RandomObjectID         = txbuilder.RandomObjectID         // 0x8
+	DenyListObjectID       = txbuilder.DenyListObjectID       // 0x403
+)
+
+// ParseAddress decodes a 0x-prefixed hex address; short forms such as
+// "0x2" are left-padded.
+func ParseAddress(s string) (Address, error) { return txbuilder.ParseAddress(s) }
+
+// ParseObjectID decodes a 0x-prefixed hex object ID; short forms such as
+// "0x6" are left-padded.
+func ParseObjectID(s string) (ObjectID, error) { return txbuilder.ParseObjectID(s) }
+
+// ParseDigest decodes a base58 digest.
+func ParseDigest(s string) (Digest, error) { return txbuilder.ParseDigest(s) }
+
+// MustParseAddress is ParseAddress for constants; it panics on error.
+func MustParseAddress(s string) Address { return txbuilder.MustParseAddress(s) }
+
+// MustParseObjectID is ParseObjectID for constants; it panics on error.
+func MustParseObjectID(s string) ObjectID { return txbuilder.MustParseObjectID(s) }
+
+// MustParseDigest is ParseDigest for constants; it panics on error.
+func MustParseDigest(s string) Digest { return txbuilder.MustParseDigest(s) }
+
+// ── gRPC objects ──────────────────────────────────────────────────────────────
+
+// ObjectRefGetter is implemented by *pb.Object and *pb.ObjectReference.
+type ObjectRefGetter interface {
+	GetObjectId() string
+	GetVersion() uint64
+	GetDigest() string
+}
+
+var (
+	_ ObjectRefGetter = (*pb.Object)(nil)
+	_ ObjectRefGetter = (*pb.ObjectReference)(nil)
+)
+
+// ObjectRefOf returns the ID, version and digest of an object read over
+// gRPC, as taken by TxBuilder.InputObject and AddGasObject.
+func ObjectRefOf(obj ObjectRefGetter) (ObjectID, uint64, Digest, error) {
+	id, err := ParseObjectID(obj.GetObjectId())
+	if err != nil {
+		return ObjectID{}, 0, Digest{}, err
+	}
+	digest, err := ParseDigest(obj.GetDigest())
+	if err != nil {
+		return ObjectID{}, 0, Digest{}, fmt.Errorf("object %s: %w", id, err)
+	}
+	return id, obj.GetVersion(), digest, nil
+}
+
+// addGasObject adds obj as a gas coin of b.
+func addGasObject(b TxBuilder, obj ObjectRefGetter) error {
+	id, version, digest, err := ObjectRefOf(obj)
+	if err != nil {
+		return err
+	}
+	return b.AddGasObject(id, version, digest)
+}
+
+// inputOwned pushes obj as an owned, mutable input of b.
+func inputOwned(b TxBuilder, obj ObjectRefGetter) (uint64, error) {
+	id, version, digest, err := ObjectRefOf(obj)
+	if err != nil {
+		return 0, err
+	}
+	return b.InputObject(id, version, digest, ObjectKindOwned, true)
+}
@@ -0,0 +1,688 @@
+// intent.go
+//
+// Transaction intents resolved in Go.
+//
+// The Rust crate resolves intents (transaction/src/intent) with a
+// sui_rpc::Client, which cannot run inside the WASM module, so the FFI does
+// not expose them.  IntentBuilder resolves them here over our own gRPC
+// connection instead.  It implements TxBuilder by recording every call
+// against virtual Argument IDs; Build lists the sender's coins, emits the
+// commands producing each intent's coin, then replays the recorded calls
+// into a fresh backend builder with the virtual IDs mapped to real ones:
+//
+//	balance 0                    0x2::coin::zero<T>()
+//	SUI                          coins join the gas payment, SplitCoins(Gas, […])
+//	other types, AvoidGasCoin    MergeCoins(c0, [c1 … cN]), SplitCoins(c0, […])
+//
+// Intents of the same coin type share one SplitCoins.
+//
+//	b := gosuisdk.NewIntentBuilder(conn, backend, ctx)
+//	b.SetConfig(sender, budget, price)
+//	coin, _ := b.CoinWithBalance(gosuisdk.CoinWithBalance{CoinType: usdc, Balance: 5_000_000})
+//	rec, _ := b.PureAddress(recipient)
+//	b.TransferObjects([]uint64{coin}, rec)
+//	txBytes, err := b.Build()
+
+package gosuisdk
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math/big"
+
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
+	"google.golang.org/grpc"
+)
+
+// CoinWithBalance asks for a Coin<CoinType> holding exactly Balance, taken
+// from the sender's coins when the transaction is built.
+type CoinWithBalance struct {
+	CoinType typetag.TypeTag // e.g. typetag.SUI
+	Balance  uint64
+
+	// AvoidGasCoin takes SUI from coins outside the gas payment, e.g. when
+	// gas is sponsored.  It has no effect on other coin types.
+	AvoidGasCoin bool
+}
+
+// usesGas reports whether the intent is split off the gas coin.
+func (c CoinWithBalance) usesGas() bool {
+	return isSuiType(c.CoinType) && !c.AvoidGasCoin
+}
+
+// intentOp is a recorded builder call.  apply receives the real Argument
+// IDs indexed by virtual ID.
+type intentOp struct {
+	id       uint64 // virtual ID of the result, when produces is set
+	produces bool
+	apply    func(b TxBuilder, ids []uint64) (uint64, error)
+}
+
+type coinIntent struct {
+	CoinWithBalance
+	id uint64 // virtual ID
+}
+
+type gasRef struct {
+	id      ObjectID
+	version uint64
+	digest  Digest
+}
+
+// IntentBuilder is a TxBuilder that also accepts intents.  Argument IDs it
+// returns are virtual and only valid with this builder.  Value errors are
+// reported by the call that made them; resolution and backend errors by
+// Build.  It is NOT safe for concurrent use.
+type IntentBuilder struct {
+	conn    *grpc.ClientConn
+	backend Backend
+	ctx     context.Context
+
+	sender              Address
+	gasBudget, gasPrice uint64
+	configured          bool
+	gas                 []gasRef
+	used                map[string]bool // object IDs never selected for intents
+
+	ops       []intentOp
+	intents   []*coinIntent
+	objects   map[uint64]*objectInput // recorded by InputObjectByID
+	objectIDs map[ObjectID]uint64
+
+	// Kept for ValidateMoveCalls.
+	functions *FunctionCache
+	validate  bool
+	calls     []*moveCallRecord
+	pures     map[uint64][]byte
+	inputs    map[uint64]inputObject
+	gasArgs   map[uint64]bool
+	next      uint64
+	consumed  bool
+}
+
+var _ txbuilder.Builder = (*IntentBuilder)(nil)
+
+// NewIntentBuilder returns an IntentBuilder that resolves intents over conn
+// and builds with backend.
+func NewIntentBuilder(conn *grpc.ClientConn, backend Backend, ctx context.Context) *IntentBuilder {
+	return &IntentBuilder{
+		conn: conn, backend: backend, ctx: ctx,
+		used:      map[string]bool{},
+		objects:   map[uint64]*objectInput{},
+		objectIDs: map[ObjectID]uint64{},
+		functions: NewFunctionCache(conn),
+		pures:     map[uint64][]byte{},
+		inputs:    map[uint64]inputObject{},
+		gasArgs:   map[uint64]bool{},
+	}
+}
+
+// ── Recording ─────────────────────────────────────────────────────────────────
+
+func (b *IntentBuilder) record(apply func(TxBuilder, []uint64) (uint64, error)) (uint64, error) {
+	if b.consumed {
+		return 0, txbuilder.ErrConsumed
+	}
+	id := b.next
+	b.next++
+	b.ops = append(b.ops, intentOp{id: id, produces: true, apply: apply})
+	return id, nil
+}
+
+func (b *IntentBuilder) recordCommand(apply func(TxBuilder, []uint64) error) error {
+	if b.consumed {
+		return txbuilder.ErrConsumed
+	}
+	b.ops = append(b.ops, intentOp{apply: func(tb TxBuilder, ids []uint64) (uint64, error) {
+		return 0, apply(tb, ids)
+	}})
+	return nil
+}
+
+// checkArg rejects an Argument ID this builder never returned.
+func (b *IntentBuilder) checkArg(fn, field string, id uint64) error {
+	if id >= b.next {
+		return txbuilder.NewError(fn, -4, txbuilder.KindUnknownArgument,
+			fmt.Sprintf("argument %d does not exist", id)).WithField(field)
+	}
+	return nil
+}
+
+// checkArgs is checkArg for a list field; the error names the index.
+func (b *IntentBuilder) checkArgs(fn, field string, ids []uint64) error {
+	for i, id := range ids {
+		if err := b.checkArg(fn, field, id); err != nil {
+			return err.(*txbuilder.Error).WithIndex(i)
+		}
+	}
+	return nil
+}
+
+// mapIDs translates virtual IDs to real ones.
+func mapIDs(ids, virtual []uint64) []uint64 {
+	out := make([]uint64, len(virtual))
+	for i, v := range virtual {
+		out[i] = ids[v]
+	}
+	return out
+}
+
+// ── Intents ───────────────────────────────────────────────────────────────────
+
+// CoinWithBalance registers a coin intent and returns the virtual Argument
+// ID of the coin it resolves to.
+func (b *IntentBuilder) CoinWithBalance(intent CoinWithBalance) (uint64, error) {
+	if b.consumed {
+		return 0, txbuilder.ErrConsumed
+	}
+	if err := txbuilder.CheckTypeTag("coin_with_balance", -1, "coin_type", intent.CoinType); err != nil {
+		return 0, err
+	}
+	id := b.next
+	b.next++
+	b.intents = append(b.intents, &coinIntent{CoinWithBalance: intent, id: id})
+	return id, nil
+}
+
+// ── TxBuilder ─────────────────────────────────────────────────────────────────
+
+// SetConfig sets the sender address, gas budget, and gas price.  The sender
+// also owns the coins intents are resolved from.
+func (b *IntentBuilder) SetConfig(sender Address, gasBudget, gasPrice uint64) error {
+	if b.consumed {
+		return txbuilder.ErrConsumed
+	}
+	b.sender, b.gasBudget, b.gasPrice, b.configured = sender, gasBudget, gasPrice, true
+	return nil
+}
+
+// AddGasObject adds an owned gas coin.  It is never selected for an intent.
+func (b *IntentBuilder) AddGasObject(id ObjectID, version uint64, digest Digest) error {
+	if b.consumed {
+		return txbuilder.ErrConsumed
+	}
+	b.gas = append(b.gas, gasRef{id: id, version: version, digest: digest})
+	b.used[id.String()] = true
+	return nil
+}
+
+// GasArgument returns the Argument ID for the transaction's gas coin.
+func (b *IntentBuilder) GasArgument() (uint64, error) {
+	id, err := b.record(func(tb TxBuilder, _ []uint64) (uint64, error) {
+		return tb.GasArgument()
+	})
+	if err == nil {
+		b.gasArgs[id] = true
+	}
+	return id, err
+}
+
+// InputObject pushes an object input and returns its Argument ID.  The
+// object is never selected for an intent.
+func (b *IntentBuilder) InputObject(id ObjectID, version uint64, digest Digest, kind ObjectKind, mutable bool) (uint64, error) {
+	if !b.consumed {
+		b.used[id.String()] = true
+	}
+	vid, err := b.record(func(tb TxBuilder, _ []uint64) (uint64, error) {
+		return tb.InputObject(id, version, digest, kind, mutable)
+	})
+	if err == nil {
+		b.inputs[vid] = inputObject{kind: kind, mutable: mutable}
+	}
+	return vid, err
+}
+
+// InputFundsWithdrawal pushes a withdrawal from an address balance and
+// returns its Argument ID.  Like a command result, it is not checked
+// against Move call signatures.
+func (b *IntentBuilder) InputFundsWithdrawal(w FundsWithdrawal) (uint64, error) {
+	return b.record(func(tb TxBuilder, _ []uint64) (uint64, error) {
+		return tb.InputFundsWithdrawal(w)
+	})
+}
+
+func (b *IntentBuilder) PureBool(v bool) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodePrimitive(v))
+}
+
+func (b *IntentBuilder) PureU8(v uint8) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodePrimitive(v))
+}
+
+func (b *IntentBuilder) PureU16(v uint16) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodePrimitive(v))
+}
+
+func (b *IntentBuilder) PureU32(v uint32) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodePrimitive(v))
+}
+
+func (b *IntentBuilder) PureU64(v uint64) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodePrimitive(v))
+}
+
+func (b *IntentBuilder) PureU128(hi, lo uint64) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeU128(hi, lo))
+}
+
+func (b *IntentBuilder) PureAddress(addr Address) (uint64, error) {
+	return b.PureRawBCS(addr[:])
+}
+
+// PureRawBCS pushes already-BCS-encoded bytes as a pure argument.
+func (b *IntentBuilder) PureRawBCS(bcsBytes []byte) (uint64, error) {
+	value := append([]byte{}, bcsBytes...)
+	id, err := b.record(func(tb TxBuilder, _ []uint64) (uint64, error) {
+		return tb.PureRawBCS(value)
+	})
+	if err == nil {
+		b.pures[id] = value
+	}
+	return id, err
+}
+
+func (b *IntentBuilder) PureString(s string) (uint64, error) {
+	return txbuilder.PureString(b, s)
+}
+
+func (b *IntentBuilder) PureASCIIString(s string) (uint64, error) {
+	return txbuilder.PureASCIIString(b, s)
+}
+
+func (b *IntentBuilder) PureBytes(v []byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeBytes(v))
+}
+
+func (b *IntentBuilder) PureU256(v *big.Int) (uint64, error) {
+	return txbuilder.PureU256(b, v)
+}
+
+func (b *IntentBuilder) PureID(id ObjectID) (uint64, error) {
+	return txbuilder.PureID(b, id)
+}
+
+func (b *IntentBuilder) PureOption(value []byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeOption(value))
+}
+
+func (b *IntentBuilder) PureVector(elems [][]byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeVectorOf(elems))
+}
+
+// NestedResult returns the Argument ID for the Nth sub-result of a
+// multi-output command.
+func (b *IntentBuilder) NestedResult(baseID, subIndex uint64) (uint64, error) {
+	if err := b.checkArg("nested_result", "base_id", baseID); err != nil {
+		return 0, err
+	}
+	return b.record(func(tb TxBuilder, ids []uint64) (uint64, error) {
+		return tb.NestedResult(ids[baseID], subIndex)
+	})
+}
+
+func (b *IntentBuilder) MoveCall(pkg ObjectID, module, function string, typeArgs []typetag.TypeTag, args []MoveCallArg) (uint64, error) {
+	for i, a := range args {
+		if a.ArgID != nil {
+			if err := b.checkArg("move_call", "arguments", *a.ArgID); err != nil {
+				return 0, err.(*txbuilder.Error).WithIndex(i)
+			}
+		}
+	}
+	for i, a := range args {
+		if a.ArgID != nil {
+			if in := b.objects[*a.ArgID]; in != nil {
+				in.calls = append(in.calls, moveUse{fn: functionKey{pkg, module, function}, index: i})
+			}
+		}
+	}
+	typeArgs = append([]typetag.TypeTag(nil), typeArgs...)
+	args = append([]MoveCallArg(nil), args...)
+	if !b.consumed {
+		b.calls = append(b.calls, &moveCallRecord{fn: functionKey{pkg, module, function}, typeArgs: typeArgs, args: args})
+	}
+	return b.record(func(tb TxBuilder, ids []uint64) (uint64, error) {
+		mapped := make([]MoveCallArg, len(args))
+		for i, a := range args {
+			if a.ArgID != nil {
+				mapped[i] = txbuilder.ArgID(ids[*a.ArgID])
+			} else {
+				mapped[i] = a
+			}
+		}
+		return tb.MoveCall(pkg, module, function, typeArgs, mapped)
+	})
+}
+
+func (b *IntentBuilder) SplitCoins(coinArgID uint64, amountArgIDs []uint64) (uint64, error) {
+	if err := b.checkArg("split_coins", "coin", coinArgID); err != nil {
+		return 0, err
+	}
+	if err := b.checkArgs("split_coins", "amounts", amountArgIDs); err != nil {
+		return 0, err
+	}
+	b.useByValue(coinArgID)
+	amounts := append([]uint64(nil), amountArgIDs...)
+	return b.record(func(tb TxBuilder, ids []uint64) (uint64, error) {
+		return tb.SplitCoins(ids[coinArgID], mapIDs(ids, amounts))
+	})
+}
+
+func (b *IntentBuilder) MergeCoins(targetCoinArgID uint64, sourceArgIDs []uint64) error {
+	if err := b.checkArg("merge_coins", "coin", targetCoinArgID); err != nil {
+		return err
+	}
+	if err := b.checkArgs("merge_coins", "sources", sourceArgIDs); err != nil {
+		return err
+	}
+	b.useByValue(targetCoinArgID)
+	b.useByValue(sourceArgIDs...)
+	sources := append([]uint64(nil), sourceArgIDs...)
+	return b.recordCommand(func(tb TxBuilder, ids []uint64) error {
+		return tb.MergeCoins(ids[targetCoinArgID], mapIDs(ids, sources))
+	})
+}
+
+func (b *IntentBuilder) TransferObjects(objectArgIDs []uint64, recipientArgID uint64) error {
+	if err := b.checkArgs("transfer_objects", "objects", objectArgIDs); err != nil {
+		return err
+	}
+	if err := b.checkArg("transfer_objects", "recipient", recipientArgID); err != nil {
+		return err
+	}
+	b.useByValue(objectArgIDs...)
+	objects := append([]uint64(nil), objectArgIDs...)
+	return b.recordCommand(func(tb TxBuilder, ids []uint64) error {
+		return tb.TransferObjects(mapIDs(ids, objects), ids[recipientArgID])
+	})
+}
+
+func (b *IntentBuilder) MakeMoveVec(elemType *typetag.TypeTag, elemArgIDs []uint64) (uint64, error) {
+	if err := b.checkArgs("make_move_vec", "elements", elemArgIDs); err != nil {
+		return 0, err
+	}
+	b.useByValue(elemArgIDs...)
+	elems := append([]uint64(nil), elemArgIDs...)
+	return b.record(func(tb TxBuilder, ids []uint64) (uint64, error) {
+		return tb.MakeMoveVec(elemType, mapIDs(ids, elems))
+	})
+}
+
+func (b *IntentBuilder) Publish(modules [][]byte, dependencies []ObjectID) (uint64, error) {
+	modules = append([][]byte(nil), modules...)
+	dependencies = append([]ObjectID(nil), dependencies...)
+	return b.record(func(tb TxBuilder, _ []uint64) (uint64, error) {
+		return tb.Publish(modules, dependencies)
+	})
+}
+
+func (b *IntentBuilder) Upgrade(modules [][]byte, dependencies []ObjectID, packageID ObjectID, ticketArgID uint64) (uint64, error) {
+	if err := b.checkArg("upgrade", "ticket_arg_id", ticketArgID); err != nil {
+		return 0, err
+	}
+	modules = append([][]byte(nil), modules...)
+	dependencies = append([]ObjectID(nil), dependencies...)
+	return b.record(func(tb TxBuilder, ids []uint64) (uint64, error) {
+		return tb.Upgrade(modules, dependencies, packageID, ids[ticketArgID])
+	})
+}
+
+// Free discards the recorded transaction.
+func (b *IntentBuilder) Free() {
+	b.consumed = true
+	b.ops, b.intents, b.objects, b.calls = nil, nil, nil, nil
+}
+
+// Build resolves the intents, replays the recorded calls into a backend
+// builder, and returns the transaction's BCS.  It consumes the builder.
+func (b *IntentBuilder) Build() ([]byte, error) {
+	if b.consumed {
+		return nil, txbuilder.ErrConsumed
+	}
+	defer b.Free()
+
+	if err := b.resolveObjects(); err != nil {
+		return nil, err
+	}
+	if b.validate {
+		if err := b.validateMoveCalls(); err != nil {
+			return nil, err
+		}
+	}
+	plan, err := b.resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	tb, err := b.backend.NewBuilder()
+	if err != nil {
+		return nil, err
+	}
+	built := false
+	defer func() {
+		if !built {
+			tb.Free()
+		}
+	}()
+
+	if b.configured {
+		if err := tb.SetConfig(b.sender, b.gasBudget, b.gasPrice); err != nil {
+			return nil, err
+		}
+	}
+	for _, g := range b.gas {
+		if err := tb.AddGasObject(g.id, g.version, g.digest); err != nil {
+			return nil, err
+		}
+	}
+	for _, g := range plan.gas {
+		if err := addGasObject(tb, g); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]uint64, b.next)
+	if err := plan.emit(tb, ids); err != nil {
+		return nil, err
+	}
+	for _, op := range b.ops {
+		res, err := op.apply(tb, ids)
+		if err != nil {
+			return nil, err
+		}
+		if op.produces {
+			ids[op.id] = res
+		}
+	}
+
+	built = true
+	return tb.Build()
+}
+
+// ── Resolution ────────────────────────────────────────────────────────────────
+
+// coinGroup is the intents of one coin type resolved from the same coins.
+type coinGroup struct {
+	coinType typetag.TypeTag
+	viaGas   bool
+	intents  []*coinIntent
+	coins    []*pb.Object // inputs to merge; extra gas coins when viaGas
+}
+
+func (g *coinGroup) total() (sum uint64, err error) {
+	for _, in := range g.intents {
+		if sum+in.Balance < sum {
+			return 0, fmt.Errorf("intents: %s balances overflow u64", g.coinType)
+		}
+		sum += in.Balance
+	}
+	return sum, nil
+}
+
+// intentPlan is the outcome of resolving every intent.
+type intentPlan struct {
+	zero   []*coinIntent
+	groups []*coinGroup
+	gas    []*pb.Object // coins added to the gas payment
+}
+
+// resolve lists the sender's coins and selects those covering each group.
+func (b *IntentBuilder) resolve() (*intentPlan, error) {
+	plan := &intentPlan{}
+	if len(b.intents) == 0 {
+		return plan, nil
+	}
+	if b.sender.IsZero() {
+		return nil, fmt.Errorf("intents: %w", txbuilder.ErrMissingSender)
+	}
+
+	byKey := map[string]*coinGroup{}
+	for _, in := range b.intents {
+		if in.Balance == 0 {
+			plan.zero = append(plan.zero, in)
+			continue
+		}
+		viaGas := in.usesGas()
+		key := in.CoinType.String()
+		if viaGas {
+			key = "gas"
+		}
+		g := byKey[key]
+		if g == nil {
+			g = &coinGroup{coinType: in.CoinType, viaGas: viaGas}
+			byKey[key] = g
+			plan.groups = append(plan.groups, g)
+		}
+		g.intents = append(g.intents, in)
+	}
+
+	// Coins already taken by the gas group are excluded from a SUI group
+	// avoiding the gas coin, so resolve the gas group first.
+	used := maps.Clone(b.used)
+	for _, g := range plan.groups {
+		if !g.viaGas {
+			continue
+		}
+		sum, err := g.total()
+		if err != nil {
+			return nil, err
+		}
+		// Without explicit gas coins the selected ones also pay for gas.
+		need := sum
+		if len(b.gas) == 0 {
+			need += b.gasBudget
+		}
+		coins, err := b.selectFor(g.coinType, need, MaxInputObjects, used)
+		if err != nil {
+			return nil, err
+		}
+		room := max(MaxGasObjects-len(b.gas), 0)
+		n := min(room, len(coins))
+		plan.gas, g.coins = coins[:n], coins[n:]
+		for _, c := range coins {
+			used[normalizeID(c.GetObjectId())] = true
+		}
+	}
+	for _, g := range plan.groups {
+		if g.viaGas {
+			continue
+		}
+		sum, err := g.total()
+		if err != nil {
+			return nil, err
+		}
+		if g.coins, err = b.selectFor(g.coinType, sum, MaxInputObjects, used); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// selectFor picks the sender's coins of cointype covering amount, skipping
+// objects in used.
+func (b *IntentBuilder) selectFor(coinType typetag.TypeTag, amount uint64, limit int, used map[string]bool) ([]*pb.Object, error) {
+	owned, err := listAllCoins(b.conn, b.sender, coinType, b.ctx)
+	if err != nil {
+		return nil, err
+	}
+	free := owned[:0:0]
+	for _, c := range owned {
+		if !used[normalizeID(c.GetObjectId())] {
+			free = append(free, c)
+		}
+	}
+	coins, err := SelectCoins(free, amount, limit)
+	if err != nil {
+		return nil, fmt.Errorf("intents: %s: %w", coinType.Short(), err)
+	}
+	return coins, nil
+}
+
+// emit pushes the commands producing every intent's coin and records the
+// real IDs in ids.
+func (p *intentPlan) emit(tb TxBuilder, ids []uint64) error {
+	for _, in := range p.zero {
+		res, err := tb.MoveCall(SuiFrameworkPackageID, "coin", "zero", []typetag.TypeTag{in.CoinType}, nil)
+		if err != nil {
+			return err
+		}
+		ids[in.id] = res
+	}
+
+	for _, g := range p.groups {
+		var primary uint64
+		var sources []uint64
+		var err error
+		if g.viaGas {
+			if primary, err = tb.GasArgument(); err != nil {
+				return err
+			}
+		}
+		for i, c := range g.coins {
+			arg, err := inputOwned(tb, c)
+			if err != nil {
+				return err
+			}
+			if i == 0 && !g.viaGas {
+				primary = arg
+			} else {
+				sources = append(sources, arg)
+			}
+		}
+		for start := 0; start < len(sources); start += MaxArguments {
+			end := min(start+MaxArguments, len(sources))
+			if err := tb.MergeCoins(primary, sources[start:end]); err != nil {
+				return err
+			}
+		}
+
+		amounts := make([]uint64, len(g.intents))
+		for i, in := range g.intents {
+			if amounts[i], err = tb.PureU64(in.Balance); err != nil {
+				return err
+			}
+		}
+		split, err := tb.SplitCoins(primary, amounts)
+		if err != nil {
+			return err
+		}
+		for i, in := range g.intents {
+			if ids[in.id], err = tb.NestedResult(split, uint64(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeID returns the canonical form of an object ID for comparison,
+// or id itself when it does not parse.
+func normalizeID(id string) string {
+	a, err := txbuilder.ParseAddress(id)
+	if err != nil {
+		return id
+	}
+	return fmt.Sprintf("%#x", a[:])
+}
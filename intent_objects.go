@@ -0,0 +1,176 @@
+// intent_objects.go
+//
+// Object inputs given by ID alone, resolved when an IntentBuilder builds.
+//
+// InputObjectByID records the object unresolved.  Build fetches every such
+// object in one BatchGetObjects call and derives the input from its Owner:
+//
+//	Address                  owned, at the fetched version and digest
+//	Immutable                immutable
+//	Shared, ConsensusAddress shared, at the initial shared version
+//	Object                   receiving (an object sent to another object)
+//
+// A shared object is taken mutably unless every use is a Move-call
+// parameter of type &T; the parameters are read with GetFunction.  Uses
+// outside Move calls (SplitCoins, MergeCoins, TransferObjects, MakeMoveVec)
+// take the object by value.
+
+package gosuisdk
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// objectInput is an object recorded by ID.
+type objectInput struct {
+	id      ObjectID
+	byValue bool      // used outside a Move call
+	calls   []moveUse // Move-call uses, checked against the signature
+
+	obj     *pb.Object // set by resolveObjects
+	mutable bool
+}
+
+// moveUse is an object passed as argument index of a Move call.
+type moveUse struct {
+	fn    functionKey
+	index int
+}
+
+type functionKey struct {
+	pkg              ObjectID
+	module, function string
+}
+
+// InputObjectByID pushes an object input known only by ID and returns its
+// Argument ID.  Version, digest, kind and mutability are filled in by
+// Build.  Repeated calls for the same object return the same ID.
+func (b *IntentBuilder) InputObjectByID(id ObjectID) (uint64, error) {
+	if b.consumed {
+		return 0, txbuilder.ErrConsumed
+	}
+	if vid, ok := b.objectIDs[id]; ok {
+		return vid, nil
+	}
+	in := &objectInput{id: id}
+	vid, err := b.record(func(tb TxBuilder, _ []uint64) (uint64, error) {
+		return in.push(tb)
+	})
+	if err != nil {
+		return 0, err
+	}
+	b.objects[vid] = in
+	b.objectIDs[id] = vid
+	b.used[id.String()] = true
+	return vid, nil
+}
+
+// useByValue records that the objects among ids are used outside a Move
+// call.
+func (b *IntentBuilder) useByValue(ids ...uint64) {
+	for _, id := range ids {
+		if in := b.objects[id]; in != nil {
+			in.byValue = true
+		}
+	}
+}
+
+// push adds the resolved object to tb.
+func (in *objectInput) push(tb TxBuilder) (uint64, error) {
+	owner := in.obj.GetOwner()
+	switch owner.GetKind() {
+	case pb.Owner_ADDRESS:
+		return inputOwned(tb, in.obj)
+	case pb.Owner_IMMUTABLE:
+		id, version, digest, err := ObjectRefOf(in.obj)
+		if err != nil {
+			return 0, err
+		}
+		return tb.InputObject(id, version, digest, ObjectKindImmutable, false)
+	case pb.Owner_SHARED, pb.Owner_CONSENSUS_ADDRESS:
+		return tb.InputObject(in.id, owner.GetVersion(), Digest{}, ObjectKindShared, in.mutable)
+	case pb.Owner_OBJECT:
+		return ReceivingInput(tb, in.obj)
+	}
+	return 0, fmt.Errorf("input object %s: cannot be used as an input (owner kind %s)", in.id, owner.GetKind())
+}
+
+// resolveObjects fetches the objects recorded by ID and infers the
+// mutability of shared ones.
+func (b *IntentBuilder) resolveObjects() error {
+	if len(b.objects) == 0 {
+		return nil
+	}
+	inputs := make([]*objectInput, 0, len(b.objects))
+	ids := make([]ObjectID, 0, len(b.objects))
+	for _, in := range b.objects {
+		inputs = append(inputs, in)
+		ids = append(ids, in.id)
+	}
+	objs, err := getObjectsInOrder(b.conn, ids, []string{"object_id", "version", "digest", "owner"}, b.ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, in := range inputs {
+		in.obj = objs[i]
+		kind := in.obj.GetOwner().GetKind()
+		if kind != pb.Owner_SHARED && kind != pb.Owner_CONSENSUS_ADDRESS {
+			continue
+		}
+		if in.mutable = in.byValue; in.mutable {
+			continue
+		}
+		for _, use := range in.calls {
+			fn, err := b.functions.Function(use.fn.pkg, use.fn.module, use.fn.function, b.ctx)
+			if err != nil {
+				return err
+			}
+			params := fn.GetParameters()
+			if use.index >= len(params) || params[use.index].GetReference() != pb.OpenSignature_IMMUTABLE {
+				in.mutable = true
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// getObjectsInOrder is BatchGetObjects with a read mask, returning the
+// objects in the order of ids.  A missing object is an error.
+func getObjectsInOrder(conn *grpc.ClientConn, ids []ObjectID, mask []string, ctx context.Context) ([]*pb.Object, error) {
+	requests := make([]*pb.GetObjectRequest, len(ids))
+	for i := range ids {
+		id := ids[i].String()
+		requests[i] = &pb.GetObjectRequest{ObjectId: &id}
+	}
+	client := pb.NewLedgerServiceClient(conn)
+	resp, err := client.BatchGetObjects(ctx, &pb.BatchGetObjectsRequest{
+		Requests: requests,
+		ReadMask: &fieldmaskpb.FieldMask{Paths: mask},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := resp.GetObjects()
+	if len(results) != len(ids) {
+		return nil, fmt.Errorf("batch get objects: asked for %d objects, got %d", len(ids), len(results))
+	}
+	objs := make([]*pb.Object, len(ids))
+	for i, res := range results {
+		if e := res.GetError(); e != nil {
+			return nil, fmt.Errorf("object %s: %s", ids[i], e.GetMessage())
+		}
+		if objs[i] = res.GetObject(); objs[i] == nil {
+			return nil, fmt.Errorf("object %s: not returned", ids[i])
+		}
+	}
+	return objs, nil
+}
@@ -0,0 +1,306 @@
+package gosuisdk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pictorx/go-sui-sdk/gobuilder"
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// coinServer serves fixed coin lists, keyed by Coin<T> object type, to
+// ListOwnedObjects.
+type coinServer struct {
+	pb.UnimplementedStateServiceServer
+	owner Address
+	coins map[string][]*pb.Object
+}
+
+func (s *coinServer) ListOwnedObjects(_ context.Context, req *pb.ListOwnedObjectsRequest) (*pb.ListOwnedObjectsResponse, error) {
+	if req.GetOwner() != s.owner.String() {
+		return nil, fmt.Errorf("unexpected owner %s", req.GetOwner())
+	}
+	return &pb.ListOwnedObjectsResponse{Objects: s.coins[req.GetObjectType()]}, nil
+}
+
+// coinConn returns a connection to a coinServer for confSender.
+func coinConn(t *testing.T, coins map[string][]*pb.Object) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterStateServiceServer(srv, &coinServer{owner: confSender, coins: coins})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///coins",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// recordingBackend builds with gobuilder and keeps the plan of the last
+// builder it handed out.
+type recordingBackend struct {
+	rec *txbuilder.Recorder
+}
+
+func (r *recordingBackend) Kind() BackendKind { return BackendGo }
+
+func (r *recordingBackend) NewBuilder() (TxBuilder, error) {
+	r.rec = txbuilder.NewRecorder(gobuilder.NewBuilder())
+	return r.rec, nil
+}
+
+func (r *recordingBackend) Close(context.Context) error { return nil }
+
+func testCoin(id string, balance uint64) *pb.Object {
+	version, digest := uint64(1), confDigest.String()
+	return &pb.Object{ObjectId: &id, Version: &version, Digest: &digest, Balance: &balance}
+}
+
+// summarize renders the inputs and commands of p, one per line.
+func summarize(p *txbuilder.Plan) []string {
+	var out []string
+	for _, in := range p.Inputs {
+		switch {
+		case in.Object != nil:
+			out = append(out, "object "+in.Object.ID.Short())
+		case in.Pure != nil && in.Pure.BCS != nil:
+			out = append(out, fmt.Sprintf("pure bcs %x", []byte(in.Pure.BCS)))
+		case in.Pure != nil:
+			out = append(out, "pure "+in.Pure.Type+" "+strings.Trim(string(in.Pure.Value), `"`))
+		}
+	}
+	for _, c := range p.Commands {
+		switch {
+		case c.MoveCall != nil:
+			var types []string
+			for _, t := range c.MoveCall.TypeArgs {
+				types = append(types, t.Short())
+			}
+			out = append(out, fmt.Sprintf("move_call %s::%s::%s<%s> %v", c.MoveCall.Package.Short(),
+				c.MoveCall.Module, c.MoveCall.Function, strings.Join(types, ", "), c.MoveCall.Arguments))
+		case c.SplitCoins != nil:
+			out = append(out, fmt.Sprintf("split_coins %v %v", c.SplitCoins.Coin, c.SplitCoins.Amounts))
+		case c.MergeCoins != nil:
+			out = append(out, fmt.Sprintf("merge_coins %v %v", c.MergeCoins.Coin, c.MergeCoins.Sources))
+		case c.TransferObjects != nil:
+			out = append(out, fmt.Sprintf("transfer_objects %v %v", c.TransferObjects.Objects, c.TransferObjects.Recipient))
+		}
+	}
+	return out
+}
+
+func TestIntentBuilder(t *testing.T) {
+	usd := typetag.MustParse("0xab::usd::USD")
+	suiCoins, usdCoins := SuiCoin.String(), Coin{Type: usd}.String()
+
+	// MaxGasObjects+2 SUI coins, all needed: the two smallest are merged
+	// into the gas coin.
+	var many []*pb.Object
+	var manyTotal uint64
+	for i := range MaxGasObjects + 2 {
+		many = append(many, testCoin(fmt.Sprintf("%#x", 0x1000+i), uint64(1+i)))
+		manyTotal += uint64(1 + i)
+	}
+	var manyGas []string
+	for i := MaxGasObjects + 1; i >= 2; i-- {
+		manyGas = append(manyGas, fmt.Sprintf("%#x", 0x1000+i))
+	}
+
+	tests := []struct {
+		name  string
+		coins map[string][]*pb.Object
+		gas   bool // add 0x9a5 as an explicit gas coin
+		build func(b *IntentBuilder) error
+		// wantGas are the gas payment coins, wantPlan the inputs and
+		// commands.
+		wantGas  []string
+		wantPlan []string
+		wantErr  string
+	}{
+		{
+			name:  "gas group",
+			coins: map[string][]*pb.Object{suiCoins: {testCoin("0x5002", 100), testCoin("0x5001", 5000)}},
+			build: func(b *IntentBuilder) error {
+				// The recipient is recorded first, so its virtual ID is
+				// lower than the coins' but its real input comes after
+				// the split amounts.
+				rec, err := b.PureAddress(confRecipient)
+				if err != nil {
+					return err
+				}
+				a, _ := b.CoinWithBalance(CoinWithBalance{CoinType: typetag.SUI, Balance: 100})
+				c, _ := b.CoinWithBalance(CoinWithBalance{CoinType: typetag.SUI, Balance: 200})
+				return b.TransferObjects([]uint64{c, a}, rec)
+			},
+			wantGas: []string{"0x5001"},
+			wantPlan: []string{
+				"pure u64 100",
+				"pure u64 200",
+				fmt.Sprintf("pure bcs %x", confRecipient[:]),
+				"split_coins gas [input 0 input 1]",
+				"transfer_objects [nested result 0.1 nested result 0.0] input 2",
+			},
+		},
+		{
+			name: "avoid gas coin",
+			coins: map[string][]*pb.Object{suiCoins: {
+				testCoin("0x9a5", 10_000), testCoin("0x5001", 300), testCoin("0x5002", 200), testCoin("0x5003", 50),
+			}},
+			gas: true,
+			build: func(b *IntentBuilder) error {
+				_, err := b.CoinWithBalance(CoinWithBalance{CoinType: typetag.SUI, Balance: 450, AvoidGasCoin: true})
+				return err
+			},
+			wantGas: []string{"0x9a5"},
+			wantPlan: []string{
+				"object 0x5001",
+				"object 0x5002",
+				"pure u64 450",
+				"merge_coins input 0 [input 1]",
+				"split_coins input 0 [input 2]",
+			},
+		},
+		{
+			name: "gas group before avoid gas coin",
+			coins: map[string][]*pb.Object{suiCoins: {
+				testCoin("0x5001", 2000), testCoin("0x5002", 700), testCoin("0x5003", 600),
+			}},
+			build: func(b *IntentBuilder) error {
+				// The avoiding group is registered first but must not take
+				// the coin the gas group selects.
+				if _, err := b.CoinWithBalance(CoinWithBalance{CoinType: typetag.SUI, Balance: 600, AvoidGasCoin: true}); err != nil {
+					return err
+				}
+				_, err := b.CoinWithBalance(CoinWithBalance{CoinType: typetag.SUI, Balance: 500})
+				return err
+			},
+			wantGas: []string{"0x5001"},
+			wantPlan: []string{
+				"object 0x5002",
+				"pure u64 600",
+				"pure u64 500",
+				"split_coins input 0 [input 1]",
+				"split_coins gas [input 2]",
+			},
+		},
+		{
+			name:  "gas objects overflow into merges",
+			coins: map[string][]*pb.Object{suiCoins: many},
+			build: func(b *IntentBuilder) error {
+				_, err := b.CoinWithBalance(CoinWithBalance{CoinType: typetag.SUI, Balance: manyTotal - 1000})
+				return err
+			},
+			wantGas: manyGas,
+			wantPlan: []string{
+				"object 0x1001",
+				"object 0x1000",
+				fmt.Sprintf("pure u64 %d", manyTotal-1000),
+				"merge_coins gas [input 0 input 1]",
+				"split_coins gas [input 2]",
+			},
+		},
+		{
+			name:  "zero balance and merged coins",
+			coins: map[string][]*pb.Object{usdCoins: {testCoin("0x7001", 100), testCoin("0x7002", 80)}},
+			gas:   true,
+			build: func(b *IntentBuilder) error {
+				if _, err := b.CoinWithBalance(CoinWithBalance{CoinType: usd, Balance: 150}); err != nil {
+					return err
+				}
+				_, err := b.CoinWithBalance(CoinWithBalance{CoinType: usd})
+				return err
+			},
+			wantGas: []string{"0x9a5"},
+			wantPlan: []string{
+				"object 0x7001",
+				"object 0x7002",
+				"pure u64 150",
+				"move_call 0x2::coin::zero<0xab::usd::USD> []",
+				"merge_coins input 0 [input 1]",
+				"split_coins input 0 [input 2]",
+			},
+		},
+		{
+			name:  "insufficient balance",
+			coins: map[string][]*pb.Object{usdCoins: {testCoin("0x7001", 100)}},
+			gas:   true,
+			build: func(b *IntentBuilder) error {
+				_, err := b.CoinWithBalance(CoinWithBalance{CoinType: usd, Balance: 150})
+				return err
+			},
+			wantErr: "insufficient balance",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &recordingBackend{}
+			b := NewIntentBuilder(coinConn(t, tt.coins), backend, context.Background())
+			if err := b.SetConfig(confSender, 1000, confPrice); err != nil {
+				t.Fatal(err)
+			}
+			if tt.gas {
+				if err := b.AddGasObject(confGas, 7, confDigest); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tt.build(b); err != nil {
+				t.Fatal(err)
+			}
+			_, err := b.Build()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Build: %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			plan := backend.rec.Plan()
+			var gas []string
+			for _, g := range plan.Gas {
+				gas = append(gas, g.ID.Short())
+			}
+			if !slices.Equal(gas, tt.wantGas) {
+				t.Errorf("gas = %v, want %v", gas, tt.wantGas)
+			}
+			if got := summarize(plan); !slices.Equal(got, tt.wantPlan) {
+				t.Errorf("plan:\n\t%s\nwant:\n\t%s", strings.Join(got, "\n\t"), strings.Join(tt.wantPlan, "\n\t"))
+			}
+		})
+	}
+}
+
+// Resolving must not mark the selected coins as used on the builder.
+func TestIntentResolveKeepsUsed(t *testing.T) {
+	conn := coinConn(t, map[string][]*pb.Object{SuiCoin.String(): {testCoin("0x5001", 5000)}})
+	b := NewIntentBuilder(conn, GoBackend(), context.Background())
+	if err := b.SetConfig(confSender, 1000, confPrice); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.CoinWithBalance(CoinWithBalance{CoinType: typetag.SUI, Balance: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.resolve(); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.used) != 0 {
+		t.Errorf("used = %v after resolve, want empty", b.used)
+	}
+}
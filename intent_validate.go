@@ -0,0 +1,359 @@
+// intent_validate.go
+//
+// Move call validation against on-chain function signatures.
+//
+// With ValidateMoveCalls, Build checks every recorded MoveCall against the
+// FunctionDescriptor returned by GetFunction before anything is serialised:
+//
+//   - the function is public or entry
+//   - the number of type arguments matches its type parameters
+//   - the number of arguments matches its parameters, not counting a
+//     trailing &TxContext / &mut TxContext
+//   - pure arguments decode as the parameter type (primitives, vectors,
+//     0x1::string::String, 0x1::ascii::String, 0x2::object::ID, Option)
+//   - object arguments fit the parameter's &T / &mut T / T: immutable
+//     objects only by &T, shared objects taken immutably only by &T,
+//     receiving objects only as 0x2::transfer::Receiving<T>, and the gas
+//     coin never by value
+//
+// Parameters typed by a type parameter are not shape-checked.  Results of
+// earlier commands carry no type here and are not checked either.
+
+package gosuisdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pictorx/go-sui-sdk/bcs"
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
+	"google.golang.org/grpc"
+)
+
+// FunctionCache holds Move function signatures fetched with GetFunction,
+// grouped by package.  It is safe for concurrent use and may be shared
+// between builders.
+type FunctionCache struct {
+	conn     *grpc.ClientConn
+	mu       sync.Mutex
+	packages map[ObjectID]map[string]*pb.FunctionDescriptor // package → "module::function"
+}
+
+// NewFunctionCache returns an empty cache fetching over conn.
+func NewFunctionCache(conn *grpc.ClientConn) *FunctionCache {
+	return &FunctionCache{conn: conn, packages: map[ObjectID]map[string]*pb.FunctionDescriptor{}}
+}
+
+// Function returns the signature of pkg::module::name, fetching it on first
+// use.
+func (c *FunctionCache) Function(pkg ObjectID, module, name string, ctx context.Context) (*pb.FunctionDescriptor, error) {
+	key, fn := pkg, module+"::"+name
+	c.mu.Lock()
+	cached := c.packages[key][fn]
+	c.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	resp, err := GetFunction(c.conn, pkg, module, name, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s::%s::%s: %w", pkg.Short(), module, name, err)
+	}
+	desc := resp.GetFunction()
+	if desc == nil {
+		return nil, fmt.Errorf("%s::%s::%s: no function returned", pkg.Short(), module, name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.packages[key] == nil {
+		c.packages[key] = map[string]*pb.FunctionDescriptor{}
+	}
+	c.packages[key][fn] = desc
+	return desc, nil
+}
+
+// moveCallRecord is a MoveCall kept for validation.
+type moveCallRecord struct {
+	fn       functionKey
+	typeArgs []typetag.TypeTag
+	args     []MoveCallArg
+}
+
+// inputObject is an object pushed with InputObject.
+type inputObject struct {
+	kind    ObjectKind
+	mutable bool
+}
+
+// ValidateMoveCalls makes Build check every MoveCall against its on-chain
+// signature.  cache may be nil to use the builder's own.
+func (b *IntentBuilder) ValidateMoveCalls(cache *FunctionCache) {
+	if cache != nil {
+		b.functions = cache
+	}
+	b.validate = true
+}
+
+// validateMoveCalls runs the checks described at the top of the file.
+func (b *IntentBuilder) validateMoveCalls() error {
+	for _, call := range b.calls {
+		desc, err := b.functions.Function(call.fn.pkg, call.fn.module, call.fn.function, b.ctx)
+		if err != nil {
+			return err
+		}
+		if err := b.validateMoveCall(call, desc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *IntentBuilder) validateMoveCall(call *moveCallRecord, desc *pb.FunctionDescriptor) error {
+	target := call.fn.pkg.Short() + "::" + call.fn.module + "::" + call.fn.function
+	mismatch := func(field, format string, args ...any) *txbuilder.Error {
+		return txbuilder.NewError("move_call", 0, txbuilder.KindSignatureMismatch,
+			target+": "+fmt.Sprintf(format, args...)).WithField(field)
+	}
+
+	if desc.GetVisibility() != pb.FunctionDescriptor_PUBLIC && !desc.GetIsEntry() {
+		return mismatch("function", "function is neither public nor entry")
+	}
+	if want, got := len(desc.GetTypeParameters()), len(call.typeArgs); want != got {
+		return mismatch("type_args", "expects %d type arguments, got %d", want, got)
+	}
+
+	params := desc.GetParameters()
+	if n := len(params); n > 0 && isDatatype(params[n-1].GetBody(), "0x2", "tx_context", "TxContext") {
+		params = params[:n-1]
+	}
+	if want, got := len(params), len(call.args); want != got {
+		return mismatch("arguments", "expects %d arguments, got %d", want, got)
+	}
+
+	for i, arg := range call.args {
+		if err := b.checkMoveArg(arg, params[i]); err != nil {
+			return mismatch("arguments", "argument %d (%s): %v", i, signatureString(params[i]), err).WithIndex(i)
+		}
+	}
+	return nil
+}
+
+// checkMoveArg checks one argument against its parameter.
+func (b *IntentBuilder) checkMoveArg(arg MoveCallArg, param *pb.OpenSignature) error {
+	body, ref := param.GetBody(), param.GetReference()
+	if arg.ArgID == nil {
+		return checkPure(arg.PureBCS, body)
+	}
+	id := *arg.ArgID
+
+	if value, ok := b.pures[id]; ok {
+		return checkPure(value, body)
+	}
+	if b.gasArgs[id] {
+		if ref == pb.OpenSignature_REFERENCE_UNKNOWN {
+			return errors.New("the gas coin can only be taken by value in TransferObjects")
+		}
+		return nil
+	}
+
+	var kind ObjectKind
+	var mutable bool
+	if in, ok := b.inputs[id]; ok {
+		kind, mutable = in.kind, in.mutable
+	} else if in := b.objects[id]; in != nil {
+		switch in.obj.GetOwner().GetKind() {
+		case pb.Owner_IMMUTABLE:
+			kind = ObjectKindImmutable
+		case pb.Owner_SHARED, pb.Owner_CONSENSUS_ADDRESS:
+			kind, mutable = ObjectKindShared, in.mutable
+		case pb.Owner_OBJECT:
+			kind = ObjectKindReceiving
+		default:
+			kind = ObjectKindOwned
+		}
+	} else {
+		return nil // a command result
+	}
+
+	if isPureType(body) {
+		return fmt.Errorf("expects a pure value, got a %s object", kind)
+	}
+	receiving := isDatatype(body, "0x2", "transfer", "Receiving")
+	switch {
+	case kind == ObjectKindReceiving && (!receiving || ref != pb.OpenSignature_REFERENCE_UNKNOWN):
+		return errors.New("a receiving object can only be passed as Receiving<T> by value")
+	case kind != ObjectKindReceiving && receiving:
+		return fmt.Errorf("expects a receiving object, got a %s object", kind)
+	case kind == ObjectKindImmutable && ref != pb.OpenSignature_IMMUTABLE:
+		return errors.New("an immutable object can only be passed by &")
+	case kind == ObjectKindShared && !mutable && ref != pb.OpenSignature_IMMUTABLE:
+		return errors.New("the shared object is taken immutably but the parameter needs it mutably")
+	}
+	return nil
+}
+
+// ── Pure values ───────────────────────────────────────────────────────────────
+
+// errTypeParameter marks a shape that depends on a type argument.
+var errTypeParameter = errors.New("type parameter")
+
+// checkPure reports whether value is exactly one BCS value of type t.
+func checkPure(value []byte, t *pb.OpenSignatureBody) error {
+	if !isPureType(t) {
+		if t.GetType() == pb.OpenSignatureBody_TYPE_PARAMETER {
+			return nil
+		}
+		return fmt.Errorf("expects an object, got a pure value")
+	}
+	d := bcs.NewDecoder(value)
+	if err := readPure(d, t); err != nil {
+		if errors.Is(err, errTypeParameter) {
+			return nil
+		}
+		return fmt.Errorf("pure value does not decode: %w", err)
+	}
+	if n := d.Remaining(); n > 0 {
+		return fmt.Errorf("pure value has %d trailing bytes", n)
+	}
+	return nil
+}
+
+// readPure consumes one value of type t.
+func readPure(d *bcs.Decoder, t *pb.OpenSignatureBody) error {
+	var err error
+	switch t.GetType() {
+	case pb.OpenSignatureBody_BOOL:
+		_, err = d.ReadBool()
+	case pb.OpenSignatureBody_U8:
+		_, err = d.ReadFixed(1)
+	case pb.OpenSignatureBody_U16:
+		_, err = d.ReadFixed(2)
+	case pb.OpenSignatureBody_U32:
+		_, err = d.ReadFixed(4)
+	case pb.OpenSignatureBody_U64:
+		_, err = d.ReadFixed(8)
+	case pb.OpenSignatureBody_U128:
+		_, err = d.ReadFixed(16)
+	case pb.OpenSignatureBody_U256, pb.OpenSignatureBody_ADDRESS:
+		_, err = d.ReadFixed(32)
+	case pb.OpenSignatureBody_VECTOR:
+		return readPureSeq(d, t, -1)
+	case pb.OpenSignatureBody_DATATYPE:
+		switch {
+		case isDatatype(t, "0x1", "string", "String"):
+			_, err = d.ReadString()
+		case isDatatype(t, "0x1", "ascii", "String"):
+			var s []byte
+			if s, err = d.ReadBytes(); err == nil {
+				for _, c := range s {
+					if c > 0x7f {
+						return fmt.Errorf("byte 0x%02x is not ASCII", c)
+					}
+				}
+			}
+		case isDatatype(t, "0x2", "object", "ID"):
+			_, err = d.ReadFixed(32)
+		case isDatatype(t, "0x1", "option", "Option"):
+			return readPureSeq(d, t, 1)
+		default:
+			return fmt.Errorf("%s is not a pure type", bodyString(t))
+		}
+	case pb.OpenSignatureBody_TYPE_PARAMETER:
+		return errTypeParameter
+	default:
+		return fmt.Errorf("unknown type %s", t.GetType())
+	}
+	return err
+}
+
+// readPureSeq reads a vector<T> (or an Option<T>, a vector of at most one
+// element) whose element type is t's first type argument.
+func readPureSeq(d *bcs.Decoder, t *pb.OpenSignatureBody, maxLen int) error {
+	inst := t.GetTypeParameterInstantiation()
+	if len(inst) != 1 {
+		return fmt.Errorf("%s has %d type arguments", bodyString(t), len(inst))
+	}
+	n, err := d.ReadLength()
+	if err != nil {
+		return err
+	}
+	if maxLen >= 0 && n > maxLen {
+		return fmt.Errorf("invalid Option tag %d", n)
+	}
+	for i := range n {
+		if err := readPure(d, inst[0]); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// isPureType reports whether values of t can be passed as pure arguments.
+func isPureType(t *pb.OpenSignatureBody) bool {
+	switch t.GetType() {
+	case pb.OpenSignatureBody_TYPE_PARAMETER, pb.OpenSignatureBody_TYPE_UNKNOWN:
+		return false
+	case pb.OpenSignatureBody_VECTOR:
+		inst := t.GetTypeParameterInstantiation()
+		return len(inst) == 1 && (isPureType(inst[0]) || inst[0].GetType() == pb.OpenSignatureBody_TYPE_PARAMETER)
+	case pb.OpenSignatureBody_DATATYPE:
+		if isDatatype(t, "0x1", "option", "Option") {
+			inst := t.GetTypeParameterInstantiation()
+			return len(inst) == 1 && (isPureType(inst[0]) || inst[0].GetType() == pb.OpenSignatureBody_TYPE_PARAMETER)
+		}
+		return isDatatype(t, "0x1", "string", "String") || isDatatype(t, "0x1", "ascii", "String") ||
+			isDatatype(t, "0x2", "object", "ID")
+	}
+	return true
+}
+
+// ── Type names ────────────────────────────────────────────────────────────────
+
+// isDatatype reports whether t is the datatype addr::module::name.
+func isDatatype(t *pb.OpenSignatureBody, addr, module, name string) bool {
+	if t.GetType() != pb.OpenSignatureBody_DATATYPE {
+		return false
+	}
+	parts := strings.SplitN(t.GetTypeName(), "::", 3)
+	return len(parts) == 3 && parts[1] == module && parts[2] == name &&
+		normalizeID(parts[0]) == normalizeID(addr)
+}
+
+// signatureString renders a parameter, e.g. "&mut 0x2::coin::Coin<T0>".
+func signatureString(s *pb.OpenSignature) string {
+	switch s.GetReference() {
+	case pb.OpenSignature_IMMUTABLE:
+		return "&" + bodyString(s.GetBody())
+	case pb.OpenSignature_MUTABLE:
+		return "&mut " + bodyString(s.GetBody())
+	}
+	return bodyString(s.GetBody())
+}
+
+func bodyString(t *pb.OpenSignatureBody) string {
+	switch t.GetType() {
+	case pb.OpenSignatureBody_TYPE_PARAMETER:
+		return fmt.Sprintf("T%d", t.GetTypeParameter())
+	case pb.OpenSignatureBody_VECTOR, pb.OpenSignatureBody_DATATYPE:
+		name := "vector"
+		if t.GetType() == pb.OpenSignatureBody_DATATYPE {
+			name = t.GetTypeName()
+		}
+		inst := t.GetTypeParameterInstantiation()
+		if len(inst) == 0 {
+			return name
+		}
+		args := make([]string, len(inst))
+		for i, a := range inst {
+			args[i] = bodyString(a)
+		}
+		return name + "<" + strings.Join(args, ", ") + ">"
+	}
+	return strings.ToLower(strings.TrimPrefix(t.GetType().String(), "TYPE_"))
+}
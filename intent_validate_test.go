@@ -0,0 +1,211 @@
+package gosuisdk
+
+import (
+	"errors"
+	"testing"
+
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
+	"google.golang.org/protobuf/proto"
+)
+
+// An object taken by ID and owned by another object enters as
+// Receiving<T>, so validation treats it as a receiving object.
+func TestCheckMoveArgObjectOwned(t *testing.T) {
+	byID := func(owner pb.Owner_OwnerKind) *IntentBuilder {
+		obj := &pb.Object{Owner: &pb.Owner{Kind: owner.Enum()}}
+		return &IntentBuilder{objects: map[uint64]*objectInput{0: {obj: obj}}}
+	}
+	param := func(ref pb.OpenSignature_Reference, typeName string) *pb.OpenSignature {
+		return &pb.OpenSignature{Reference: ref.Enum(), Body: &pb.OpenSignatureBody{
+			Type:     pb.OpenSignatureBody_DATATYPE.Enum(),
+			TypeName: proto.String(typeName),
+		}}
+	}
+	var (
+		receiving = param(pb.OpenSignature_REFERENCE_UNKNOWN, "0x2::transfer::Receiving")
+		borrowed  = param(pb.OpenSignature_IMMUTABLE, "0x2::coin::Coin")
+		byValue   = param(pb.OpenSignature_REFERENCE_UNKNOWN, "0x2::coin::Coin")
+	)
+
+	tests := []struct {
+		name  string
+		owner pb.Owner_OwnerKind
+		param *pb.OpenSignature
+		ok    bool
+	}{
+		{"object-owned as Receiving", pb.Owner_OBJECT, receiving, true},
+		{"object-owned by reference", pb.Owner_OBJECT, borrowed, false},
+		{"object-owned by value", pb.Owner_OBJECT, byValue, false},
+		{"address-owned as Receiving", pb.Owner_ADDRESS, receiving, false},
+		{"address-owned by value", pb.Owner_ADDRESS, byValue, true},
+	}
+	for _, tt := range tests {
+		err := byID(tt.owner).checkMoveArg(ArgID(0), tt.param)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+// ── Signatures ────────────────────────────────────────────────────────────────
+
+func prim(t pb.OpenSignatureBody_Type) *pb.OpenSignatureBody {
+	return &pb.OpenSignatureBody{Type: t.Enum()}
+}
+
+func vectorOf(elem *pb.OpenSignatureBody) *pb.OpenSignatureBody {
+	return &pb.OpenSignatureBody{Type: pb.OpenSignatureBody_VECTOR.Enum(), TypeParameterInstantiation: []*pb.OpenSignatureBody{elem}}
+}
+
+func datatype(name string, inst ...*pb.OpenSignatureBody) *pb.OpenSignatureBody {
+	return &pb.OpenSignatureBody{Type: pb.OpenSignatureBody_DATATYPE.Enum(), TypeName: proto.String(name), TypeParameterInstantiation: inst}
+}
+
+func typeParam(i uint32) *pb.OpenSignatureBody {
+	return &pb.OpenSignatureBody{Type: pb.OpenSignatureBody_TYPE_PARAMETER.Enum(), TypeParameter: proto.Uint32(i)}
+}
+
+func signature(ref pb.OpenSignature_Reference, body *pb.OpenSignatureBody) *pb.OpenSignature {
+	return &pb.OpenSignature{Reference: ref.Enum(), Body: body}
+}
+
+func TestValidateMoveCall(t *testing.T) {
+	var (
+		u64       = signature(pb.OpenSignature_REFERENCE_UNKNOWN, prim(pb.OpenSignatureBody_U64))
+		flag      = signature(pb.OpenSignature_REFERENCE_UNKNOWN, prim(pb.OpenSignatureBody_BOOL))
+		ctxMut    = signature(pb.OpenSignature_MUTABLE, datatype("0x2::tx_context::TxContext"))
+		ctxRef    = signature(pb.OpenSignature_IMMUTABLE, datatype("0x0000000000000000000000000000000000000000000000000000000000000002::tx_context::TxContext"))
+		seven     = ArgBCS([]byte{7, 0, 0, 0, 0, 0, 0, 0})
+		yes       = ArgBCS([]byte{1})
+		public    = pb.FunctionDescriptor_PUBLIC
+		private   = pb.FunctionDescriptor_PRIVATE
+		friend    = pb.FunctionDescriptor_FRIEND
+		oneTParam = []*pb.TypeParameter{{}}
+	)
+
+	tests := []struct {
+		name       string
+		visibility pb.FunctionDescriptor_Visibility
+		entry      bool
+		tparams    []*pb.TypeParameter
+		params     []*pb.OpenSignature
+		typeArgs   []typetag.TypeTag
+		args       []MoveCallArg
+		field      string // "" when the call is valid
+		index      int
+	}{
+		{name: "public", visibility: public, params: []*pb.OpenSignature{u64, flag}, args: []MoveCallArg{seven, yes}},
+		{name: "private entry", visibility: private, entry: true, params: []*pb.OpenSignature{u64}, args: []MoveCallArg{seven}},
+		{name: "private", visibility: private, params: []*pb.OpenSignature{u64}, args: []MoveCallArg{seven}, field: "function"},
+		{name: "friend", visibility: friend, params: []*pb.OpenSignature{u64}, args: []MoveCallArg{seven}, field: "function"},
+
+		{name: "type argument", visibility: public, tparams: oneTParam, typeArgs: []typetag.TypeTag{typetag.SUI}},
+		{name: "missing type argument", visibility: public, tparams: oneTParam, field: "type_args"},
+		{name: "extra type argument", visibility: public, typeArgs: []typetag.TypeTag{typetag.SUI}, field: "type_args"},
+
+		{name: "&mut TxContext stripped", visibility: public, params: []*pb.OpenSignature{u64, ctxMut}, args: []MoveCallArg{seven}},
+		{name: "&TxContext stripped", visibility: public, params: []*pb.OpenSignature{u64, ctxRef}, args: []MoveCallArg{seven}},
+		{name: "only TxContext", visibility: public, params: []*pb.OpenSignature{ctxMut}},
+		{name: "TxContext passed", visibility: public, params: []*pb.OpenSignature{u64, ctxMut}, args: []MoveCallArg{seven, seven}, field: "arguments"},
+		{name: "TxContext not last", visibility: public, params: []*pb.OpenSignature{ctxMut, u64}, args: []MoveCallArg{seven}, field: "arguments"},
+
+		{name: "too few arguments", visibility: public, params: []*pb.OpenSignature{u64, flag}, args: []MoveCallArg{seven}, field: "arguments"},
+		{name: "too many arguments", visibility: public, params: []*pb.OpenSignature{u64}, args: []MoveCallArg{seven, yes}, field: "arguments"},
+		{name: "pure shape", visibility: public, params: []*pb.OpenSignature{u64, flag}, args: []MoveCallArg{seven, seven}, field: "arguments", index: 1},
+	}
+	for _, tt := range tests {
+		desc := &pb.FunctionDescriptor{
+			Visibility:     tt.visibility.Enum(),
+			IsEntry:        proto.Bool(tt.entry),
+			TypeParameters: tt.tparams,
+			Parameters:     tt.params,
+		}
+		call := &moveCallRecord{
+			fn:       functionKey{pkg: mustObjectID("0xabc"), module: "m", function: "f"},
+			typeArgs: tt.typeArgs,
+			args:     tt.args,
+		}
+		err := (&IntentBuilder{}).validateMoveCall(call, desc)
+		if tt.field == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		var te *txbuilder.Error
+		if !errors.Is(err, txbuilder.ErrSignatureMismatch) || !errors.As(err, &te) {
+			t.Errorf("%s: %v, want a signature mismatch", tt.name, err)
+			continue
+		}
+		if te.Field != tt.field {
+			t.Errorf("%s: field %q, want %q", tt.name, te.Field, tt.field)
+		}
+		if tt.index > 0 && (te.Index == nil || *te.Index != tt.index) {
+			t.Errorf("%s: index %v, want %d", tt.name, te.Index, tt.index)
+		}
+	}
+}
+
+func TestCheckPure(t *testing.T) {
+	var (
+		u8      = prim(pb.OpenSignatureBody_U8)
+		u64     = prim(pb.OpenSignatureBody_U64)
+		address = prim(pb.OpenSignatureBody_ADDRESS)
+		utf8    = datatype("0x1::string::String")
+		ascii   = datatype("0x1::ascii::String")
+		id      = datatype("0x2::object::ID")
+		option  = func(t *pb.OpenSignatureBody) *pb.OpenSignatureBody { return datatype("0x1::option::Option", t) }
+		zeros   = func(n int) []byte { return make([]byte, n) }
+	)
+
+	tests := []struct {
+		name  string
+		t     *pb.OpenSignatureBody
+		value []byte
+		ok    bool
+	}{
+		{"bool", prim(pb.OpenSignatureBody_BOOL), []byte{1}, true},
+		{"bool out of range", prim(pb.OpenSignatureBody_BOOL), []byte{2}, false},
+		{"u64", u64, zeros(8), true},
+		{"u64 short", u64, zeros(7), false},
+		{"u64 trailing bytes", u64, zeros(9), false},
+		{"u256", prim(pb.OpenSignatureBody_U256), zeros(32), true},
+		{"address", address, zeros(32), true},
+		{"address short", address, zeros(20), false},
+		{"object ID", id, zeros(32), true},
+
+		{"Option none", option(u64), []byte{0}, true},
+		{"Option some", option(u64), append([]byte{1}, zeros(8)...), true},
+		{"Option bad tag", option(u64), append([]byte{2}, zeros(16)...), false},
+		{"Option some missing value", option(u64), []byte{1}, false},
+		{"Option none trailing bytes", option(u64), []byte{0, 0}, false},
+
+		{"string", utf8, []byte{2, 'h', 'i'}, true},
+		{"string invalid UTF-8", utf8, []byte{1, 0xff}, false},
+		{"string short", utf8, []byte{3, 'h', 'i'}, false},
+		{"ascii", ascii, []byte{2, 'h', 'i'}, true},
+		{"ascii non-ASCII", ascii, []byte{2, 'h', 0xe9}, false},
+
+		{"vector<u8>", vectorOf(u8), []byte{3, 1, 2, 3}, true},
+		{"vector<u8> trailing bytes", vectorOf(u8), []byte{2, 1, 2, 3}, false},
+		{"vector<vector<u8>>", vectorOf(vectorOf(u8)), []byte{2, 1, 9, 0}, true},
+		{"vector<vector<u8>> short", vectorOf(vectorOf(u8)), []byte{2, 1, 9, 2, 1}, false},
+		{"vector<vector<u8>> as vector<u8>", vectorOf(vectorOf(u8)), []byte{1, 9}, false},
+		{"vector<Option<address>>", vectorOf(option(address)), append([]byte{2, 0, 1}, zeros(32)...), true},
+		{"vector<Option<address>> bad tag", vectorOf(option(address)), []byte{1, 3}, false},
+		{"vector<ascii>", vectorOf(ascii), []byte{2, 1, 'a', 1, 0x80}, false},
+
+		{"type parameter", typeParam(0), []byte{1, 2, 3}, true},
+		{"vector<T>", vectorOf(typeParam(0)), []byte{1, 2, 3}, true},
+		{"object", datatype("0x2::coin::Coin", typeParam(0)), zeros(32), false},
+		{"vector of objects", vectorOf(datatype("0x2::coin::Coin", typeParam(0))), []byte{0}, false},
+	}
+	for _, tt := range tests {
+		err := checkPure(tt.value, tt.t)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// limits.go
+//
+// Protocol limits loaded from the node and enforced while building.
+//
+//	limits, err := gosuisdk.GetProtocolLimits(conn, ctx)
+//	backend = gosuisdk.LimitedBackend(backend, limits)
+//
+// Builders from a limited backend fail with txbuilder.ErrLimitExceeded at the
+// call that crosses a limit, before anything is simulated or signed.
+
+package gosuisdk
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Limits is a protocol limits profile; see txbuilder.Limits.
+type Limits = txbuilder.Limits
+
+// GetProtocolLimits reads the limits of the current epoch's protocol
+// version.
+func GetProtocolLimits(conn *grpc.ClientConn, ctx context.Context) (Limits, error) {
+	client := pb.NewLedgerServiceClient(conn)
+	resp, err := client.GetEpoch(ctx, &pb.GetEpochRequest{
+		ReadMask: &fieldmaskpb.FieldMask{
+			Paths: []string{"protocol_config"},
+		},
+	})
+	if err != nil {
+		return Limits{}, err
+	}
+	return LimitsFromProtocolConfig(resp.GetEpoch().GetProtocolConfig())
+}
+
+// LimitsFromProtocolConfig reads the limits from cfg's attributes.
+func LimitsFromProtocolConfig(cfg *pb.ProtocolConfig) (Limits, error) {
+	if cfg == nil {
+		return Limits{}, errors.New("protocol limits: no protocol config in response")
+	}
+	return txbuilder.LimitsFromAttributes(cfg.GetAttributes())
+}
+
+type limitedBackend struct {
+	Backend
+	limits Limits
+}
+
+// LimitedBackend returns a Backend whose builders enforce limits on top of
+// backend's (txbuilder.WithLimits).
+func LimitedBackend(backend Backend, limits Limits) Backend {
+	return &limitedBackend{Backend: backend, limits: limits}
+}
+
+func (l *limitedBackend) NewBuilder() (TxBuilder, error) {
+	b, err := l.Backend.NewBuilder()
+	if err != nil {
+		return nil, err
+	}
+	return txbuilder.WithLimits(b, l.limits), nil
+}
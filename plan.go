@@ -0,0 +1,83 @@
+// plan.go
+//
+// Execution of transaction plans (txbuilder.Plan): PTBs authored or
+// exported as JSON and run by the Go signer.
+//
+//	p, err := gosuisdk.ParsePlan(data)
+//	resp, err := (&gosuisdk.ExecutePlan{Plan: p}).SignExecuteTx(conn, backend, account, ctx)
+//
+// The plan is replayed into an IntentBuilder, so object inputs given by ID
+// alone are resolved over the connection when the transaction is built.
+
+package gosuisdk
+
+import (
+	"context"
+	"errors"
+
+	"github.com/block-vision/sui-go-sdk/signer"
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"google.golang.org/grpc"
+)
+
+// Plan is an unbuilt transaction in the JSON plan format; see
+// txbuilder.Plan.
+type Plan = txbuilder.Plan
+
+// ParsePlan decodes and validates a JSON plan.
+func ParsePlan(data []byte) (*Plan, error) { return txbuilder.ParsePlan(data) }
+
+// ExecutePlan signs and executes Plan.  The plan must name its sender and
+// gas price; its gas budget is the starting point for the estimate.
+type ExecutePlan struct {
+	Plan *Plan
+
+	// GasCoins pays for gas when the plan lists no gas objects.  When both
+	// are empty, the largest SUI coins covering the gas budget are
+	// selected.
+	GasCoins []*pb.Object
+}
+
+func (e *ExecutePlan) buildTx(conn *grpc.ClientConn, backend Backend, budget uint64, gas []*pb.Object, ctx context.Context) ([]byte, error) {
+	b := NewIntentBuilder(conn, backend, ctx)
+	p := *e.Plan
+	p.GasBudget = budget
+	if err := p.Replay(b); err != nil {
+		b.Free()
+		return nil, err
+	}
+	for _, g := range gas {
+		if err := addGasObject(b, g); err != nil {
+			b.Free()
+			return nil, err
+		}
+	}
+	return b.Build()
+}
+
+func (e *ExecutePlan) SignExecuteTx(conn *grpc.ClientConn, backend Backend, account *signer.Signer, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
+	p := e.Plan
+	switch {
+	case p == nil:
+		return nil, errors.New("execute plan: no plan")
+	case p.Sender.IsZero():
+		return nil, errors.New("execute plan: plan has no sender")
+	case p.GasBudget == 0 || p.GasPrice == 0:
+		return nil, errors.New("execute plan: plan needs gas_budget and gas_price")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var gas []*pb.Object
+	if len(p.Gas) == 0 {
+		var err error
+		if gas, err = selectGasCoins(conn, p.Sender, p.GasBudget, e.GasCoins, ctx); err != nil {
+			return nil, err
+		}
+	}
+	return estimateSignExecute(conn, account, p.GasBudget, func(budget uint64) ([]byte, error) {
+		return e.buildTx(conn, backend, budget, gas, ctx)
+	}, nil, ctx)
+}
@@ -0,0 +1,517 @@
+// publish.go
+//
+// Publishing and upgrading Move packages from `sui move build` output.
+//
+// LoadCompiledPackage reads build/<pkg>/bytecode_modules/*.mv and the
+// dependency IDs from build/<pkg>/BuildInfo.yaml.  PublishPackage sends
+//
+//	cap = Publish(modules, dependencies)
+//	TransferObjects([cap], sender)
+//
+// and UpgradePackage runs the upgrade through the package's UpgradeCap:
+//
+//	ticket  = 0x2::package::authorize_upgrade(&mut cap, policy, digest)
+//	receipt = Upgrade(modules, dependencies, package, ticket)
+//	0x2::package::commit_upgrade(&mut cap, receipt)
+//
+// Both report the new package ID and version from the effects.
+
+package gosuisdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/block-vision/sui-go-sdk/signer"
+	"github.com/pictorx/go-sui-sdk/bcs"
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/typetag"
+	"golang.org/x/crypto/blake2b"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Upgrade policies of 0x2::package::UpgradeCap.
+const (
+	UpgradePolicyCompatible uint8 = 0
+	UpgradePolicyAdditive   uint8 = 128
+	UpgradePolicyDepOnly    uint8 = 192
+)
+
+// UpgradeCapType is the Move type of the capability returned by Publish.
+var UpgradeCapType = typetag.MustParse("0x2::package::UpgradeCap")
+
+// CompiledPackage is a built Move package ready to publish.
+type CompiledPackage struct {
+	Name    string
+	Modules [][]byte
+	// Dependencies are the IDs of the packages linked against, always
+	// including 0x1 and 0x2.
+	Dependencies []ObjectID
+}
+
+// LoadCompiledPackage reads the output of `sui move build`.  dir is either
+// the package root holding build/ or build/<pkg> itself.  Modules are read
+// in file-name order.
+//
+// Dependency IDs are the non-zero named addresses of the build, as Move.toml
+// or Move.lock resolved them; for a dependency upgraded since, replace its
+// original ID in Dependencies with the latest one, or compute the list from
+// the bytecode with ResolveDependencies.
+func LoadCompiledPackage(dir string) (*CompiledPackage, error) {
+	buildDir, err := findBuildDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(buildDir, "bytecode_modules", "*.mv"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%s: no compiled modules in bytecode_modules", buildDir)
+	}
+	sort.Strings(paths)
+	pkg := &CompiledPackage{Name: filepath.Base(buildDir)}
+	for _, p := range paths {
+		m, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		pkg.Modules = append(pkg.Modules, m)
+	}
+
+	info, err := os.ReadFile(filepath.Join(buildDir, "BuildInfo.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	name, aliases, err := parseBuildInfo(info)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", buildDir, err)
+	}
+	if name != "" {
+		pkg.Name = name
+	}
+
+	deps := map[ObjectID]bool{MoveStdlibPackageID: true, SuiFrameworkPackageID: true}
+	for alias, addr := range aliases {
+		if strings.EqualFold(alias, pkg.Name) {
+			continue // the package's own address when already published
+		}
+		id, err := ParseObjectID("0x" + addr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: address of %s: %w", buildDir, alias, err)
+		}
+		if !id.IsZero() {
+			deps[id] = true
+		}
+	}
+	for id := range deps {
+		pkg.Dependencies = append(pkg.Dependencies, id)
+	}
+	sortObjectIDs(pkg.Dependencies)
+	return pkg, nil
+}
+
+// findBuildDir resolves dir to the build/<pkg> directory.
+func findBuildDir(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "bytecode_modules")); err == nil {
+		return dir, nil
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "build"))
+	if err != nil {
+		return "", fmt.Errorf("%s: no build output, run `sui move build`: %w", dir, err)
+	}
+	var candidates []string
+	for _, e := range entries {
+		p := filepath.Join(dir, "build", e.Name())
+		if _, err := os.Stat(filepath.Join(p, "bytecode_modules")); e.IsDir() && err == nil {
+			candidates = append(candidates, p)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("%s: no build/<pkg>/bytecode_modules", dir)
+	case 1:
+		return candidates[0], nil
+	}
+	// Several packages were built into the same directory; prefer the one
+	// named in Move.toml.
+	if toml, err := os.ReadFile(filepath.Join(dir, "Move.toml")); err == nil {
+		if name := moveTomlName(toml); name != "" {
+			for _, c := range candidates {
+				if filepath.Base(c) == name {
+					return c, nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("%s: several built packages, pass build/<pkg>: %v", dir, candidates)
+}
+
+// moveTomlName returns the name in Move.toml's [package] section.
+func moveTomlName(toml []byte) string {
+	section := ""
+	sc := bufio.NewScanner(bytes.NewReader(toml))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok && section == "package" && strings.TrimSpace(k) == "name" {
+			return strings.Trim(strings.TrimSpace(v), `"'`)
+		}
+	}
+	return ""
+}
+
+// parseBuildInfo extracts the package name and named addresses from
+// BuildInfo.yaml:
+//
+//	compiled_package_info:
+//	  package_name: example
+//	  address_alias_instantiation:
+//	    std: "0000…0001"
+func parseBuildInfo(yaml []byte) (name string, aliases map[string]string, err error) {
+	aliases = map[string]string{}
+	inAliases, aliasIndent := false, 0
+	sc := bufio.NewScanner(bytes.NewReader(yaml))
+	for sc.Scan() {
+		raw := sc.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		k, v, _ := strings.Cut(line, ":")
+		k, v = strings.TrimSpace(k), strings.Trim(strings.TrimSpace(v), `"'`)
+
+		if inAliases {
+			if indent > aliasIndent {
+				aliases[k] = v
+				continue
+			}
+			inAliases = false
+		}
+		switch k {
+		case "package_name":
+			name = v
+		case "address_alias_instantiation":
+			inAliases, aliasIndent = true, indent
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return "", nil, err
+	}
+	if len(aliases) == 0 {
+		return "", nil, fmt.Errorf("BuildInfo.yaml has no address_alias_instantiation")
+	}
+	return name, aliases, nil
+}
+
+// Digest returns the package digest authorize_upgrade expects: the
+// blake2b-256 of the sorted module hashes and dependency IDs.
+func (p *CompiledPackage) Digest() ([32]byte, error) {
+	components := make([][]byte, 0, len(p.Modules)+len(p.Dependencies))
+	for _, m := range p.Modules {
+		h := blake2b.Sum256(m)
+		components = append(components, h[:])
+	}
+	for _, d := range p.Dependencies {
+		components = append(components, d[:])
+	}
+	sort.Slice(components, func(i, j int) bool { return bytes.Compare(components[i], components[j]) < 0 })
+
+	h, _ := blake2b.New256(nil)
+	for _, c := range components {
+		h.Write(c)
+	}
+	var digest [32]byte
+	copy(digest[:], h.Sum(nil))
+	return digest, nil
+}
+
+// resolvedPackage returns pkg, or a copy of it with the dependencies
+// computed by ResolveDependencies when resolve is set.
+func resolvedPackage(conn *grpc.ClientConn, pkg *CompiledPackage, resolve bool, ctx context.Context) (*CompiledPackage, error) {
+	if !resolve {
+		return pkg, nil
+	}
+	deps, err := ResolveDependencies(conn, pkg, ctx)
+	if err != nil {
+		return nil, err
+	}
+	cp := *pkg
+	cp.Dependencies = deps
+	return &cp, nil
+}
+
+// PublishResult describes a published or upgraded package.
+type PublishResult struct {
+	Digest    Digest // transaction digest
+	PackageID ObjectID
+	Version   uint64
+	// UpgradeCap is the capability sent to the sender by a publish, or the
+	// one used by an upgrade.
+	UpgradeCap ObjectID
+}
+
+// publishResult reads the new package (and, on publish, the UpgradeCap)
+// from the effects of a successful transaction.
+func publishResult(resp *pb.ExecuteTransactionResponse) (*PublishResult, error) {
+	tx := resp.GetTransaction()
+	effects := tx.GetEffects()
+	if !effects.GetStatus().GetSuccess() {
+		return nil, fmt.Errorf("transaction %s failed: %s", tx.GetDigest(), effects.GetStatus().GetError())
+	}
+	digest, err := ParseDigest(tx.GetDigest())
+	if err != nil {
+		return nil, err
+	}
+	res := &PublishResult{Digest: digest}
+	found := false
+	for _, c := range effects.GetChangedObjects() {
+		switch {
+		case c.GetOutputState() == pb.ChangedObject_OUTPUT_OBJECT_STATE_PACKAGE_WRITE:
+			if res.PackageID, err = ParseObjectID(c.GetObjectId()); err != nil {
+				return nil, err
+			}
+			res.Version, found = c.GetOutputVersion(), true
+		case c.GetIdOperation() == pb.ChangedObject_CREATED && isUpgradeCapType(c.GetObjectType()):
+			if res.UpgradeCap, err = ParseObjectID(c.GetObjectId()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("transaction %s: no package in effects", tx.GetDigest())
+	}
+	return res, nil
+}
+
+func isUpgradeCapType(t string) bool {
+	tag, err := typetag.Parse(t)
+	return err == nil && tag.Equal(UpgradeCapType)
+}
+
+// selectGasCoins returns given, or the sender's largest SUI coins covering
+// budget.
+func selectGasCoins(conn *grpc.ClientConn, sender Address, budget uint64, given []*pb.Object, ctx context.Context) ([]*pb.Object, error) {
+	if len(given) > 0 {
+		return given, nil
+	}
+	owned, err := listAllCoins(conn, sender, SuiCoin.Type, ctx)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := SelectCoins(owned, budget, MaxGasObjects)
+	if err != nil {
+		return nil, fmt.Errorf("gas: %w", err)
+	}
+	return gas, nil
+}
+
+// withBuilder runs fill on a configured builder and builds it.
+func withBuilder(backend Backend, sender Address, budget, price uint64, gas []*pb.Object, fill func(b TxBuilder) error) ([]byte, error) {
+	b, err := backend.NewBuilder()
+	if err != nil {
+		return nil, err
+	}
+	built := false
+	defer func() {
+		if !built {
+			b.Free()
+		}
+	}()
+
+	if err := b.SetConfig(sender, budget, price); err != nil {
+		return nil, err
+	}
+	for _, g := range gas {
+		if err := addGasObject(b, g); err != nil {
+			return nil, err
+		}
+	}
+	if err := fill(b); err != nil {
+		return nil, err
+	}
+
+	built = true
+	return b.Build()
+}
+
+// ── Publish ───────────────────────────────────────────────────────────────────
+
+// PublishPackage publishes Package and sends its UpgradeCap to Sender.
+type PublishPackage struct {
+	Sender    Address
+	Package   *CompiledPackage
+	Gasbudget uint64
+	Gasprice  uint64
+
+	// GasCoins pays for gas.  When empty, the largest SUI coins covering
+	// Gasbudget are selected.
+	GasCoins []*pb.Object
+
+	// ResolveDependencies replaces Package.Dependencies with the list
+	// computed from the bytecode (see ResolveDependencies).
+	ResolveDependencies bool
+}
+
+func (p *PublishPackage) buildTx(b TxBuilder, pkg *CompiledPackage) error {
+	capArg, err := b.Publish(pkg.Modules, pkg.Dependencies)
+	if err != nil {
+		return err
+	}
+	sender, err := b.PureAddress(p.Sender)
+	if err != nil {
+		return err
+	}
+	return b.TransferObjects([]uint64{capArg}, sender)
+}
+
+func (p *PublishPackage) SignExecuteTx(conn *grpc.ClientConn, backend Backend, account *signer.Signer, ctx context.Context) (*PublishResult, error) {
+	pkg, err := resolvedPackage(conn, p.Package, p.ResolveDependencies, ctx)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := selectGasCoins(conn, p.Sender, p.Gasbudget, p.GasCoins, ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := estimateSignExecute(conn, account, p.Gasbudget, func(budget uint64) ([]byte, error) {
+		return withBuilder(backend, p.Sender, budget, p.Gasprice, gas, func(b TxBuilder) error {
+			return p.buildTx(b, pkg)
+		})
+	}, []string{"digest", "effects"}, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return publishResult(resp)
+}
+
+// ── Upgrade ───────────────────────────────────────────────────────────────────
+
+// upgradeCap mirrors the contents of 0x2::package::UpgradeCap.
+type upgradeCap struct {
+	ID      [32]byte
+	Package [32]byte
+	Version uint64
+	Policy  uint8
+}
+
+// UpgradePackage upgrades the package controlled by UpgradeCap to Package.
+type UpgradePackage struct {
+	Sender     Address
+	UpgradeCap ObjectID // owned by Sender
+	Package    *CompiledPackage
+	// Policy is the upgrade policy to authorize; nil uses the cap's own.
+	Policy    *uint8
+	Gasbudget uint64
+	Gasprice  uint64
+
+	// GasCoins pays for gas.  When empty, the largest SUI coins covering
+	// Gasbudget are selected.
+	GasCoins []*pb.Object
+
+	// ResolveDependencies replaces Package.Dependencies with the list
+	// computed from the bytecode (see ResolveDependencies).
+	ResolveDependencies bool
+}
+
+// loadCap fetches the UpgradeCap with its contents.
+func (u *UpgradePackage) loadCap(conn *grpc.ClientConn, ctx context.Context) (*pb.Object, *upgradeCap, error) {
+	client := pb.NewLedgerServiceClient(conn)
+	id := u.UpgradeCap.String()
+	resp, err := client.GetObject(ctx, &pb.GetObjectRequest{
+		ObjectId: &id,
+		ReadMask: &fieldmaskpb.FieldMask{
+			Paths: []string{"object_id", "version", "digest", "object_type", "contents"},
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	obj := resp.GetObject()
+	if !isUpgradeCapType(obj.GetObjectType()) {
+		return nil, nil, fmt.Errorf("%s is a %s, not an %s", u.UpgradeCap, obj.GetObjectType(), UpgradeCapType.Short())
+	}
+	var contents upgradeCap
+	if err := bcs.Unmarshal(obj.GetContents().GetValue(), &contents); err != nil {
+		return nil, nil, fmt.Errorf("%s: decoding UpgradeCap: %w", u.UpgradeCap, err)
+	}
+	return obj, &contents, nil
+}
+
+func (u *UpgradePackage) buildTx(b TxBuilder, pkg *CompiledPackage, capObj *pb.Object, packageID ObjectID, policy uint8, digest [32]byte) error {
+	capArg, err := inputOwned(b, capObj)
+	if err != nil {
+		return err
+	}
+	policyArg, err := b.PureU8(policy)
+	if err != nil {
+		return err
+	}
+	digestArg, err := b.PureBytes(digest[:])
+	if err != nil {
+		return err
+	}
+	ticket, err := b.MoveCall(SuiFrameworkPackageID, "package", "authorize_upgrade", nil,
+		[]MoveCallArg{ArgID(capArg), ArgID(policyArg), ArgID(digestArg)})
+	if err != nil {
+		return err
+	}
+	receipt, err := b.Upgrade(pkg.Modules, pkg.Dependencies, packageID, ticket)
+	if err != nil {
+		return err
+	}
+	_, err = b.MoveCall(SuiFrameworkPackageID, "package", "commit_upgrade", nil,
+		[]MoveCallArg{ArgID(capArg), ArgID(receipt)})
+	return err
+}
+
+func (u *UpgradePackage) SignExecuteTx(conn *grpc.ClientConn, backend Backend, account *signer.Signer, ctx context.Context) (*PublishResult, error) {
+	capObj, contents, err := u.loadCap(conn, ctx)
+	if err != nil {
+		return nil, err
+	}
+	policy := contents.Policy
+	if u.Policy != nil {
+		policy = *u.Policy
+	}
+	packageID := ObjectID(contents.Package)
+	pkg, err := resolvedPackage(conn, u.Package, u.ResolveDependencies, ctx)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := pkg.Digest()
+	if err != nil {
+		return nil, err
+	}
+	gas, err := selectGasCoins(conn, u.Sender, u.Gasbudget, u.GasCoins, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := estimateSignExecute(conn, account, u.Gasbudget, func(budget uint64) ([]byte, error) {
+		return withBuilder(backend, u.Sender, budget, u.Gasprice, gas, func(b TxBuilder) error {
+			return u.buildTx(b, pkg, capObj, packageID, policy, digest)
+		})
+	}, []string{"digest", "effects"}, ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := publishResult(resp)
+	if err != nil {
+		return nil, err
+	}
+	res.UpgradeCap = u.UpgradeCap
+	return res, nil
+}
@@ -0,0 +1,310 @@
+// publish_deps.go
+//
+// Dependency lists for Publish and Upgrade computed from the bytecode.
+//
+// A module refers to other packages by their original (runtime) IDs in its
+// module handle table.  Publish and Upgrade instead take the storage IDs of
+// every transitive dependency, at versions at least as new as any package in
+// the set links against, or fail with PUBLISH_UPGRADE_MISSING_DEPENDENCY.
+// ResolveDependencies collects the referenced packages, takes the latest
+// version of each, and merges their linkage tables:
+//
+//	deps, err := gosuisdk.ResolveDependencies(conn, pkg, ctx)
+
+package gosuisdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"google.golang.org/grpc"
+)
+
+// ModuleRef names a module by package address and module name.
+type ModuleRef struct {
+	Address ObjectID
+	Name    string
+}
+
+func (m ModuleRef) String() string { return m.Address.Short() + "::" + m.Name }
+
+// Move binary format constants.
+const (
+	moveMagic              = 0xa11ceb0b
+	moveTableModuleHandles = 0x1
+	moveTableIdentifiers   = 0x7
+	moveTableAddresses     = 0x8
+	moveAddressLength      = 32
+	// Version 5 added the self module handle index after the tables;
+	// earlier modules are their own first handle.
+	moveVersionSelfIndex = 5
+)
+
+// moveReader reads the primitives of the Move binary format.
+type moveReader struct {
+	b   []byte
+	pos int
+}
+
+var errMoveEOF = errors.New("unexpected end of bytecode")
+
+func (r *moveReader) uleb() (uint64, error) {
+	var v uint64
+	for shift := 0; shift < 64; shift += 7 {
+		if r.pos >= len(r.b) {
+			return 0, errMoveEOF
+		}
+		c := r.b[r.pos]
+		r.pos++
+		v |= uint64(c&0x7f) << shift
+		if c&0x80 == 0 {
+			return v, nil
+		}
+	}
+	return 0, errors.New("uleb128 overflow")
+}
+
+func (r *moveReader) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(r.b)-r.pos) {
+		return nil, errMoveEOF
+	}
+	b := r.b[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+// ParseModuleRefs reads a compiled module's own name and the modules it
+// refers to (its module handles, which include itself).
+func ParseModuleRefs(bytecode []byte) (self ModuleRef, refs []ModuleRef, err error) {
+	r := &moveReader{b: bytecode}
+	header, err := r.bytes(8)
+	if err != nil {
+		return ModuleRef{}, nil, err
+	}
+	if binary.BigEndian.Uint32(header) != moveMagic {
+		return ModuleRef{}, nil, errors.New("not a compiled Move module (bad magic)")
+	}
+	// The high byte of the version carries the binary flavor.
+	version := binary.LittleEndian.Uint32(header[4:]) & 0x00ffffff
+
+	count, err := r.uleb()
+	if err != nil {
+		return ModuleRef{}, nil, err
+	}
+	type table struct{ offset, length uint64 }
+	tables := map[byte]table{}
+	var end uint64
+	for range count {
+		kind, err := r.bytes(1)
+		if err != nil {
+			return ModuleRef{}, nil, err
+		}
+		offset, err := r.uleb()
+		if err != nil {
+			return ModuleRef{}, nil, err
+		}
+		length, err := r.uleb()
+		if err != nil {
+			return ModuleRef{}, nil, err
+		}
+		if offset > math.MaxUint64-length {
+			return ModuleRef{}, nil, fmt.Errorf("table %#x: offset %d and length %d overflow", kind[0], offset, length)
+		}
+		tables[kind[0]] = table{offset, length}
+		end = max(end, offset+length)
+	}
+	content, err := r.bytes(end)
+	if err != nil {
+		return ModuleRef{}, nil, err
+	}
+	section := func(kind byte) (*moveReader, error) {
+		t := tables[kind]
+		if t.offset+t.length > uint64(len(content)) {
+			return nil, fmt.Errorf("table %#x: bytes %d to %d outside %d bytes of content", kind, t.offset, t.offset+t.length, len(content))
+		}
+		return &moveReader{b: content[t.offset : t.offset+t.length]}, nil
+	}
+	identTable, err := section(moveTableIdentifiers)
+	if err != nil {
+		return ModuleRef{}, nil, err
+	}
+	addrTable, err := section(moveTableAddresses)
+	if err != nil {
+		return ModuleRef{}, nil, err
+	}
+	handleTable, err := section(moveTableModuleHandles)
+	if err != nil {
+		return ModuleRef{}, nil, err
+	}
+
+	var idents []string
+	for s := identTable; s.pos < len(s.b); {
+		n, err := s.uleb()
+		if err != nil {
+			return ModuleRef{}, nil, err
+		}
+		name, err := s.bytes(n)
+		if err != nil {
+			return ModuleRef{}, nil, err
+		}
+		idents = append(idents, string(name))
+	}
+
+	addrs := addrTable.b
+	if len(addrs)%moveAddressLength != 0 {
+		return ModuleRef{}, nil, fmt.Errorf("address table of %d bytes", len(addrs))
+	}
+	for s := handleTable; s.pos < len(s.b); {
+		a, err := s.uleb()
+		if err != nil {
+			return ModuleRef{}, nil, err
+		}
+		n, err := s.uleb()
+		if err != nil {
+			return ModuleRef{}, nil, err
+		}
+		if a >= uint64(len(addrs)/moveAddressLength) || n >= uint64(len(idents)) {
+			return ModuleRef{}, nil, fmt.Errorf("module handle %d out of range", len(refs))
+		}
+		ref := ModuleRef{Name: idents[n]}
+		copy(ref.Address[:], addrs[a*moveAddressLength:])
+		refs = append(refs, ref)
+	}
+
+	var selfIdx uint64
+	if version >= moveVersionSelfIndex {
+		if selfIdx, err = r.uleb(); err != nil {
+			return ModuleRef{}, nil, err
+		}
+	}
+	if selfIdx >= uint64(len(refs)) {
+		return ModuleRef{}, nil, errors.New("module has no self handle")
+	}
+	return refs[selfIdx], refs, nil
+}
+
+// ReferencedPackages returns the original IDs of the packages p's modules
+// refer to, other than p itself, sorted.
+func (p *CompiledPackage) ReferencedPackages() ([]ObjectID, error) {
+	self := map[ObjectID]bool{}
+	seen := map[ObjectID]bool{}
+	for i, m := range p.Modules {
+		s, refs, err := ParseModuleRefs(m)
+		if err != nil {
+			return nil, fmt.Errorf("module %d: %w", i, err)
+		}
+		self[s.Address] = true
+		for _, r := range refs {
+			seen[r.Address] = true
+		}
+	}
+	var ids []ObjectID
+	for id := range seen {
+		if !self[id] {
+			ids = append(ids, id)
+		}
+	}
+	sortObjectIDs(ids)
+	return ids, nil
+}
+
+func sortObjectIDs(ids []ObjectID) {
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+}
+
+// linkedVersion is the storage ID chosen for an original package ID.
+type linkedVersion struct {
+	id      ObjectID
+	version uint64
+}
+
+// ResolveDependencies computes the dependency list for publishing or
+// upgrading pkg: the latest version of every package its bytecode refers
+// to (plus 0x1 and 0x2), and every package those link against, each at the
+// newest version any of them requires.  The result holds storage IDs,
+// sorted.
+func ResolveDependencies(conn *grpc.ClientConn, pkg *CompiledPackage, ctx context.Context) ([]ObjectID, error) {
+	direct, err := pkg.ReferencedPackages()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range []ObjectID{MoveStdlibPackageID, SuiFrameworkPackageID} {
+		if !slices.Contains(direct, id) {
+			direct = append(direct, id)
+		}
+	}
+
+	client := pb.NewMovePackageServiceClient(conn)
+	linked := map[ObjectID]linkedVersion{}
+	link := func(original ObjectID, v linkedVersion) {
+		if cur, ok := linked[original]; !ok || v.version > cur.version {
+			linked[original] = v
+		}
+	}
+	for _, original := range direct {
+		latest, err := latestPackageVersion(client, original, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("dependency %s: %w", original.Short(), err)
+		}
+		link(original, latest)
+
+		id := latest.id.String()
+		resp, err := client.GetPackage(ctx, &pb.GetPackageRequest{PackageId: &id})
+		if err != nil {
+			return nil, fmt.Errorf("dependency %s: %w", original.Short(), err)
+		}
+		for _, l := range resp.GetPackage().GetLinkage() {
+			orig, err := ParseObjectID(l.GetOriginalId())
+			if err != nil {
+				return nil, fmt.Errorf("linkage of %s: %w", original.Short(), err)
+			}
+			upgraded, err := ParseObjectID(l.GetUpgradedId())
+			if err != nil {
+				return nil, fmt.Errorf("linkage of %s: %w", original.Short(), err)
+			}
+			link(orig, linkedVersion{upgraded, l.GetUpgradedVersion()})
+		}
+	}
+
+	deps := make([]ObjectID, 0, len(linked))
+	for _, v := range linked {
+		deps = append(deps, v.id)
+	}
+	sortObjectIDs(deps)
+	return deps, nil
+}
+
+// latestPackageVersion pages through ListPackageVersions for the highest
+// version of the package with the given original ID.
+func latestPackageVersion(client pb.MovePackageServiceClient, original ObjectID, ctx context.Context) (linkedVersion, error) {
+	id := original.String()
+	req := &pb.ListPackageVersionsRequest{PackageId: &id}
+	latest := linkedVersion{id: original}
+	for {
+		resp, err := client.ListPackageVersions(ctx, req)
+		if err != nil {
+			return linkedVersion{}, err
+		}
+		for _, v := range resp.GetVersions() {
+			if v.GetVersion() < latest.version {
+				continue
+			}
+			storage, err := ParseObjectID(v.GetPackageId())
+			if err != nil {
+				return linkedVersion{}, err
+			}
+			latest = linkedVersion{storage, v.GetVersion()}
+		}
+		if len(resp.GetNextPageToken()) == 0 {
+			return latest, nil
+		}
+		req.PageToken = resp.GetNextPageToken()
+	}
+}
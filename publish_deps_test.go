@@ -0,0 +1,100 @@
+package gosuisdk
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// testdata/modules holds two modules hand-assembled in the Move binary
+// format, both declaring 0xc0ffee::pool with handles for itself, 0x2::coin
+// and 0x1::vector:
+//
+//   - pool_v6.mv is version 6 and names its self handle (index 1) in the
+//     trailing self_module_handle_idx
+//   - pool_v4.mv is version 4, before that index, so its first handle is
+//     itself
+//
+// Each carries the identifier, address, module handle, function handle,
+// signature and function definition tables of a one-function module.
+
+func readModule(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", "modules", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestParseModuleRefs(t *testing.T) {
+	var (
+		pool   = ModuleRef{mustObjectID("0xc0ffee"), "pool"}
+		coin   = ModuleRef{SuiFrameworkPackageID, "coin"}
+		vector = ModuleRef{MoveStdlibPackageID, "vector"}
+	)
+	tests := []struct {
+		file string
+		refs []ModuleRef
+	}{
+		{"pool_v6.mv", []ModuleRef{coin, pool, vector}},
+		{"pool_v4.mv", []ModuleRef{pool, coin, vector}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			self, refs, err := ParseModuleRefs(readModule(t, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if self != pool {
+				t.Errorf("self = %s, want %s", self, pool)
+			}
+			if !slices.Equal(refs, tt.refs) {
+				t.Errorf("refs = %v, want %v", refs, tt.refs)
+			}
+		})
+	}
+
+	pkg := &CompiledPackage{Modules: [][]byte{readModule(t, "pool_v6.mv"), readModule(t, "pool_v4.mv")}}
+	ids, err := pkg.ReferencedPackages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []ObjectID{MoveStdlibPackageID, SuiFrameworkPackageID}; !slices.Equal(ids, want) {
+		t.Errorf("ReferencedPackages = %v, want %v", ids, want)
+	}
+}
+
+func TestParseModuleRefsMalformed(t *testing.T) {
+	v6 := readModule(t, "pool_v6.mv")
+
+	// Every truncation fails cleanly; the last byte is the self index.
+	for n := range len(v6) {
+		if _, _, err := ParseModuleRefs(v6[:n]); err == nil {
+			t.Errorf("truncated to %d bytes: accepted", n)
+		}
+	}
+
+	header := []byte{0xa1, 0x1c, 0xeb, 0x0b, 6, 0, 0, 0}
+	tests := map[string][]byte{
+		"bad magic": append([]byte{0xde, 0xad, 0xbe, 0xef}, v6[4:]...),
+		// Identifiers at offset 2^64-1, length 2: the end wraps to 1.
+		"offset overflow": append(slices.Clone(header),
+			1, moveTableIdentifiers, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 2, 'a', 'b', 'c'),
+		"length overflow": append(slices.Clone(header),
+			1, moveTableIdentifiers, 2, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 'a', 'b', 'c'),
+		"table past content": append(slices.Clone(header),
+			1, moveTableIdentifiers, 0, 8, 1, 'a'),
+		// One handle naming address 1 of a one-address table.
+		"handle out of range": slices.Concat(header,
+			[]byte{3, moveTableModuleHandles, 0, 2, moveTableIdentifiers, 2, 2, moveTableAddresses, 4, 32},
+			[]byte{1, 0, 1, 'a'}, make([]byte, 32), []byte{0}),
+		"self index out of range": append(slices.Clone(v6[:len(v6)-1]), 3),
+	}
+	for name, b := range tests {
+		if _, _, err := ParseModuleRefs(b); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
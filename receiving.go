@@ -0,0 +1,204 @@
+// receiving.go
+//
+// Claiming objects sent to another object (transfer-to-object).
+//
+// An object transferred to an object ID is owned by that object and can only
+// enter a transaction as a Receiving<T> input, passed to a function of the
+// parent's module that calls 0x2::transfer::receive on the parent's UID:
+//
+//	coin := typetag.Coin(typetag.SUI)
+//	objs, err := gosuisdk.ListReceivable(conn, vault, &coin, ctx)
+//	resp, err := (&gosuisdk.ReceiveObjects{
+//		Sender: sender, Parent: vault, Objects: objs,
+//		Package: pkg, Module: "vault", Function: "withdraw", TypeArgs: []typetag.TypeTag{typetag.SUI},
+//		Recipient: sender, Gasbudget: 50_000_000, Gasprice: 1_000,
+//	}).SignExecuteTx(conn, backend, account, ctx)
+
+package gosuisdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/block-vision/sui-go-sdk/signer"
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/typetag"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// ListReceivable pages through the objects owned by the object parent,
+// with the version and digest needed to receive them.  objectType filters
+// the listing when non-nil; a type without type parameters matches every
+// instantiation.
+func ListReceivable(conn *grpc.ClientConn, parent ObjectID, objectType *typetag.StructTag, ctx context.Context) ([]*pb.Object, error) {
+	var filter *string
+	if objectType != nil {
+		t := objectType.String()
+		filter = &t
+	}
+
+	client := pb.NewStateServiceClient(conn)
+	owner := parent.String()
+	pageSize := uint32(1000)
+	var token []byte
+	var objs []*pb.Object
+	for {
+		resp, err := client.ListOwnedObjects(ctx, &pb.ListOwnedObjectsRequest{
+			Owner:      &owner,
+			PageSize:   &pageSize,
+			PageToken:  token,
+			ObjectType: filter,
+			ReadMask: &fieldmaskpb.FieldMask{
+				Paths: []string{"object_id", "version", "digest", "object_type", "balance"},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, resp.GetObjects()...)
+		token = resp.GetNextPageToken()
+		if len(token) == 0 {
+			return objs, nil
+		}
+	}
+}
+
+// ReceivingInput pushes obj, an object owned by another object, as a
+// Receiving<T> input of b.
+func ReceivingInput(b TxBuilder, obj ObjectRefGetter) (uint64, error) {
+	id, version, digest, err := ObjectRefOf(obj)
+	if err != nil {
+		return 0, err
+	}
+	return b.InputObject(id, version, digest, ObjectKindReceiving, false)
+}
+
+// ReceiveObjects claims objects sent to Parent in one transaction, calling
+//
+//	Package::Module::Function<TypeArgs>(parent, Receiving<T>)
+//
+// once per object.  Parent may be shared or owned by Sender; it is resolved
+// by ID, and a shared parent is taken mutably when Function takes &mut.
+type ReceiveObjects struct {
+	Sender Address
+	Parent ObjectID
+
+	Package  ObjectID
+	Module   string
+	Function string
+	TypeArgs []typetag.TypeTag
+
+	// Objects are the objects to claim, as listed by ListReceivable.  When
+	// empty, every object Parent owns of ObjectType is claimed.
+	Objects    []*pb.Object
+	ObjectType *typetag.StructTag
+
+	// Recipient, when set, receives what each call returns.  Leave it zero
+	// when Function returns nothing.
+	Recipient Address
+
+	Gasbudget uint64
+	Gasprice  uint64
+
+	// GasCoins pays for gas.  When empty, the largest SUI coins covering
+	// Gasbudget are selected.
+	GasCoins []*pb.Object
+}
+
+func (r *ReceiveObjects) buildTx(conn *grpc.ClientConn, backend Backend, budget uint64, objs, gas []*pb.Object, ctx context.Context) ([]byte, error) {
+	b := NewIntentBuilder(conn, backend, ctx)
+	if err := r.fill(b, budget, objs, gas); err != nil {
+		b.Free()
+		return nil, err
+	}
+	return b.Build()
+}
+
+func (r *ReceiveObjects) fill(b *IntentBuilder, budget uint64, objs, gas []*pb.Object) error {
+	if err := b.SetConfig(r.Sender, budget, r.Gasprice); err != nil {
+		return err
+	}
+	for _, g := range gas {
+		if err := addGasObject(b, g); err != nil {
+			return err
+		}
+	}
+	parent, err := b.InputObjectByID(r.Parent)
+	if err != nil {
+		return err
+	}
+
+	results := make([]uint64, 0, len(objs))
+	for _, obj := range objs {
+		recv, err := ReceivingInput(b, obj)
+		if err != nil {
+			return err
+		}
+		res, err := b.MoveCall(r.Package, r.Module, r.Function, r.TypeArgs,
+			[]MoveCallArg{ArgID(parent), ArgID(recv)})
+		if err != nil {
+			return fmt.Errorf("receive %s: %w", obj.GetObjectId(), err)
+		}
+		results = append(results, res)
+	}
+	if r.Recipient.IsZero() {
+		return nil
+	}
+
+	recipient, err := b.PureAddress(r.Recipient)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(results); start += MaxArguments - 1 {
+		end := min(start+MaxArguments-1, len(results))
+		if err := b.TransferObjects(results[start:end], recipient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxReceivable returns how many objects one claim can take.  Each object
+// is an input next to the parent and the gas coins, and costs a MoveCall;
+// with transfer, the results add one TransferObjects per MaxArguments-1.
+func maxReceivable(gas int, transfer bool) int {
+	inputs := MaxInputObjects - 1 - gas
+	commands := MaxCommands
+	if transfer {
+		// n + ceil(n/k) <= MaxCommands  <=>  n <= MaxCommands*k/(k+1)
+		k := MaxArguments - 1
+		commands = MaxCommands * k / (k + 1)
+	}
+	return max(min(inputs, commands), 0)
+}
+
+// SignExecuteTx lists the objects to claim when none are given, then
+// simulates, signs and executes the claim.
+func (r *ReceiveObjects) SignExecuteTx(conn *grpc.ClientConn, backend Backend, account *signer.Signer, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
+	if r.Module == "" || r.Function == "" {
+		return nil, errors.New("receive: Module and Function are required")
+	}
+	objs := r.Objects
+	if len(objs) == 0 {
+		var err error
+		if objs, err = ListReceivable(conn, r.Parent, r.ObjectType, ctx); err != nil {
+			return nil, err
+		}
+	}
+	if len(objs) == 0 {
+		return nil, fmt.Errorf("receive: %s owns no objects to receive", r.Parent)
+	}
+
+	gas, err := selectGasCoins(conn, r.Sender, r.Gasbudget, r.GasCoins, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if limit := maxReceivable(len(gas), !r.Recipient.IsZero()); len(objs) > limit {
+		return nil, fmt.Errorf("receive: %d objects, at most %d per transaction with %d gas coins", len(objs), limit, len(gas))
+	}
+	return estimateSignExecute(conn, account, r.Gasbudget, func(budget uint64) ([]byte, error) {
+		return r.buildTx(conn, backend, budget, objs, gas, ctx)
+	}, nil, ctx)
+}
@@ -0,0 +1,31 @@
+package gosuisdk
+
+import "testing"
+
+func TestMaxReceivable(t *testing.T) {
+	// commands and inputs a claim of n objects needs
+	cost := func(n, gas int, transfer bool) (commands, inputs int) {
+		commands = n
+		if transfer {
+			commands += (n + MaxArguments - 2) / (MaxArguments - 1)
+		}
+		return commands, 1 + gas + n
+	}
+	for _, tt := range []struct {
+		gas      int
+		transfer bool
+	}{
+		{1, false},
+		{1, true},
+		{MaxGasObjects, false},
+		{MaxGasObjects, true},
+	} {
+		n := maxReceivable(tt.gas, tt.transfer)
+		if c, i := cost(n, tt.gas, tt.transfer); c > MaxCommands || i > MaxInputObjects {
+			t.Errorf("maxReceivable(%d, %v) = %d: %d commands, %d inputs", tt.gas, tt.transfer, n, c, i)
+		}
+		if c, i := cost(n+1, tt.gas, tt.transfer); c <= MaxCommands && i <= MaxInputObjects {
+			t.Errorf("maxReceivable(%d, %v) = %d, but %d fits", tt.gas, tt.transfer, n, n+1)
+		}
+	}
+}
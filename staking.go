@@ -0,0 +1,336 @@
+// staking.go
+//
+// Native staking helpers for the Sui system package (0x3).
+//
+// Staking and withdrawing are plain Move calls against the shared
+// SuiSystemState object (0x5):
+//
+//	0x3::sui_system::request_add_stake(&mut SuiSystemState, Coin<SUI>, address)
+//	0x3::sui_system::request_withdraw_stake(&mut SuiSystemState, StakedSui)
+//
+// Validator metadata and the inputs of the APY estimate are read from the
+// SystemState carried in GetEpochResponse, so dashboards and automation
+// work from the same numbers.
+
+package gosuisdk
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/block-vision/sui-go-sdk/signer"
+	"github.com/pictorx/go-sui-sdk/bcs"
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/typetag"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// SuiSystemStateInitialVersion is the version 0x5 was shared at.
+const SuiSystemStateInitialVersion = 1
+
+// StakedSuiType is the Move type of a stake receipt.
+var StakedSuiType = typetag.MustParse("0x3::staking_pool::StakedSui")
+
+const (
+	msPerYear       = 365 * 24 * 60 * 60 * 1000
+	basisPointsBase = 10_000
+)
+
+// GetSystemState fetches the current epoch together with its SystemState.
+func GetSystemState(conn *grpc.ClientConn, ctx context.Context) (*pb.GetEpochResponse, error) {
+	client := pb.NewLedgerServiceClient(conn)
+	resp, err := client.GetEpoch(ctx, &pb.GetEpochRequest{
+		ReadMask: &fieldmaskpb.FieldMask{
+			Paths: []string{"epoch", "reference_gas_price", "system_state"},
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ── Builder helpers ───────────────────────────────────────────────────────────
+
+// systemStateArg pushes the mutable shared 0x5 object.
+func systemStateArg(b TxBuilder) (uint64, error) {
+	return b.InputObject(SuiSystemStateObjectID, SuiSystemStateInitialVersion, Digest{}, ObjectKindShared, true)
+}
+
+// RequestAddStake appends 0x3::sui_system::request_add_stake, staking the
+// coin identified by coinArgID with validator.
+func RequestAddStake(b TxBuilder, coinArgID uint64, validator Address) (uint64, error) {
+	state, err := systemStateArg(b)
+	if err != nil {
+		return 0, err
+	}
+	validatorArg, err := b.PureAddress(validator)
+	if err != nil {
+		return 0, err
+	}
+	return b.MoveCall(SuiSystemPackageID, "sui_system", "request_add_stake", nil,
+		[]MoveCallArg{ArgID(state), ArgID(coinArgID), ArgID(validatorArg)})
+}
+
+// RequestWithdrawStake appends 0x3::sui_system::request_withdraw_stake for
+// the given StakedSui object.  The principal and rewards are sent back to
+// the sender.
+func RequestWithdrawStake(b TxBuilder, stakedSui *pb.Object) (uint64, error) {
+	state, err := systemStateArg(b)
+	if err != nil {
+		return 0, err
+	}
+	staked, err := inputOwned(b, stakedSui)
+	if err != nil {
+		return 0, err
+	}
+	return b.MoveCall(SuiSystemPackageID, "sui_system", "request_withdraw_stake", nil,
+		[]MoveCallArg{ArgID(state), ArgID(staked)})
+}
+
+// ── Transactions ──────────────────────────────────────────────────────────────
+
+// AddStake stakes Amount MIST, split off the gas coin, with Validator.
+type AddStake struct {
+	Sender    Address
+	Validator Address
+	Amount    uint64
+	Gasbudget uint64
+	Gasprice  uint64
+
+	// GasCoins pays for gas and the stake.  When empty, the largest SUI
+	// coins covering Amount plus Gasbudget are selected.
+	GasCoins []*pb.Object
+}
+
+func (s *AddStake) buildTx(b TxBuilder) error {
+	gasArg, err := b.GasArgument()
+	if err != nil {
+		return err
+	}
+	amt, err := b.PureU64(s.Amount)
+	if err != nil {
+		return err
+	}
+	res, err := b.SplitCoins(gasArg, []uint64{amt})
+	if err != nil {
+		return err
+	}
+	coin, err := b.NestedResult(res, 0)
+	if err != nil {
+		return err
+	}
+	_, err = RequestAddStake(b, coin, s.Validator)
+	return err
+}
+
+func (s *AddStake) SignExecuteTx(conn *grpc.ClientConn, backend Backend, account *signer.Signer, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
+	if s.Amount > math.MaxUint64-s.Gasbudget {
+		return nil, fmt.Errorf("stake: Amount %d plus Gasbudget %d overflows u64", s.Amount, s.Gasbudget)
+	}
+	gas, err := selectGasCoins(conn, s.Sender, s.Amount+s.Gasbudget, s.GasCoins, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return estimateSignExecute(conn, account, s.Gasbudget, func(budget uint64) ([]byte, error) {
+		return withBuilder(backend, s.Sender, budget, s.Gasprice, gas, s.buildTx)
+	}, nil, ctx)
+}
+
+// WithdrawStake withdraws a StakedSui object (as listed by ListStakes or
+// GetObject) back to the sender.
+type WithdrawStake struct {
+	Sender    Address
+	StakedSui *pb.Object
+	Gasbudget uint64
+	Gasprice  uint64
+
+	// GasCoins pays for gas.  When empty, the largest SUI coins covering
+	// Gasbudget are selected.
+	GasCoins []*pb.Object
+}
+
+func (w *WithdrawStake) buildTx(b TxBuilder) error {
+	_, err := RequestWithdrawStake(b, w.StakedSui)
+	return err
+}
+
+func (w *WithdrawStake) SignExecuteTx(conn *grpc.ClientConn, backend Backend, account *signer.Signer, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
+	gas, err := selectGasCoins(conn, w.Sender, w.Gasbudget, w.GasCoins, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return estimateSignExecute(conn, account, w.Gasbudget, func(budget uint64) ([]byte, error) {
+		return withBuilder(backend, w.Sender, budget, w.Gasprice, gas, w.buildTx)
+	}, nil, ctx)
+}
+
+// ── Validators ────────────────────────────────────────────────────────────────
+
+// ValidatorInfo summarises an active validator from the SystemState.
+type ValidatorInfo struct {
+	Name           string
+	Address        Address
+	StakingPoolID  ObjectID
+	VotingPower    uint64 // out of 10_000
+	CommissionRate uint64 // basis points
+	GasPrice       uint64
+	Stake          uint64 // SUI held by the staking pool, in MIST
+	APY            float64
+}
+
+// epochsPerYear derives the number of epochs per year from the epoch length.
+func epochsPerYear(state *pb.SystemState) float64 {
+	duration := state.GetParameters().GetEpochDurationMs()
+	if duration == 0 {
+		return 0
+	}
+	return float64(msPerYear) / float64(duration)
+}
+
+// epochRewardRate estimates the per-epoch return of staking with v: the
+// validator's voting-power share of the stake subsidy, net of commission,
+// over the SUI in its pool.  Computation-fee rewards are not included, so
+// the estimate is conservative.
+func epochRewardRate(state *pb.SystemState, v *pb.Validator) float64 {
+	stake := v.GetStakingPool().GetSuiBalance()
+	if stake == 0 {
+		return 0
+	}
+	subsidy := float64(state.GetStakeSubsidy().GetCurrentDistributionAmount())
+	share := float64(v.GetVotingPower()) / basisPointsBase
+	commission := float64(v.GetCommissionRate()) / basisPointsBase
+	return subsidy * share * (1 - commission) / float64(stake)
+}
+
+// Validators lists the active validators in epoch with an estimated APY.
+// epoch must come from GetSystemState (or a GetEpoch call whose read mask
+// includes system_state).
+func Validators(epoch *pb.GetEpochResponse) ([]ValidatorInfo, error) {
+	state := epoch.GetEpoch().GetSystemState()
+	if state == nil {
+		return nil, fmt.Errorf("epoch response has no system_state; use GetSystemState")
+	}
+
+	n := epochsPerYear(state)
+	var out []ValidatorInfo
+	for _, v := range state.GetValidators().GetActiveValidators() {
+		addr, err := ParseAddress(v.GetAddress())
+		if err != nil {
+			return nil, fmt.Errorf("validator %s: %w", v.GetName(), err)
+		}
+		pool, err := ParseObjectID(v.GetStakingPool().GetId())
+		if err != nil {
+			return nil, fmt.Errorf("validator %s: staking pool: %w", v.GetName(), err)
+		}
+		out = append(out, ValidatorInfo{
+			Name:           v.GetName(),
+			Address:        addr,
+			StakingPoolID:  pool,
+			VotingPower:    v.GetVotingPower(),
+			CommissionRate: v.GetCommissionRate(),
+			GasPrice:       v.GetGasPrice(),
+			Stake:          v.GetStakingPool().GetSuiBalance(),
+			APY:            math.Pow(1+epochRewardRate(state, v), n) - 1,
+		})
+	}
+	return out, nil
+}
+
+// ── Stakes ────────────────────────────────────────────────────────────────────
+
+// StakeInfo describes one StakedSui object.
+type StakeInfo struct {
+	Object          *pb.Object
+	PoolID          ObjectID
+	Validator       Address // zero if the pool's validator is no longer active
+	ActivationEpoch uint64
+	Principal       uint64
+	Active          bool // the stake has started earning rewards
+
+	// EstimatedReward compounds the validator's current per-epoch rate over
+	// the epochs since activation.  The exact amount depends on the pool's
+	// historical exchange rates and is only known on withdrawal.
+	EstimatedReward uint64
+}
+
+// stakedSui mirrors the contents of 0x3::staking_pool::StakedSui; the
+// principal is a Balance<SUI>, a struct holding one u64.
+type stakedSui struct {
+	ID                   ObjectID
+	PoolID               ObjectID
+	StakeActivationEpoch uint64
+	Principal            uint64
+}
+
+// ListStakes lists owner's StakedSui objects with estimated rewards, using
+// the validator set from epoch (see GetSystemState).
+func ListStakes(conn *grpc.ClientConn, owner Address, epoch *pb.GetEpochResponse, ctx context.Context) ([]StakeInfo, error) {
+	state := epoch.GetEpoch().GetSystemState()
+	if state == nil {
+		return nil, fmt.Errorf("epoch response has no system_state; use GetSystemState")
+	}
+	pools := map[ObjectID]*pb.Validator{}
+	for _, v := range state.GetValidators().GetActiveValidators() {
+		if id, err := ParseObjectID(v.GetStakingPool().GetId()); err == nil {
+			pools[id] = v
+		}
+	}
+	current := epoch.GetEpoch().GetEpoch()
+
+	client := pb.NewStateServiceClient(conn)
+	o := owner.String()
+	objectType := StakedSuiType.String()
+	pageSize := uint32(1000)
+	var token []byte
+	var stakes []StakeInfo
+	for {
+		resp, err := client.ListOwnedObjects(ctx, &pb.ListOwnedObjectsRequest{
+			Owner:      &o,
+			PageSize:   &pageSize,
+			PageToken:  token,
+			ObjectType: &objectType,
+			ReadMask: &fieldmaskpb.FieldMask{
+				Paths: []string{"object_id", "version", "digest", "object_type", "contents"},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range resp.GetObjects() {
+			var contents stakedSui
+			if err := bcs.Unmarshal(obj.GetContents().GetValue(), &contents); err != nil {
+				return nil, fmt.Errorf("%s: decoding StakedSui: %w", obj.GetObjectId(), err)
+			}
+			activation := contents.StakeActivationEpoch
+			stake := StakeInfo{
+				Object:          obj,
+				PoolID:          contents.PoolID,
+				ActivationEpoch: activation,
+				Principal:       contents.Principal,
+				Active:          activation <= current,
+			}
+			if v, ok := pools[contents.PoolID]; ok {
+				if stake.Validator, err = ParseAddress(v.GetAddress()); err != nil {
+					return nil, fmt.Errorf("validator %s: %w", v.GetName(), err)
+				}
+				if current > activation {
+					rate := epochRewardRate(state, v)
+					growth := math.Pow(1+rate, float64(current-activation)) - 1
+					stake.EstimatedReward = uint64(float64(contents.Principal) * growth)
+				}
+			}
+			stakes = append(stakes, stake)
+		}
+
+		token = resp.GetNextPageToken()
+		if len(token) == 0 {
+			return stakes, nil
+		}
+	}
+}
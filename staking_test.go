@@ -0,0 +1,31 @@
+package gosuisdk
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/pictorx/go-sui-sdk/bcs"
+)
+
+// StakedSui contents are id, pool_id, stake_activation_epoch and
+// principal, with nothing after them.
+func TestStakedSuiContents(t *testing.T) {
+	id, pool := mustObjectID("0x51"), mustObjectID("0x9001")
+	raw := append(append([]byte{}, id[:]...), pool[:]...)
+	raw = binary.LittleEndian.AppendUint64(raw, 17)
+	raw = binary.LittleEndian.AppendUint64(raw, 1_000_000_000)
+
+	var got stakedSui
+	if err := bcs.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := stakedSui{ID: id, PoolID: pool, StakeActivationEpoch: 17, Principal: 1_000_000_000}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	for _, bad := range [][]byte{raw[:79], append(raw, 0)} {
+		if err := bcs.Unmarshal(bad, &got); err == nil {
+			t.Errorf("%d bytes decoded", len(bad))
+		}
+	}
+}
@@ -7,9 +7,10 @@ import (
 
 	"github.com/block-vision/sui-go-sdk/signer"
 	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
-	v2 "github.com/pictorx/go-sui-sdk/v2"
-	"github.com/tetratelabs/wazero/api"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 func GetEpoch(conn *grpc.ClientConn, ctx context.Context) (*pb.GetEpochResponse, error) {
@@ -34,10 +35,11 @@ func GetServiceInfo(conn *grpc.ClientConn, ctx context.Context) (*pb.GetServiceI
 	return resp, nil
 }
 
-func GetObject(conn *grpc.ClientConn, objectId string, version *uint64, ctx context.Context) (*pb.GetObjectResponse, error) {
+func GetObject(conn *grpc.ClientConn, objectId ObjectID, version *uint64, ctx context.Context) (*pb.GetObjectResponse, error) {
 	client := pb.NewLedgerServiceClient(conn)
+	id := objectId.String()
 	resp, err := client.GetObject(ctx, &pb.GetObjectRequest{
-		ObjectId: &objectId,
+		ObjectId: &id,
 		Version:  version,
 	})
 	if err != nil {
@@ -47,10 +49,11 @@ func GetObject(conn *grpc.ClientConn, objectId string, version *uint64, ctx cont
 	return resp, err
 }
 
-func GetTransaction(conn *grpc.ClientConn, digest string, ctx context.Context) (*pb.GetTransactionResponse, error) {
+func GetTransaction(conn *grpc.ClientConn, digest Digest, ctx context.Context) (*pb.GetTransactionResponse, error) {
 	client := pb.NewLedgerServiceClient(conn)
+	d := digest.String()
 	resp, err := client.GetTransaction(ctx, &pb.GetTransactionRequest{
-		Digest: &digest,
+		Digest: &d,
 	})
 	if err != nil {
 		return nil, err
@@ -59,7 +62,7 @@ func GetTransaction(conn *grpc.ClientConn, digest string, ctx context.Context) (
 	return resp, nil
 }
 
-func BatchGetObjects(conn *grpc.ClientConn, objects map[string]*uint64, ctx context.Context) (*pb.BatchGetObjectsResponse, error) {
+func BatchGetObjects(conn *grpc.ClientConn, objects map[ObjectID]*uint64, ctx context.Context) (*pb.BatchGetObjectsResponse, error) {
 	if len(objects) == 0 {
 		return nil, fmt.Errorf("objects cannot be zero")
 	}
@@ -68,8 +71,9 @@ func BatchGetObjects(conn *grpc.ClientConn, objects map[string]*uint64, ctx cont
 	ObjectRequests := []*pb.GetObjectRequest{}
 
 	for objectId, version := range objects {
+		id := objectId.String()
 		ObjectRequests = append(ObjectRequests, &pb.GetObjectRequest{
-			ObjectId: &objectId,
+			ObjectId: &id,
 			Version:  version,
 		})
 	}
@@ -84,14 +88,18 @@ func BatchGetObjects(conn *grpc.ClientConn, objects map[string]*uint64, ctx cont
 	return resp, err
 }
 
-func BatchGetTransactions(conn *grpc.ClientConn, digests []string, ctx context.Context) (*pb.BatchGetTransactionsResponse, error) {
+func BatchGetTransactions(conn *grpc.ClientConn, digests []Digest, ctx context.Context) (*pb.BatchGetTransactionsResponse, error) {
 	if len(digests) == 0 {
 		return nil, fmt.Errorf("digests parameter cannot be empty")
 	}
 
+	ds := make([]string, len(digests))
+	for i, d := range digests {
+		ds[i] = d.String()
+	}
 	client := pb.NewLedgerServiceClient(conn)
 	resp, err := client.BatchGetTransactions(ctx, &pb.BatchGetTransactionsRequest{
-		Digests: digests,
+		Digests: ds,
 	})
 
 	if err != nil {
@@ -101,10 +109,11 @@ func BatchGetTransactions(conn *grpc.ClientConn, digests []string, ctx context.C
 	return resp, err
 }
 
-func GetPackage(conn *grpc.ClientConn, packageId string, ctx context.Context) (*pb.GetPackageResponse, error) {
+func GetPackage(conn *grpc.ClientConn, packageId ObjectID, ctx context.Context) (*pb.GetPackageResponse, error) {
 	client := pb.NewMovePackageServiceClient(conn)
+	pkg := packageId.String()
 	resp, err := client.GetPackage(ctx, &pb.GetPackageRequest{
-		PackageId: &packageId,
+		PackageId: &pkg,
 	})
 
 	if err != nil {
@@ -114,10 +123,11 @@ func GetPackage(conn *grpc.ClientConn, packageId string, ctx context.Context) (*
 	return resp, nil
 }
 
-func GetFunction(conn *grpc.ClientConn, packageId, module, funcName string, ctx context.Context) (*pb.GetFunctionResponse, error) {
+func GetFunction(conn *grpc.ClientConn, packageId ObjectID, module, funcName string, ctx context.Context) (*pb.GetFunctionResponse, error) {
 	client := pb.NewMovePackageServiceClient(conn)
+	pkg := packageId.String()
 	resp, err := client.GetFunction(ctx, &pb.GetFunctionRequest{
-		PackageId:  &packageId,
+		PackageId:  &pkg,
 		ModuleName: &module,
 		Name:       &funcName,
 	})
@@ -129,11 +139,12 @@ func GetFunction(conn *grpc.ClientConn, packageId, module, funcName string, ctx
 	return resp, err
 }
 
-func GetDatatype(conn *grpc.ClientConn, packageId, module, dataTypeName string, ctx context.Context) (*pb.GetDatatypeResponse, error) {
+func GetDatatype(conn *grpc.ClientConn, packageId ObjectID, module, dataTypeName string, ctx context.Context) (*pb.GetDatatypeResponse, error) {
 	client := pb.NewMovePackageServiceClient(conn)
+	pkg := packageId.String()
 
 	resp, err := client.GetDatatype(ctx, &pb.GetDatatypeRequest{
-		PackageId:  &packageId,
+		PackageId:  &pkg,
 		ModuleName: &module,
 		Name:       &dataTypeName,
 	})
@@ -145,11 +156,12 @@ func GetDatatype(conn *grpc.ClientConn, packageId, module, dataTypeName string,
 	return resp, nil
 }
 
-func ListPackageVersions(conn *grpc.ClientConn, packageId string, pagesize *uint32, pagetoken []byte, ctx context.Context) (*pb.ListPackageVersionsResponse, error) {
+func ListPackageVersions(conn *grpc.ClientConn, packageId ObjectID, pagesize *uint32, pagetoken []byte, ctx context.Context) (*pb.ListPackageVersionsResponse, error) {
 	client := pb.NewMovePackageServiceClient(conn)
+	pkg := packageId.String()
 
 	resp, err := client.ListPackageVersions(ctx, &pb.ListPackageVersionsRequest{
-		PackageId: &packageId,
+		PackageId: &pkg,
 		PageSize:  pagesize,
 		PageToken: pagetoken,
 	})
@@ -161,10 +173,12 @@ func ListPackageVersions(conn *grpc.ClientConn, packageId string, pagesize *uint
 	return resp, err
 }
 
-func GetBalance(conn *grpc.ClientConn, owner, cointype string, ctx context.Context) (*pb.GetBalanceResponse, error) {
+func GetBalance(conn *grpc.ClientConn, owner Address, coinType typetag.TypeTag, ctx context.Context) (*pb.GetBalanceResponse, error) {
 	client := pb.NewStateServiceClient(conn)
+	o := owner.String()
+	cointype := coinType.String()
 	resp, err := client.GetBalance(ctx, &pb.GetBalanceRequest{
-		Owner:    &owner,
+		Owner:    &o,
 		CoinType: &cointype,
 	})
 
@@ -175,8 +189,9 @@ func GetBalance(conn *grpc.ClientConn, owner, cointype string, ctx context.Conte
 	return resp, nil
 }
 
-func GetCoinInfo(conn *grpc.ClientConn, cointype string, ctx context.Context) (*pb.GetCoinInfoResponse, error) {
+func GetCoinInfo(conn *grpc.ClientConn, coinType typetag.TypeTag, ctx context.Context) (*pb.GetCoinInfoResponse, error) {
 	client := pb.NewStateServiceClient(conn)
+	cointype := coinType.String()
 	resp, err := client.GetCoinInfo(ctx, &pb.GetCoinInfoRequest{
 		CoinType: &cointype,
 	})
@@ -188,10 +203,11 @@ func GetCoinInfo(conn *grpc.ClientConn, cointype string, ctx context.Context) (*
 	return resp, nil
 }
 
-func ListBalances(conn *grpc.ClientConn, owner string, pagesize *uint32, pagetoken []byte, ctx context.Context) (*pb.ListBalancesResponse, error) {
+func ListBalances(conn *grpc.ClientConn, owner Address, pagesize *uint32, pagetoken []byte, ctx context.Context) (*pb.ListBalancesResponse, error) {
 	client := pb.NewStateServiceClient(conn)
+	o := owner.String()
 	resp, err := client.ListBalances(ctx, &pb.ListBalancesRequest{
-		Owner:     &owner,
+		Owner:     &o,
 		PageSize:  pagesize,
 		PageToken: pagetoken,
 	})
@@ -203,10 +219,11 @@ func ListBalances(conn *grpc.ClientConn, owner string, pagesize *uint32, pagetok
 	return resp, nil
 }
 
-func ListOwnedObjects(conn *grpc.ClientConn, owner string, pagesize *uint32, pagetoken []byte, ctx context.Context) (*pb.ListOwnedObjectsResponse, error) {
+func ListOwnedObjects(conn *grpc.ClientConn, owner Address, pagesize *uint32, pagetoken []byte, ctx context.Context) (*pb.ListOwnedObjectsResponse, error) {
 	client := pb.NewStateServiceClient(conn)
+	o := owner.String()
 	resp, err := client.ListOwnedObjects(ctx, &pb.ListOwnedObjectsRequest{
-		Owner:     &owner,
+		Owner:     &o,
 		PageSize:  pagesize,
 		PageToken: pagetoken,
 	})
@@ -218,10 +235,34 @@ func ListOwnedObjects(conn *grpc.ClientConn, owner string, pagesize *uint32, pag
 	return resp, nil
 }
 
-func ListDynamicFields(conn *grpc.ClientConn, objectId string, pagesize *uint32, pagetoken []byte, ctx context.Context) (*pb.ListDynamicFieldsResponse, error) {
+// ListOwnedCoins lists the Coin<coinType> objects owned by owner, including
+// the digest and balance needed to use them as transaction inputs.
+func ListOwnedCoins(conn *grpc.ClientConn, owner Address, coinType typetag.TypeTag, pagesize *uint32, pagetoken []byte, ctx context.Context) (*pb.ListOwnedObjectsResponse, error) {
+	objectType := Coin{Type: coinType}.String()
+	client := pb.NewStateServiceClient(conn)
+	o := owner.String()
+	resp, err := client.ListOwnedObjects(ctx, &pb.ListOwnedObjectsRequest{
+		Owner:      &o,
+		PageSize:   pagesize,
+		PageToken:  pagetoken,
+		ObjectType: &objectType,
+		ReadMask: &fieldmaskpb.FieldMask{
+			Paths: []string{"object_id", "version", "digest", "object_type", "balance"},
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func ListDynamicFields(conn *grpc.ClientConn, objectId ObjectID, pagesize *uint32, pagetoken []byte, ctx context.Context) (*pb.ListDynamicFieldsResponse, error) {
 	client := pb.NewStateServiceClient(conn)
+	parent := objectId.String()
 	resp, err := client.ListDynamicFields(ctx, &pb.ListDynamicFieldsRequest{
-		Parent:    &objectId,
+		Parent:    &parent,
 		PageSize:  pagesize,
 		PageToken: pagetoken,
 	})
@@ -233,14 +274,19 @@ func ListDynamicFields(conn *grpc.ClientConn, objectId string, pagesize *uint32,
 	return resp, nil
 }
 
-func OwnedCoins(listownedobjects *pb.ListOwnedObjectsResponse, cointype, owner string) []*pb.Object {
+// OwnedCoins filters the listed objects to those of type cointype, e.g.
+// SuiCoin.Tag().  Types are compared structurally, so short and full-length
+// addresses match; objects whose type does not parse are skipped.
+func OwnedCoins(listownedobjects *pb.ListOwnedObjectsResponse, cointype typetag.TypeTag) []*pb.Object {
 	list := listownedobjects
 
 	var coins []*pb.Object
 	for _, v := range list.GetObjects() {
-
-		if *v.ObjectType == cointype {
-
+		if v.ObjectType == nil {
+			continue
+		}
+		t, err := typetag.Parse(v.GetObjectType())
+		if err == nil && t.Equal(cointype) {
 			coins = append(coins, v)
 		}
 	}
@@ -248,7 +294,7 @@ func OwnedCoins(listownedobjects *pb.ListOwnedObjectsResponse, cointype, owner s
 	return coins
 }
 
-func GasPayment(coins []*pb.Object, owner string, price uint64, budget uint64) *pb.GasPayment {
+func GasPayment(coins []*pb.Object, owner Address, price uint64, budget uint64) *pb.GasPayment {
 	coinObjects := []*pb.ObjectReference{}
 	for _, coin := range coins {
 		coinObjects = append(coinObjects, &pb.ObjectReference{
@@ -257,26 +303,39 @@ func GasPayment(coins []*pb.Object, owner string, price uint64, budget uint64) *
 			Digest:   coin.Digest,
 		})
 	}
+	o := owner.String()
 	return &pb.GasPayment{
 		Objects: coinObjects,
-		Owner:   &owner,
+		Owner:   &o,
 		Price:   &price,
 		Budget:  &budget,
 	}
 }
 
+// Coin is the object type 0x2::coin::Coin<Type>.
 type Coin struct {
-	Type string
+	Type typetag.TypeTag
 }
 
-func (c *Coin) String() string {
-	prefix := "0x0000000000000000000000000000000000000000000000000000000000000002::coin::Coin"
-	return prefix + "<" + c.Type + ">"
+// CoinOf parses cointype, e.g. "0x2::sui::SUI", into a Coin.
+func CoinOf(cointype string) (Coin, error) {
+	t, err := typetag.Parse(cointype)
+	if err != nil {
+		return Coin{}, fmt.Errorf("coin type: %w", err)
+	}
+	return Coin{Type: t}, nil
 }
 
-var SuiCoin Coin = Coin{
-	Type: "0x0000000000000000000000000000000000000000000000000000000000000002::sui::SUI",
-}
+// StructTag returns 0x2::coin::Coin<Type>.
+func (c Coin) StructTag() typetag.StructTag { return typetag.Coin(c.Type) }
+
+// Tag returns 0x2::coin::Coin<Type> as a TypeTag.
+func (c Coin) Tag() typetag.TypeTag { return c.StructTag().Tag() }
+
+// String renders the canonical object type, with full-length addresses.
+func (c Coin) String() string { return c.StructTag().String() }
+
+var SuiCoin Coin = Coin{Type: typetag.SUI}
 
 func VerifySignature(conn *grpc.ClientConn, txBytes, signature []byte, ctx context.Context) {
 	client := pb.NewSignatureVerificationServiceClient(conn)
@@ -300,6 +359,16 @@ var schemeMap = map[byte]pb.SignatureScheme{
 }
 
 func SignExecuteTransaction(conn *grpc.ClientConn, txBytes, signature []byte, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
+	return executeTransaction(conn, txBytes, signature, nil, ctx)
+}
+
+// SignExecuteTransactionWithMask is SignExecuteTransaction with an explicit
+// read mask, e.g. []string{"effects"} to get the changed object references.
+func SignExecuteTransactionWithMask(conn *grpc.ClientConn, txBytes, signature []byte, readMask []string, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
+	return executeTransaction(conn, txBytes, signature, readMask, ctx)
+}
+
+func executeTransaction(conn *grpc.ClientConn, txBytes, signature []byte, readMask []string, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
 	// The serialized signature format is: [flag: 1 byte][sig: 64 bytes][pubkey: 32 bytes]
 	if len(signature) != 97 {
 		return nil, fmt.Errorf("invalid signature length: expected 97, got %d", len(signature))
@@ -315,6 +384,11 @@ func SignExecuteTransaction(conn *grpc.ClientConn, txBytes, signature []byte, ct
 		return nil, fmt.Errorf("Unsupported signature scheme flag: 0x%02x", flagByte)
 	}
 
+	var mask *fieldmaskpb.FieldMask
+	if len(readMask) > 0 {
+		mask = &fieldmaskpb.FieldMask{Paths: readMask}
+	}
+
 	client := pb.NewTransactionExecutionServiceClient(conn)
 	resp, err := client.ExecuteTransaction(ctx, &pb.ExecuteTransactionRequest{
 		Transaction: &pb.Transaction{
@@ -332,6 +406,7 @@ func SignExecuteTransaction(conn *grpc.ClientConn, txBytes, signature []byte, ct
 				},
 			},
 		},
+		ReadMask: mask,
 	})
 	if err != nil {
 		return nil, err
@@ -339,6 +414,87 @@ func SignExecuteTransaction(conn *grpc.ClientConn, txBytes, signature []byte, ct
 	return resp, nil
 }
 
+// signAndExecute signs txBytes with account and executes it, reading back
+// the fields named in readMask.
+func signAndExecute(conn *grpc.ClientConn, txBytes []byte, account *signer.Signer, readMask []string, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
+	signed, err := SignTransaction(txBytes, account)
+	if err != nil {
+		return nil, err
+	}
+
+	txBytesRaw, err := base64.StdEncoding.DecodeString(signed.TxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signatureRaw, err := base64.StdEncoding.DecodeString(signed.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return executeTransaction(conn, txBytesRaw, signatureRaw, readMask, ctx)
+}
+
+// estimateSignExecute builds the transaction with the initial budget,
+// simulates it, rebuilds it with the estimated budget, then signs and
+// executes it.
+func estimateSignExecute(conn *grpc.ClientConn, account *signer.Signer, budget uint64, build func(budget uint64) ([]byte, error), readMask []string, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
+	simBytes, err := build(budget)
+	if err != nil {
+		return nil, err
+	}
+	simResp, err := SimulateTransaction(conn, simBytes, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	optimalBudget, err := EstimateGasBudget(simResp)
+	if err != nil {
+		return nil, err
+	}
+
+	execBytes, err := build(optimalBudget)
+	if err != nil {
+		return nil, err
+	}
+
+	return signAndExecute(conn, execBytes, account, readMask, ctx)
+}
+
+// estimateSignExecuteFill is estimateSignExecute over a transaction filled
+// into a single builder: when the backend's builders implement
+// txbuilder.Cloner, the PTB is constructed once and rebuilt with BuildWith
+// for each budget; otherwise fill runs on a fresh builder per build.
+func estimateSignExecuteFill(conn *grpc.ClientConn, backend Backend, account *signer.Signer, budget, price uint64, fill func(b TxBuilder, budget uint64) error, readMask []string, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
+	b, err := backend.NewBuilder()
+	if err != nil {
+		return nil, err
+	}
+	c, ok := b.(txbuilder.Cloner)
+	if !ok {
+		b.Free()
+		return estimateSignExecute(conn, account, budget, func(budget uint64) ([]byte, error) {
+			b, err := backend.NewBuilder()
+			if err != nil {
+				return nil, err
+			}
+			if err := fill(b, budget); err != nil {
+				b.Free()
+				return nil, err
+			}
+			return b.Build()
+		}, readMask, ctx)
+	}
+
+	defer c.Free()
+	if err := fill(c, budget); err != nil {
+		return nil, err
+	}
+	return estimateSignExecute(conn, account, budget, func(budget uint64) ([]byte, error) {
+		return c.BuildWith(budget, price, nil)
+	}, readMask, ctx)
+}
+
 func GetGas(conn *grpc.ClientConn, ctx context.Context) (*pb.GetEpochResponse, error) {
 	client := pb.NewLedgerServiceClient(conn)
 	resp, err := client.GetEpoch(ctx, &pb.GetEpochRequest{})
@@ -386,157 +542,29 @@ func EstimateGasBudget(resp *pb.SimulateTransactionResponse) (uint64, error) {
 }
 
 type SplitCoin struct {
-	Sender    string
-	Recipient string
+	Sender    Address
+	Recipient Address
 	Gasbudget uint64
 	Gasprice  uint64
 	Amount    uint64
 	GasCoin   *pb.GetObjectResponse
 }
 
-func (split *SplitCoin) buildTx(mod api.Module, ctx context.Context) ([]byte, error) {
-	b := NewBuilder(ctx, mod)
-	// Set config with the specific budget passed in
-	if err := b.SetConfig(split.Sender, split.Gasbudget, split.Gasprice); err != nil {
-		return nil, err
-	}
-
-	// Add Gas Object
-	if err := b.AddGasObject(*split.GasCoin.Object.ObjectId, uint64(*split.GasCoin.Object.Version), *split.GasCoin.Object.Digest); err != nil {
-		return nil, err
-	}
-
-	// ... Add your transaction commands (SplitCoins, Transfer, etc) ...
-	// (Copy your existing logic here)
-	gasArg := b.GasArgument()
-	amt := b.PureU64(split.Amount)
-	res, _ := b.SplitCoins(gasArg, []uint64{amt})
-	coin := b.NestedResult(res, 0)
-	rec, _ := b.PureAddress(split.Recipient)
-	b.TransferObjects([]uint64{coin}, rec)
-
-	return b.Build()
-}
-
-func (split *SplitCoin) buildTxCgo(ctx context.Context) ([]byte, error) {
-	b := v2.NewBuilder(ctx, nil)
-	// Set config with the specific budget passed in
-	if err := b.SetConfig(split.Sender, split.Gasbudget, split.Gasprice); err != nil {
-		return nil, err
-	}
-
-	// Add Gas Object
-	if err := b.AddGasObject(*split.GasCoin.Object.ObjectId, uint64(*split.GasCoin.Object.Version), *split.GasCoin.Object.Digest); err != nil {
-		return nil, err
-	}
-
-	// ... Add your transaction commands (SplitCoins, Transfer, etc) ...
-	// (Copy your existing logic here)
-	gasArg := b.GasArgument()
-	amt := b.PureU64(split.Amount)
-	res, err := b.SplitCoins(gasArg, []uint64{amt})
-	if err != nil {
-		return nil, err
-	}
-	coin := b.NestedResult(res, 0)
-	rec, err := b.PureAddress(split.Recipient)
-	if err != nil {
-		return nil, err
-	}
-	b.TransferObjects([]uint64{coin}, rec)
-
-	return b.Build()
-}
-
-func (split *SplitCoin) SignExecuteTx(conn *grpc.ClientConn, mod api.Module, account *signer.Signer, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
-	simBytes, err := split.buildTx(mod, ctx)
-	if err != nil {
-		return nil, err
-	}
-	simResp, err := SimulateTransaction(conn, simBytes, ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	optimalBudget, err := EstimateGasBudget(simResp)
-	if err != nil {
-		return nil, err
-	}
-
-	split.Gasbudget = optimalBudget
-	execBytes, err := split.buildTx(mod, ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	signed, err := SignTransaction(execBytes, account)
-	if err != nil {
-		return nil, err
-	}
-
-	txBytesRaw, err := base64.StdEncoding.DecodeString(signed.TxBytes)
-	if err != nil {
-		return nil, err
-	}
-
-	signatureRaw, err := base64.StdEncoding.DecodeString(signed.Signature)
+func (split *SplitCoin) fill(b TxBuilder, budget uint64) error {
+	id, version, digest, err := ObjectRefOf(split.GasCoin.GetObject())
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	resp, err := SignExecuteTransaction(
-		conn,
-		txBytesRaw,
-		signatureRaw,
-		ctx,
-	)
-
-	return resp, err
-
+	tx := NewTx(b)
+	tx.SetConfig(split.Sender, budget, split.Gasprice)
+	tx.AddGasObject(id, version, digest)
+	tx.SplitCoins(tx.Gas(), tx.U64(split.Amount)).Nested(0).TransferTo(split.Recipient)
+	return tx.Err()
 }
 
-func (split *SplitCoin) SignExecuteTxCgo(conn *grpc.ClientConn, account *signer.Signer, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
-	simBytes, err := split.buildTxCgo(ctx)
-	if err != nil {
-		return nil, err
-	}
-	simResp, err := SimulateTransaction(conn, simBytes, ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	optimalBudget, err := EstimateGasBudget(simResp)
-	if err != nil {
-		return nil, err
-	}
-
-	split.Gasbudget = optimalBudget
-	execBytes, err := split.buildTxCgo(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	signed, err := SignTransaction(execBytes, account)
-	if err != nil {
-		return nil, err
-	}
-
-	txBytesRaw, err := base64.StdEncoding.DecodeString(signed.TxBytes)
-	if err != nil {
-		return nil, err
-	}
-
-	signatureRaw, err := base64.StdEncoding.DecodeString(signed.Signature)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := SignExecuteTransaction(
-		conn,
-		txBytesRaw,
-		signatureRaw,
-		ctx,
-	)
-
-	return resp, err
+// SignExecuteTx simulates the split to estimate its budget, then signs and
+// executes it with builders from backend.
+func (split *SplitCoin) SignExecuteTx(conn *grpc.ClientConn, backend Backend, account *signer.Signer, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
+	return estimateSignExecuteFill(conn, backend, account, split.Gasbudget, split.Gasprice, split.fill, nil, ctx)
 }
@@ -1,3 +1,5 @@
+//go:build txbuilder_cgo
+
 package main
 
 // Static-lib version of main.go.
@@ -32,11 +34,8 @@ func main() {
 	defer conn.Close()
 
 	// ── Constants ────────────────────────────────────────────────────────
-	const (
-		sender    = "0x8aeec8403b86f22e58d87bdc85ff78c87b69dce58b8f651900b9eb5644f45180"
-		recipient = sender
-		splitMIST = uint64(100_000_000) // 0.1 SUI in MIST
-	)
+	const splitMIST = uint64(100_000_000) // 0.1 SUI in MIST
+	sender := gosuisdk.MustParseAddress("0x8aeec8403b86f22e58d87bdc85ff78c87b69dce58b8f651900b9eb5644f45180")
 
 	gas, err := gosuisdk.GetGas(conn, ctx)
 	if err != nil {
@@ -49,11 +48,15 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	suiCoins := gosuisdk.OwnedCoins(ownedObjs, gosuisdk.SuiCoin.String(), sender)
+	suiCoins := gosuisdk.OwnedCoins(ownedObjs, gosuisdk.SuiCoin.Tag())
 	if len(suiCoins) == 0 {
 		log.Fatal("no SUI coins found for sender")
 	}
-	gasCoin, err := gosuisdk.GetObject(conn, *suiCoins[0].ObjectId, suiCoins[0].Version, ctx)
+	gasCoinID, err := gosuisdk.ParseObjectID(suiCoins[0].GetObjectId())
+	if err != nil {
+		panic(err)
+	}
+	gasCoin, err := gosuisdk.GetObject(conn, gasCoinID, suiCoins[0].Version, ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -73,7 +76,12 @@ func main() {
 		Recipient: sender,
 	}
 
-	resp, err := split.SignExecuteTxCgo(conn, account, ctx)
+	backend, err := gosuisdk.CGoBackend()
+	if err != nil {
+		panic(err)
+	}
+
+	resp, err := split.SignExecuteTx(conn, backend, account, ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -4,12 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/block-vision/sui-go-sdk/signer"
 	gosuisdk "github.com/pictorx/go-sui-sdk"
-	"github.com/tetratelabs/wazero"
-	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
@@ -17,19 +14,12 @@ import (
 func main() {
 	ctx := context.Background()
 
-	// ── WASM runtime ─────────────────────────────────────────────────────
-	rt := wazero.NewRuntime(ctx)
-	defer rt.Close(ctx)
-	wasi_snapshot_preview1.MustInstantiate(ctx, rt)
-
-	wasmBytes, err := os.ReadFile("../transaction/target/wasm32-wasip1/release/transaction_builder.wasm")
-	if err != nil {
-		panic(err)
-	}
-	mod, err := rt.Instantiate(ctx, wasmBytes)
+	// ── Builder backend (embedded WASM module, or pure Go without one) ───
+	backend, err := gosuisdk.NewBackend(ctx, gosuisdk.DefaultBackendKind, nil)
 	if err != nil {
 		panic(err)
 	}
+	defer backend.Close(ctx)
 
 	// ── gRPC connection ───────────────────────────────────────────────────
 	conn, err := grpc.Dial(
@@ -42,11 +32,8 @@ func main() {
 	defer conn.Close()
 
 	// ── Constants ────────────────────────────────────────────────────────
-	const (
-		sender    = "0x8aeec8403b86f22e58d87bdc85ff78c87b69dce58b8f651900b9eb5644f45180"
-		recipient = sender
-		splitMIST = uint64(100_000_000) // 0.1 SUI in MIST
-	)
+	const splitMIST = uint64(100_000_000) // 0.1 SUI in MIST
+	sender := gosuisdk.MustParseAddress("0x8aeec8403b86f22e58d87bdc85ff78c87b69dce58b8f651900b9eb5644f45180")
 
 	gas, err := gosuisdk.GetGas(conn, ctx)
 	if err != nil {
@@ -59,11 +46,15 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	suiCoins := gosuisdk.OwnedCoins(ownedObjs, gosuisdk.SuiCoin.String(), sender)
+	suiCoins := gosuisdk.OwnedCoins(ownedObjs, gosuisdk.SuiCoin.Tag())
 	if len(suiCoins) == 0 {
 		log.Fatal("no SUI coins found for sender")
 	}
-	gasCoin, err := gosuisdk.GetObject(conn, *suiCoins[0].ObjectId, suiCoins[0].Version, ctx)
+	gasCoinID, err := gosuisdk.ParseObjectID(suiCoins[0].GetObjectId())
+	if err != nil {
+		panic(err)
+	}
+	gasCoin, err := gosuisdk.GetObject(conn, gasCoinID, suiCoins[0].Version, ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -83,7 +74,7 @@ func main() {
 		Recipient: sender,
 	}
 
-	resp, err := split.SignExecuteTx(conn, mod, account, ctx)
+	resp, err := split.SignExecuteTx(conn, backend, account, ctx)
 	if err != nil {
 		panic(err)
 	}
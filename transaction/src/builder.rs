@@ -45,18 +45,63 @@ pub(crate) enum ResolvedArgument {
     Resolved(sui_sdk_types::Argument),
 }
 
-#[derive(Debug, PartialEq, Eq, Hash)]
+#[derive(Clone, Debug, PartialEq, Eq, Hash)]
 pub(crate) enum InputArgKind {
     Gas,
     ObjectInput(Address),
     PureInput(Vec<u8>),
     UniquePureInput(usize),
+    FundsWithdrawal(usize),
 }
 
+#[derive(Clone)]
 pub(crate) enum InputArg {
     Gas,
     Pure(Vec<u8>),
     Object(ObjectInput),
+    FundsWithdrawal(FundsWithdrawal),
+}
+
+fn encode<T: serde::Serialize>(value: &T) -> Result<Vec<u8>, Error> {
+    bcs::to_bytes(value).map_err(|e| Error::Serialization(e.to_string()))
+}
+
+/// Where a funds withdrawal is taken from.
+#[derive(Clone, Copy, Debug, PartialEq, Eq)]
+pub enum WithdrawFrom {
+    Sender,
+    Sponsor,
+}
+
+/// A reservation to withdraw up to `amount` of `Balance<coin_type>` from an
+/// address balance, passed to the transaction as a `Withdrawal` input.
+#[derive(Clone, Debug)]
+pub struct FundsWithdrawal {
+    pub amount: u64,
+    pub coin_type: TypeTag,
+    pub source: WithdrawFrom,
+}
+
+impl FundsWithdrawal {
+    /// BCS encoding of the input as a `CallArg`:
+    ///
+    /// ```text
+    /// FundsWithdrawal { reservation: MaxAmountU64(amount),
+    ///                   type_arg: Balance(coin_type), withdraw_from: Sender | Sponsor }
+    /// ```
+    ///
+    /// The pinned `sui_sdk_types` has no such input, so it is written by hand.
+    fn to_bcs(&self) -> Result<Vec<u8>, Error> {
+        let mut bytes = vec![2, 1]; // CallArg::FundsWithdrawal, Reservation::MaxAmountU64
+        bytes.extend_from_slice(&self.amount.to_le_bytes());
+        bytes.push(0); // WithdrawalTypeArg::Balance
+        bytes.extend(encode(&self.coin_type)?);
+        bytes.push(match self.source {
+            WithdrawFrom::Sender => 0,
+            WithdrawFrom::Sponsor => 1,
+        });
+        Ok(bytes)
+    }
 }
 
 impl TransactionBuilder {
@@ -158,6 +203,20 @@ impl TransactionBuilder {
         }
     }
 
+    /// Add a reservation to withdraw from an address balance. Every call adds a
+    /// new input. A transaction holding one can only be built with [`try_build_bcs`].
+    ///
+    /// [`try_build_bcs`]: TransactionBuilder::try_build_bcs
+    pub fn funds_withdrawal(&mut self, withdrawal: FundsWithdrawal) -> Argument {
+        let id = self.arguments.len();
+        self.arguments.insert(id, ResolvedArgument::Unresolved);
+        self.inputs.insert(
+            InputArgKind::FundsWithdrawal(id),
+            (id, InputArg::FundsWithdrawal(withdrawal)),
+        );
+        Argument::new(id)
+    }
+
     // Metadata
 
     /// Add one or more gas objects to use to pay for the transaction.
@@ -194,6 +253,34 @@ impl TransactionBuilder {
         self.expiration = Some(expiration);
     }
 
+    /// Remove the gas objects added so far.
+    pub fn clear_gas_objects(&mut self) {
+        self.gas.clear();
+    }
+
+    /// Copy the builder, so the same commands can be built more than once (e.g. with a different
+    /// gas budget). Intents hold resolver state that cannot be copied, so a builder with
+    /// unresolved intents is rejected.
+    pub fn try_clone(&self) -> Result<Self, Error> {
+        if !self.intents.is_empty() {
+            return Err(Error::Input("cannot clone a builder with unresolved intents".to_owned()));
+        }
+        Ok(Self {
+            gas: self.gas.clone(),
+            gas_budget: self.gas_budget,
+            gas_price: self.gas_price,
+            sender: self.sender,
+            sponsor: self.sponsor,
+            expiration: self.expiration.clone(),
+            #[cfg(feature = "intents")]
+            resolvers: BTreeMap::new(),
+            arguments: self.arguments.clone(),
+            inputs: self.inputs.clone(),
+            commands: self.commands.clone(),
+            intents: BTreeMap::new(),
+        })
+    }
+
     // Commands
 
     fn command(&mut self, command: Command) -> Argument {
@@ -314,7 +401,55 @@ impl TransactionBuilder {
 
     /// Assuming everything is resolved, convert this transaction into the
     /// resolved form. Returns a [`Transaction`] if successful, or an `Error` if not.
-    pub fn try_build(mut self) -> Result<Transaction, Error> {
+    /// Funds withdrawal inputs cannot be represented in a [`Transaction`]; use
+    /// [`try_build_bcs`](Self::try_build_bcs) for those.
+    pub fn try_build(self) -> Result<Transaction, Error> {
+        let (transaction, withdrawals) = self.try_resolve()?;
+        if !withdrawals.is_empty() {
+            return Err(Error::Input(
+                "funds withdrawal inputs can only be built with try_build_bcs".to_owned(),
+            ));
+        }
+        Ok(transaction)
+    }
+
+    /// Like [`try_build`](Self::try_build), returning the BCS-encoded transaction data, with
+    /// funds withdrawal inputs encoded in place.
+    pub fn try_build_bcs(self) -> Result<Vec<u8>, Error> {
+        let (transaction, withdrawals) = self.try_resolve()?;
+        let bytes = encode(&transaction)?;
+        if withdrawals.is_empty() {
+            return Ok(bytes);
+        }
+
+        // Each withdrawal was resolved to an empty pure input; swap in its
+        // encoding.  The inputs follow the TransactionData::V1 and
+        // TransactionKind::ProgrammableTransaction tags.
+        const INPUTS: usize = 2;
+        let sui_sdk_types::TransactionKind::ProgrammableTransaction(ptb) = &transaction.kind else {
+            unreachable!("try_resolve builds programmable transactions");
+        };
+        let inputs = encode(&ptb.inputs)?;
+        if bytes.get(INPUTS..INPUTS + inputs.len()) != Some(&inputs[..]) {
+            return Err(Error::Serialization("unexpected transaction layout".to_owned()));
+        }
+        let encoded = ptb.inputs.iter().map(encode).collect::<Result<Vec<_>, _>>()?;
+        let count = inputs.len() - encoded.iter().map(Vec::len).sum::<usize>();
+
+        let mut out = bytes[..INPUTS + count].to_vec();
+        for (i, input) in encoded.into_iter().enumerate() {
+            match withdrawals.get(&i) {
+                Some(withdrawal) => out.extend(withdrawal.to_bcs()?),
+                None => out.extend(input),
+            }
+        }
+        out.extend_from_slice(&bytes[INPUTS + inputs.len()..]);
+        Ok(out)
+    }
+
+    /// Resolve the transaction offline. Funds withdrawals are returned by input index, with an
+    /// empty pure input standing in for each.
+    fn try_resolve(mut self) -> Result<(Transaction, BTreeMap<usize, FundsWithdrawal>), Error> {
         let Some(sender) = self.sender else {
             return Err(Error::MissingSender);
         };
@@ -353,6 +488,7 @@ impl TransactionBuilder {
         unresolved_inputs.sort_by_key(|(id, _input)| *id);
 
         let mut resolved_inputs = Vec::new();
+        let mut withdrawals = BTreeMap::new();
         for (id, input) in unresolved_inputs {
             let arg = match input {
                 InputArg::Gas => sui_sdk_types::Argument::Gas,
@@ -364,6 +500,11 @@ impl TransactionBuilder {
                     resolved_inputs.push(object_input.try_into_input()?);
                     sui_sdk_types::Argument::Input(resolved_inputs.len() as u16 - 1)
                 }
+                InputArg::FundsWithdrawal(withdrawal) => {
+                    withdrawals.insert(resolved_inputs.len(), withdrawal);
+                    resolved_inputs.push(sui_sdk_types::Input::Pure(Vec::new()));
+                    sui_sdk_types::Argument::Input(resolved_inputs.len() as u16 - 1)
+                }
             };
 
             *self.arguments.get_mut(&id).unwrap() = ResolvedArgument::Resolved(arg);
@@ -376,17 +517,17 @@ impl TransactionBuilder {
         let mut resolved_commands = Vec::new();
 
         for (id, command) in self.commands {
-            resolved_commands.push(
-                command
-                    .try_resolve(&self.arguments)
-                    .map_err(|e| e.unwrap_err())?,
-            );
+            resolved_commands.push(command.try_resolve(&self.arguments).map_err(|e| {
+                e.unwrap_or_else(|id| {
+                    Error::Input(format!("argument {id} is used before the command producing it"))
+                })
+            })?);
             let arg = sui_sdk_types::Argument::Result(resolved_commands.len() as u16 - 1);
 
             *self.arguments.get_mut(&id).unwrap() = ResolvedArgument::Resolved(arg);
         }
 
-        Ok(Transaction {
+        let transaction = Transaction {
             kind: sui_sdk_types::TransactionKind::ProgrammableTransaction(
                 sui_sdk_types::ProgrammableTransaction {
                     inputs: resolved_inputs,
@@ -396,7 +537,8 @@ impl TransactionBuilder {
             sender,
             gas_payment,
             expiration: self.expiration.unwrap_or(TransactionExpiration::None),
-        })
+        };
+        Ok((transaction, withdrawals))
     }
 
     // FIXED: Was `#[cfg(feature = "rpc")]` — feature is named "intents" in Cargo.toml.
@@ -467,6 +609,11 @@ impl TransactionBuilder {
                     resolved_inputs.push(object_input.to_input_proto());
                     sui_sdk_types::Argument::Input(resolved_inputs.len() as u16 - 1)
                 }
+                InputArg::FundsWithdrawal(_) => {
+                    return Err(Error::Input(
+                        "funds withdrawal inputs can only be built with try_build_bcs".to_owned(),
+                    ))
+                }
             };
 
             *self.arguments.get_mut(&id).unwrap() = ResolvedArgument::Resolved(arg);
@@ -625,11 +772,16 @@ impl Argument {
 
             loop {
                 if visited.contains(&next_id) {
-                    panic!("BUG: cyclic dependency");
+                    return Err(Err(Error::Input(format!(
+                        "argument {next_id} is a cyclic nested result"
+                    ))));
                 }
                 visited.insert(next_id);
 
-                match resolved_arguments.get(&next_id).unwrap() {
+                let Some(resolved) = resolved_arguments.get(&next_id) else {
+                    return Err(Err(Error::Input(format!("argument {next_id} does not exist"))));
+                };
+                match resolved {
                     ResolvedArgument::Unresolved => return Err(Ok(next_id)),
                     ResolvedArgument::ReplaceWith(argument) => {
                         next_id = argument.id;
@@ -664,6 +816,7 @@ impl Argument {
     }
 }
 
+#[derive(Clone)]
 pub(crate) struct Command {
     kind: CommandKind,
     // A way to encode dependencies between commands when there aren't dependencies via explicit
@@ -680,6 +833,7 @@ impl From<CommandKind> for Command {
     }
 }
 
+#[derive(Clone)]
 pub(crate) enum CommandKind {
     /// A call to either an entry or a public Move function
     MoveCall(MoveCall),
@@ -795,6 +949,7 @@ impl Command {
     }
 }
 
+#[derive(Clone)]
 pub(crate) struct TransferObjects {
     /// Set of objects to transfer
     pub objects: Vec<Argument>,
@@ -803,6 +958,7 @@ pub(crate) struct TransferObjects {
     pub address: Argument,
 }
 
+#[derive(Clone)]
 pub(crate) struct SplitCoins {
     /// The coin to split
     pub coin: Argument,
@@ -811,6 +967,7 @@ pub(crate) struct SplitCoins {
     pub amounts: Vec<Argument>,
 }
 
+#[derive(Clone)]
 pub(crate) struct MergeCoins {
     /// Coin to merge coins into
     pub coin: Argument,
@@ -821,6 +978,7 @@ pub(crate) struct MergeCoins {
     pub coins_to_merge: Vec<Argument>,
 }
 
+#[derive(Clone)]
 pub(crate) struct Publish {
     /// The serialized move modules
     pub modules: Vec<Vec<u8>>,
@@ -829,6 +987,7 @@ pub(crate) struct Publish {
     pub dependencies: Vec<Address>,
 }
 
+#[derive(Clone)]
 pub(crate) struct MakeMoveVector {
     /// Type of the individual elements
     ///
@@ -840,6 +999,7 @@ pub(crate) struct MakeMoveVector {
     pub elements: Vec<Argument>,
 }
 
+#[derive(Clone)]
 pub(crate) struct Upgrade {
     /// The serialized move modules
     pub modules: Vec<Vec<u8>>,
@@ -854,6 +1014,7 @@ pub(crate) struct Upgrade {
     pub ticket: Argument,
 }
 
+#[derive(Clone)]
 pub(crate) struct MoveCall {
     /// The package containing the module and function.
     pub package: Address,
@@ -872,6 +1033,7 @@ pub(crate) struct MoveCall {
     // Return value count??
 }
 
+#[derive(Clone)]
 pub struct ObjectInput {
     object_id: Address,
     kind: Option<ObjectKind>,
@@ -1092,12 +1254,7 @@ impl ObjectInput {
                 *object_id, *version, *mutable,
             )),
 
-            _ => {
-                return Err(Error::Input(format!(
-                    "Input object {} is incomplete",
-                    self.object_id
-                )));
-            }
+            _ => return Err(Error::IncompleteObject(self.object_id)),
         };
         Ok(input)
     }
@@ -1182,6 +1339,7 @@ impl ObjectInput {
 }
 
 /// A separate type to support denoting a function by a more structured representation.
+#[derive(Clone)]
 pub struct Function {
     /// The package that contains the module with the function.
     package: Address,
@@ -1284,6 +1442,41 @@ mod tests {
         assert!(tx.try_build().is_ok());
     }
 
+    #[test]
+    fn funds_withdrawal_bcs() {
+        let mut tx = TransactionBuilder::new();
+        let withdrawal = FundsWithdrawal {
+            amount: 5,
+            coin_type: "0x2::sui::SUI".parse().unwrap(),
+            source: WithdrawFrom::Sponsor,
+        };
+        let funds = tx.funds_withdrawal(withdrawal.clone());
+        let recipient = tx.pure(&Address::from_static("0xabc"));
+        tx.transfer_objects(vec![funds], recipient);
+        tx.set_gas_budget(500000000);
+        tx.set_gas_price(1000);
+        tx.add_gas_objects([ObjectInput::owned(
+            Address::from_static(
+                "0xd8792bce2743e002673752902c0e7348dfffd78638cb5367b0b85857bceb9821",
+            ),
+            2,
+            Digest::from_static("2ZigdvsZn5BMeszscPQZq9z8ebnS2FpmAuRbAi9ednCk"),
+        )]);
+        tx.set_sender(Address::from_static(
+            "0xc574ea804d9c1a27c886312e96c0e2c9cfd71923ebaeb3000d04b5e65fca2793",
+        ));
+
+        assert!(tx.try_clone().unwrap().try_build().is_err());
+
+        // V1, ProgrammableTransaction, two inputs: the withdrawal, then the recipient.
+        let mut want = vec![0, 0, 2];
+        want.extend(withdrawal.to_bcs().unwrap());
+        want.extend([0, 32]);
+        want.extend(Address::from_static("0xabc").into_inner());
+        let bytes = tx.try_build_bcs().unwrap();
+        assert_eq!(&bytes[..want.len()], &want[..]);
+    }
+
     #[test]
     fn test_deterministic_building() {
         let build_tx = || {
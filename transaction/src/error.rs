@@ -28,4 +28,41 @@ pub enum Error {
     MissingObjectKind(Address),
     #[error("Unknown shared object mutability for object {0}")]
     SharedObjectMutability(Address),
+    #[error("Conversion error due to input issue: Input object {0} is incomplete")]
+    IncompleteObject(Address),
+    #[error("Serialization error: {0}")]
+    Serialization(String),
+}
+
+impl Error {
+    /// Stable snake_case name of the variant, reported across the FFI.
+    pub fn kind(&self) -> &'static str {
+        match self {
+            Error::Input(_) => "input",
+            Error::WrongGasObject => "wrong_gas_object",
+            Error::MissingObjectId => "missing_object_id",
+            Error::MissingVersion(_) => "missing_version",
+            Error::MissingDigest(_) => "missing_digest",
+            Error::MissingSender => "missing_sender",
+            Error::MissingGasObjects => "missing_gas_objects",
+            Error::MissingGasBudget => "missing_gas_budget",
+            Error::MissingGasPrice => "missing_gas_price",
+            Error::MissingObjectKind(_) => "missing_object_kind",
+            Error::SharedObjectMutability(_) => "shared_object_mutability",
+            Error::IncompleteObject(_) => "incomplete_object",
+            Error::Serialization(_) => "serialization",
+        }
+    }
+
+    /// The object the error is about, if any.
+    pub fn object_id(&self) -> Option<Address> {
+        match self {
+            Error::MissingVersion(id)
+            | Error::MissingDigest(id)
+            | Error::MissingObjectKind(id)
+            | Error::SharedObjectMutability(id)
+            | Error::IncompleteObject(id) => Some(*id),
+            _ => None,
+        }
+    }
 }
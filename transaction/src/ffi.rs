@@ -1,7 +1,10 @@
 // ffi.rs
-use crate::{TransactionBuilder, ObjectInput, Function, Argument};
+use crate::{TransactionBuilder, ObjectInput, Function, Argument, FundsWithdrawal, WithdrawFrom};
 use crate::builder::ResolvedArgument;
+use serde::de::DeserializeOwned;
+use serde_json::{Map, Value};
 use sui_sdk_types::{Address, TypeTag, Identifier};
+use std::cell::RefCell;
 use std::slice;
 use std::mem;
 use std::str::FromStr;
@@ -24,6 +27,210 @@ pub unsafe extern "C" fn dealloc(ptr: *mut u8, len: usize) {
     let _ = Vec::from_raw_parts(ptr, len, len);
 }
 
+// ── Error reporting ──────────────────────────────────────────────────────────
+
+/// Details of a failed call, kept per thread as JSON until the next failure.
+///
+/// JSON shape:
+/// ```json
+/// {"function":"input_object","code":-2,"kind":"invalid_digest",
+///  "message":"…","field":"digest","index":0,"object_id":"0x…"}
+/// ```
+/// `field`, `index` and `object_id` are omitted when not applicable.
+#[derive(serde::Serialize)]
+struct FfiError {
+    function: &'static str,
+    code: i64,
+    kind: &'static str,
+    message: String,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    field: Option<&'static str>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    index: Option<usize>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    object_id: Option<Address>,
+}
+
+impl FfiError {
+    fn new(code: i64, kind: &'static str, message: impl Into<String>) -> Self {
+        Self {
+            function: "",
+            code,
+            kind,
+            message: message.into(),
+            field: None,
+            index: None,
+            object_id: None,
+        }
+    }
+
+    fn field(mut self, field: &'static str) -> Self {
+        self.field = Some(field);
+        self
+    }
+
+    fn index(mut self, index: usize) -> Self {
+        self.index = Some(index);
+        self
+    }
+}
+
+impl From<crate::error::Error> for FfiError {
+    fn from(e: crate::error::Error) -> Self {
+        let mut err = FfiError::new(0, e.kind(), e.to_string());
+        err.object_id = e.object_id();
+        err
+    }
+}
+
+thread_local! {
+    static LAST_ERROR: RefCell<Vec<u8>> = const { RefCell::new(Vec::new()) };
+}
+
+/// Record `err` as the last error of `function` and return its code.
+fn fail(function: &'static str, mut err: FfiError) -> i64 {
+    err.function = function;
+    let json = serde_json::to_vec(&err).unwrap_or_default();
+    LAST_ERROR.with(|e| *e.borrow_mut() = json);
+    err.code
+}
+
+/// Length in bytes of the JSON error recorded by the last failing call on
+/// this thread, or 0 if there is none.
+#[no_mangle]
+pub extern "C" fn last_error_length() -> usize {
+    LAST_ERROR.with(|e| e.borrow().len())
+}
+
+/// Copy the last error JSON into `buf` (capacity `len`).
+/// Returns the number of bytes written, or -1 if `len` is too small.
+/// The error is kept until the next failure or `clear_last_error`.
+#[no_mangle]
+pub unsafe extern "C" fn last_error_message(buf: *mut u8, len: usize) -> i64 {
+    LAST_ERROR.with(|e| {
+        let e = e.borrow();
+        if e.len() > len {
+            return -1;
+        }
+        if !e.is_empty() {
+            std::ptr::copy_nonoverlapping(e.as_ptr(), buf, e.len());
+        }
+        e.len() as i64
+    })
+}
+
+/// Forget the last error recorded on this thread.
+#[no_mangle]
+pub extern "C" fn clear_last_error() {
+    LAST_ERROR.with(|e| e.borrow_mut().clear());
+}
+
+// ── JSON helpers ─────────────────────────────────────────────────────────────
+
+/// Parse a request body into a JSON object; fields are then taken one by one
+/// so errors can name the offending field.
+fn parse_object(bytes: &[u8]) -> Result<Map<String, Value>, FfiError> {
+    match serde_json::from_slice::<Value>(bytes) {
+        Ok(Value::Object(m)) => Ok(m),
+        Ok(_) => Err(FfiError::new(-1, "json", "expected a JSON object")),
+        Err(e) => Err(FfiError::new(-1, "json", e.to_string())),
+    }
+}
+
+/// Take a required field, reporting decode failures as `kind`.
+fn take<T: DeserializeOwned>(
+    obj: &mut Map<String, Value>,
+    name: &'static str,
+    kind: &'static str,
+) -> Result<T, FfiError> {
+    match obj.remove(name) {
+        None | Some(Value::Null) => {
+            Err(FfiError::new(-1, "json", format!("missing field `{name}`")).field(name))
+        }
+        Some(v) => serde_json::from_value(v)
+            .map_err(|e| FfiError::new(-1, kind, e.to_string()).field(name)),
+    }
+}
+
+/// Take an optional field; `null` counts as absent.
+fn take_opt<T: DeserializeOwned>(
+    obj: &mut Map<String, Value>,
+    name: &'static str,
+    kind: &'static str,
+) -> Result<Option<T>, FfiError> {
+    match obj.get(name) {
+        None | Some(Value::Null) => Ok(None),
+        Some(_) => take(obj, name, kind).map(Some),
+    }
+}
+
+/// Take a list field and decode each element, naming the failing index.
+fn take_list<T: DeserializeOwned>(
+    obj: &mut Map<String, Value>,
+    name: &'static str,
+    kind: &'static str,
+) -> Result<Vec<T>, FfiError> {
+    let items: Vec<Value> = take_opt(obj, name, "json")?.unwrap_or_default();
+    items
+        .into_iter()
+        .enumerate()
+        .map(|(i, v)| {
+            serde_json::from_value(v)
+                .map_err(|e| FfiError::new(-1, kind, e.to_string()).field(name).index(i))
+        })
+        .collect()
+}
+
+fn identifier(s: &str, code: i64, field: &'static str) -> Result<Identifier, FfiError> {
+    Identifier::from_str(s).map_err(|_| {
+        FfiError::new(code, "invalid_identifier", format!("invalid identifier `{s}`")).field(field)
+    })
+}
+
+fn digest(s: &str) -> Result<sui_sdk_types::Digest, FfiError> {
+    sui_sdk_types::Digest::from_str(s).map_err(|_| {
+        FfiError::new(-2, "invalid_digest", format!("invalid digest `{s}`")).field("digest")
+    })
+}
+
+/// Check that every id names an existing Argument.
+fn check_arguments(
+    builder: &TransactionBuilder,
+    ids: &[usize],
+    field: &'static str,
+) -> Result<(), FfiError> {
+    let len = builder.arguments.len();
+    match ids.iter().position(|&id| id >= len) {
+        Some(i) => Err(FfiError::new(
+            -4,
+            "unknown_argument",
+            format!("argument {} does not exist", ids[i]),
+        )
+        .field(field)
+        .index(i)),
+        None => Ok(()),
+    }
+}
+
+/// Check a single Argument ID held by a scalar field.
+fn check_argument(
+    builder: &TransactionBuilder,
+    id: usize,
+    field: &'static str,
+) -> Result<(), FfiError> {
+    check_arguments(builder, &[id], field).map_err(|mut e| {
+        e.index = None;
+        e
+    })
+}
+
+unsafe fn ids_from_raw(ptr: *const u64, count: usize) -> Vec<usize> {
+    if count == 0 || ptr.is_null() {
+        return vec![];
+    }
+    slice::from_raw_parts(ptr, count).iter().map(|&id| id as usize).collect()
+}
+
 // ── Builder Lifecycle ────────────────────────────────────────────────────────
 
 /// Create a new TransactionBuilder and return an opaque pointer to it.
@@ -41,15 +248,24 @@ pub unsafe extern "C" fn free_builder(builder: *mut TransactionBuilder) {
     }
 }
 
-// ── Configuration ────────────────────────────────────────────────────────────
-
-#[derive(serde::Deserialize)]
-struct ConfigParams {
-    sender: Address,
-    gas_budget: Option<u64>,
-    gas_price: Option<u64>,
+/// Copy a builder that has not been built, so the same commands can be built
+/// again, e.g. once to simulate and once with the estimated gas budget.
+/// Argument IDs are the same in both.  The copy is freed or consumed like
+/// any builder.  Returns null (see `last_error_message`) if the builder holds
+/// unresolved intents.
+#[no_mangle]
+pub unsafe extern "C" fn clone_builder(builder: *const TransactionBuilder) -> *mut TransactionBuilder {
+    match (&*builder).try_clone() {
+        Ok(b) => Box::into_raw(Box::new(b)),
+        Err(e) => {
+            fail("clone_builder", FfiError::from(e));
+            std::ptr::null_mut()
+        }
+    }
 }
 
+// ── Configuration ────────────────────────────────────────────────────────────
+
 /// Set sender, gas_budget, and gas_price from a JSON object.
 /// JSON shape: `{"sender":"0x…","gas_budget":10000000,"gas_price":1000}`
 /// Returns 1 on success, -1 on parse error.
@@ -61,26 +277,25 @@ pub unsafe extern "C" fn set_config(
 ) -> i32 {
     let builder = &mut *builder;
     let bytes = slice::from_raw_parts(json_ptr, json_len);
-    match serde_json::from_slice::<ConfigParams>(bytes) {
-        Ok(p) => {
-            builder.set_sender(p.sender);
-            if let Some(b) = p.gas_budget { builder.set_gas_budget(b); }
-            if let Some(p) = p.gas_price  { builder.set_gas_price(p);  }
+    let parsed = parse_object(bytes).and_then(|mut obj| {
+        let sender: Address = take(&mut obj, "sender", "invalid_address")?;
+        let budget: Option<u64> = take_opt(&mut obj, "gas_budget", "json")?;
+        let price: Option<u64> = take_opt(&mut obj, "gas_price", "json")?;
+        Ok((sender, budget, price))
+    });
+    match parsed {
+        Ok((sender, budget, price)) => {
+            builder.set_sender(sender);
+            if let Some(b) = budget { builder.set_gas_budget(b); }
+            if let Some(p) = price  { builder.set_gas_price(p);  }
             1
         }
-        Err(_) => -1,
+        Err(e) => fail("set_config", e) as i32,
     }
 }
 
 // ── Gas Objects ───────────────────────────────────────────────────────────────
 
-#[derive(serde::Deserialize)]
-struct GasObjectParams {
-    id: Address,
-    version: u64,
-    digest: String,
-}
-
 /// Add an owned gas object from a JSON object.
 /// JSON shape: `{"id":"0x…","version":2,"digest":"base58…"}`
 /// Returns 1 on success, -1 on JSON parse error, -2 on invalid digest.
@@ -92,16 +307,73 @@ pub unsafe extern "C" fn add_gas_object(
 ) -> i32 {
     let builder = &mut *builder;
     let bytes = slice::from_raw_parts(json_ptr, json_len);
-    match serde_json::from_slice::<GasObjectParams>(bytes) {
-        Ok(g) => {
-            let digest = match sui_sdk_types::Digest::from_str(&g.digest) {
-                Ok(d)  => d,
-                Err(_) => return -2,
-            };
-            builder.add_gas_objects(vec![ObjectInput::owned(g.id, g.version, digest)]);
+    let parsed = parse_object(bytes).and_then(|mut obj| {
+        let id: Address = take(&mut obj, "id", "invalid_address")?;
+        let version: u64 = take(&mut obj, "version", "json")?;
+        let d: String = take(&mut obj, "digest", "json")?;
+        Ok(ObjectInput::owned(id, version, digest(&d)?))
+    });
+    match parsed {
+        Ok(gas) => {
+            builder.add_gas_objects(vec![gas]);
             1
         }
-        Err(_) => -1,
+        Err(e) => fail("add_gas_object", e) as i32,
+    }
+}
+
+/// Replace gas settings from a JSON object; every field is optional.
+/// JSON shape:
+/// `{"gas_budget":10000000,"gas_price":1000,"gas":[{"id":"0x…","version":2,"digest":"base58…"}]}`
+/// A present `gas` list replaces the gas objects added so far.
+/// Returns 1 on success, -1 on JSON parse error, -2 on invalid digest.
+#[no_mangle]
+pub unsafe extern "C" fn set_gas(
+    builder: *mut TransactionBuilder,
+    json_ptr: *const u8,
+    json_len: usize,
+) -> i32 {
+    #[derive(serde::Deserialize)]
+    struct GasRef {
+        id: Address,
+        version: u64,
+        digest: String,
+    }
+
+    let builder = &mut *builder;
+    let bytes = slice::from_raw_parts(json_ptr, json_len);
+    let parsed = parse_object(bytes).and_then(|mut obj| {
+        let budget: Option<u64> = take_opt(&mut obj, "gas_budget", "json")?;
+        let price: Option<u64> = take_opt(&mut obj, "gas_price", "json")?;
+        let gas: Option<Vec<ObjectInput>> = match take_opt::<Vec<Value>>(&mut obj, "gas", "json")? {
+            None => None,
+            Some(items) => Some(
+                items
+                    .into_iter()
+                    .enumerate()
+                    .map(|(i, v)| {
+                        let g: GasRef = serde_json::from_value(v).map_err(|e| {
+                            FfiError::new(-1, "json", e.to_string()).field("gas").index(i)
+                        })?;
+                        let d = digest(&g.digest).map_err(|e| e.field("gas").index(i))?;
+                        Ok(ObjectInput::owned(g.id, g.version, d))
+                    })
+                    .collect::<Result<_, FfiError>>()?,
+            ),
+        };
+        Ok((budget, price, gas))
+    });
+    match parsed {
+        Ok((budget, price, gas)) => {
+            if let Some(b) = budget { builder.set_gas_budget(b); }
+            if let Some(p) = price  { builder.set_gas_price(p);  }
+            if let Some(gas) = gas {
+                builder.clear_gas_objects();
+                builder.add_gas_objects(gas);
+            }
+            1
+        }
+        Err(e) => fail("set_gas", e) as i32,
     }
 }
 
@@ -118,16 +390,6 @@ pub unsafe extern "C" fn gas_argument(builder: *mut TransactionBuilder) -> u64 {
 
 // ── Object inputs ─────────────────────────────────────────────────────────────
 
-#[derive(serde::Deserialize)]
-struct ObjectInputParams {
-    id: Address,
-    version: u64,
-    digest: Option<String>,   // required for owned / immutable / receiving
-    mutable: Option<bool>,    // required for shared; ignored otherwise
-    #[serde(rename = "kind")]
-    kind: String,             // "owned" | "immutable" | "receiving" | "shared"
-}
-
 /// Push an object input (owned, immutable, receiving, or shared) and return
 /// its Argument ID.
 ///
@@ -145,32 +407,102 @@ pub unsafe extern "C" fn input_object(
 ) -> i64 {
     let builder = &mut *builder;
     let bytes = slice::from_raw_parts(json_ptr, json_len);
-    let p: ObjectInputParams = match serde_json::from_slice(bytes) {
-        Ok(v)  => v,
-        Err(_) => return -1,
-    };
-
-    let obj = match p.kind.as_str() {
-        "owned" | "immutable" | "receiving" => {
-            let digest_str = match &p.digest {
-                Some(d) => d,
-                None    => return -2,
-            };
-            let digest = match sui_sdk_types::Digest::from_str(digest_str) {
-                Ok(d)  => d,
-                Err(_) => return -2,
-            };
-            match p.kind.as_str() {
-                "owned"     => ObjectInput::owned(p.id, p.version, digest),
-                "immutable" => ObjectInput::immutable(p.id, p.version, digest),
-                _           => ObjectInput::receiving(p.id, p.version, digest),
+    let parsed = parse_object(bytes).and_then(|mut obj| {
+        let id: Address = take(&mut obj, "id", "invalid_address")?;
+        let version: u64 = take(&mut obj, "version", "json")?;
+        let kind: String = take(&mut obj, "kind", "json")?;
+        let input = match kind.as_str() {
+            "owned" | "immutable" | "receiving" => {
+                let d: Option<String> = take_opt(&mut obj, "digest", "json")?;
+                let Some(d) = d else {
+                    return Err(FfiError::new(
+                        -2,
+                        "missing_digest",
+                        format!("Missing digest for object {id}"),
+                    )
+                    .field("digest"));
+                };
+                let digest = digest(&d)?;
+                match kind.as_str() {
+                    "owned"     => ObjectInput::owned(id, version, digest),
+                    "immutable" => ObjectInput::immutable(id, version, digest),
+                    _           => ObjectInput::receiving(id, version, digest),
+                }
+            }
+            "shared" => {
+                let mutable: Option<bool> = take_opt(&mut obj, "mutable", "json")?;
+                ObjectInput::shared(id, version, mutable.unwrap_or(true))
             }
+            _ => {
+                return Err(FfiError::new(
+                    -3,
+                    "unknown_object_kind",
+                    format!("unknown object kind `{kind}`"),
+                )
+                .field("kind"))
+            }
+        };
+        Ok(input)
+    });
+    match parsed {
+        Ok(obj) => builder.object(obj).id as i64,
+        Err(mut e) => {
+            if e.object_id.is_none() {
+                e.object_id = object_id_hint(bytes);
+            }
+            fail("input_object", e)
         }
-        "shared" => ObjectInput::shared(p.id, p.version, p.mutable.unwrap_or(true)),
-        _        => return -3,
-    };
+    }
+}
 
-    builder.object(obj).id as i64
+/// Best-effort object id of a request that failed to parse.
+fn object_id_hint(bytes: &[u8]) -> Option<Address> {
+    let v: Value = serde_json::from_slice(bytes).ok()?;
+    Address::from_str(v.get("id")?.as_str()?).ok()
+}
+
+/// Push a withdrawal from an address balance and return its Argument ID.
+/// Every call adds a new input.
+///
+/// JSON shape: `{"amount":N,"coin_type":"0x2::sui::SUI","source":"sender"}`;
+/// `source` is `"sender"` (default) or `"sponsor"`.
+///
+/// Returns Argument ID (≥ 0) on success, -1 on JSON error, -2 on bad type
+/// tag, -3 on unknown source.
+#[no_mangle]
+pub unsafe extern "C" fn input_funds_withdrawal(
+    builder: *mut TransactionBuilder,
+    json_ptr: *const u8,
+    json_len: usize,
+) -> i64 {
+    let builder = &mut *builder;
+    let bytes = slice::from_raw_parts(json_ptr, json_len);
+    let parsed = parse_object(bytes).and_then(|mut obj| {
+        let amount: u64 = take(&mut obj, "amount", "json")?;
+        let coin_type: String = take(&mut obj, "coin_type", "json")?;
+        let coin_type = coin_type.trim().parse::<TypeTag>().map_err(|e| {
+            FfiError::new(-2, "invalid_type_tag", format!("invalid type tag `{coin_type}`: {e}"))
+                .field("coin_type")
+        })?;
+        let source: Option<String> = take_opt(&mut obj, "source", "json")?;
+        let source = match source.as_deref() {
+            None | Some("sender") => WithdrawFrom::Sender,
+            Some("sponsor") => WithdrawFrom::Sponsor,
+            Some(other) => {
+                return Err(FfiError::new(
+                    -3,
+                    "unknown_withdrawal_source",
+                    format!("unknown withdrawal source `{other}`"),
+                )
+                .field("source"))
+            }
+        };
+        Ok(FundsWithdrawal { amount, coin_type, source })
+    });
+    match parsed {
+        Ok(w) => builder.funds_withdrawal(w).id as i64,
+        Err(e) => fail("input_funds_withdrawal", e),
+    }
 }
 
 // ── Pure-value helpers ────────────────────────────────────────────────────────
@@ -229,11 +561,14 @@ pub unsafe extern "C" fn pure_address(
     let bytes = slice::from_raw_parts(ptr, len);
     let s = match std::str::from_utf8(bytes) {
         Ok(s)  => s,
-        Err(_) => return -1,
+        Err(e) => return fail("pure_address", FfiError::new(-1, "utf8", e.to_string())),
     };
     match Address::from_str(s.trim().trim_matches('"')) {
         Ok(addr) => (&mut *builder).pure(&addr).id as i64,
-        Err(_)   => -1,
+        Err(_)   => fail(
+            "pure_address",
+            FfiError::new(-1, "invalid_address", format!("invalid address `{s}`")),
+        ),
     }
 }
 
@@ -245,7 +580,7 @@ pub unsafe extern "C" fn pure_raw_bcs(
     ptr: *const u8,
     len: usize,
 ) -> i64 {
-    let bytes = slice::from_raw_parts(ptr, len).to_vec();
+    let bytes = if len == 0 { vec![] } else { slice::from_raw_parts(ptr, len).to_vec() };
     (&mut *builder).pure_bytes(bytes).id as i64
 }
 
@@ -257,8 +592,8 @@ pub unsafe extern "C" fn pure_raw_bcs(
 /// - `base_id`   – Argument ID returned by `command_split_coins`.
 /// - `sub_index` – 0-based index of the desired result (0 … N-1).
 ///
-/// Returns the new Argument ID.  Use it wherever a plain Argument ID is
-/// accepted (move_call, transfer_objects, etc.).
+/// Returns the new Argument ID, or -4 if `base_id` does not exist.  Use it
+/// wherever a plain Argument ID is accepted (move_call, transfer_objects, etc.).
 #[no_mangle]
 pub unsafe extern "C" fn nested_result(
     builder: *mut TransactionBuilder,
@@ -266,6 +601,9 @@ pub unsafe extern "C" fn nested_result(
     sub_index: u64,
 ) -> i64 {
     let builder = &mut *builder;
+    if let Err(e) = check_argument(builder, base_id as usize, "base_id") {
+        return fail("nested_result", e);
+    }
     let nested = Argument { id: base_id as usize, sub_index: Some(sub_index as usize) };
     let new_id = builder.arguments.len();
     builder.arguments.insert(new_id, ResolvedArgument::ReplaceWith(nested));
@@ -297,22 +635,13 @@ pub unsafe extern "C" fn nested_result(
 ///   -1  JSON parse error
 ///   -2  invalid module identifier
 ///   -3  invalid function identifier
+///   -4  unknown argument ID
 #[no_mangle]
 pub unsafe extern "C" fn command_move_call(
     builder: *mut TransactionBuilder,
     json_ptr: *const u8,
     json_len: usize,
 ) -> i64 {
-    #[derive(serde::Deserialize)]
-    struct Req {
-        package: Address,
-        module: String,
-        function: String,
-        #[serde(default)]
-        type_args: Vec<TypeTag>,
-        #[serde(default)]
-        arguments: Vec<CallArg>,
-    }
     #[derive(serde::Deserialize)]
     #[serde(untagged)]
     enum CallArg {
@@ -322,29 +651,37 @@ pub unsafe extern "C" fn command_move_call(
 
     let builder = &mut *builder;
     let bytes = slice::from_raw_parts(json_ptr, json_len);
-    let req: Req = match serde_json::from_slice(bytes) {
-        Ok(r)  => r,
-        Err(_) => return -1,
-    };
-    let module = match Identifier::from_str(&req.module) {
-        Ok(m)  => m,
-        Err(_) => return -2,
-    };
-    let function = match Identifier::from_str(&req.function) {
-        Ok(f)  => f,
-        Err(_) => return -3,
+    let parsed = parse_object(bytes).and_then(|mut obj| {
+        let package: Address = take(&mut obj, "package", "invalid_address")?;
+        let module: String = take(&mut obj, "module", "json")?;
+        let function: String = take(&mut obj, "function", "json")?;
+        let type_args: Vec<TypeTag> = take_list(&mut obj, "type_args", "invalid_type_tag")?;
+        let arguments: Vec<CallArg> = take_list(&mut obj, "arguments", "json")?;
+        let module = identifier(&module, -2, "module")?;
+        let function = identifier(&function, -3, "function")?;
+        let ids: Vec<usize> = arguments
+            .iter()
+            .map(|a| match a { CallArg::Id { id } => *id, CallArg::PureBcs { .. } => 0 })
+            .collect();
+        for (i, a) in arguments.iter().enumerate() {
+            if let CallArg::Id { .. } = a {
+                check_argument(builder, ids[i], "arguments").map_err(|e| e.index(i))?;
+            }
+        }
+        Ok((Function::new(package, module, function).with_type_args(type_args), arguments))
+    });
+    let (function, arguments) = match parsed {
+        Ok(p) => p,
+        Err(e) => return fail("command_move_call", e),
     };
     let mut args = Vec::new();
-    for a in req.arguments {
+    for a in arguments {
         match a {
             CallArg::Id      { id }       => args.push(Argument::new(id)),
             CallArg::PureBcs { pure_bcs } => args.push(builder.pure_bytes(pure_bcs)),
         }
     }
-    builder.move_call(
-        Function::new(req.package, module, function).with_type_args(req.type_args),
-        args,
-    ).id as i64
+    builder.move_call(function, args).id as i64
 }
 
 /// SplitCoins — split `coin_arg_id` into N coins of the specified amounts.
@@ -359,7 +696,7 @@ pub unsafe extern "C" fn command_move_call(
 /// Use `nested_result(base, 0)`, `nested_result(base, 1)`, … to address
 /// individual coins.
 ///
-/// Returns -1 if `count` is 0.
+/// Returns -1 if `count` is 0, -4 on an unknown argument ID.
 #[no_mangle]
 pub unsafe extern "C" fn command_split_coins(
     builder: *mut TransactionBuilder,
@@ -367,11 +704,21 @@ pub unsafe extern "C" fn command_split_coins(
     amount_arg_ids_ptr: *const u64,
     count: usize,
 ) -> i64 {
-    if count == 0 { return -1; }
+    if count == 0 {
+        return fail(
+            "command_split_coins",
+            FfiError::new(-1, "empty_list", "at least one amount required").field("amounts"),
+        );
+    }
     let builder = &mut *builder;
+    let amount_ids = ids_from_raw(amount_arg_ids_ptr, count);
+    if let Err(e) = check_argument(builder, coin_arg_id as usize, "coin")
+        .and_then(|_| check_arguments(builder, &amount_ids, "amounts"))
+    {
+        return fail("command_split_coins", e);
+    }
     let coin    = Argument::new(coin_arg_id as usize);
-    let amounts = slice::from_raw_parts(amount_arg_ids_ptr, count)
-        .iter().map(|&id| Argument::new(id as usize)).collect();
+    let amounts = amount_ids.into_iter().map(Argument::new).collect();
     builder.split_coins(coin, amounts)[0].id as i64
 }
 
@@ -381,7 +728,7 @@ pub unsafe extern "C" fn command_split_coins(
 /// - `source_arg_ids_ptr` – pointer to a C array of `count` uint64 Argument IDs.
 /// - `count`              – number of coins to merge (must be ≥ 1).
 ///
-/// Returns 1 on success, -1 if `count` is 0.
+/// Returns 1 on success, -1 if `count` is 0, -4 on an unknown argument ID.
 #[no_mangle]
 pub unsafe extern "C" fn command_merge_coins(
     builder: *mut TransactionBuilder,
@@ -389,11 +736,21 @@ pub unsafe extern "C" fn command_merge_coins(
     source_arg_ids_ptr: *const u64,
     count: usize,
 ) -> i32 {
-    if count == 0 { return -1; }
+    if count == 0 {
+        return fail(
+            "command_merge_coins",
+            FfiError::new(-1, "empty_list", "at least one source required").field("sources"),
+        ) as i32;
+    }
     let builder = &mut *builder;
+    let source_ids = ids_from_raw(source_arg_ids_ptr, count);
+    if let Err(e) = check_argument(builder, target_coin_arg_id as usize, "coin")
+        .and_then(|_| check_arguments(builder, &source_ids, "sources"))
+    {
+        return fail("command_merge_coins", e) as i32;
+    }
     let target  = Argument::new(target_coin_arg_id as usize);
-    let sources = slice::from_raw_parts(source_arg_ids_ptr, count)
-        .iter().map(|&id| Argument::new(id as usize)).collect();
+    let sources = source_ids.into_iter().map(Argument::new).collect();
     builder.merge_coins(target, sources);
     1
 }
@@ -404,7 +761,7 @@ pub unsafe extern "C" fn command_merge_coins(
 /// - `count`              – number of objects to transfer (must be ≥ 1).
 /// - `recipient_arg_id`   – Argument ID returned by `pure_address`.
 ///
-/// Returns 1 on success, -1 if `count` is 0.
+/// Returns 1 on success, -1 if `count` is 0, -4 on an unknown argument ID.
 #[no_mangle]
 pub unsafe extern "C" fn command_transfer_objects(
     builder: *mut TransactionBuilder,
@@ -412,10 +769,20 @@ pub unsafe extern "C" fn command_transfer_objects(
     count: usize,
     recipient_arg_id: u64,
 ) -> i32 {
-    if count == 0 { return -1; }
+    if count == 0 {
+        return fail(
+            "command_transfer_objects",
+            FfiError::new(-1, "empty_list", "at least one object required").field("objects"),
+        ) as i32;
+    }
     let builder  = &mut *builder;
-    let objects  = slice::from_raw_parts(object_arg_ids_ptr, count)
-        .iter().map(|&id| Argument::new(id as usize)).collect();
+    let object_ids = ids_from_raw(object_arg_ids_ptr, count);
+    if let Err(e) = check_arguments(builder, &object_ids, "objects")
+        .and_then(|_| check_argument(builder, recipient_arg_id as usize, "recipient"))
+    {
+        return fail("command_transfer_objects", e) as i32;
+    }
+    let objects  = object_ids.into_iter().map(Argument::new).collect();
     let recipient = Argument::new(recipient_arg_id as usize);
     builder.transfer_objects(objects, recipient);
     1
@@ -431,6 +798,7 @@ pub unsafe extern "C" fn command_transfer_objects(
 /// Returns the result Argument ID, or:
 ///   -1  bad type-tag UTF-8
 ///   -2  type-tag parse error
+///   -4  unknown argument ID
 #[no_mangle]
 pub unsafe extern "C" fn command_make_move_vec(
     builder: *mut TransactionBuilder,
@@ -447,20 +815,30 @@ pub unsafe extern "C" fn command_make_move_vec(
         let bytes = slice::from_raw_parts(type_tag_ptr, type_tag_len);
         let s = match std::str::from_utf8(bytes) {
             Ok(s)  => s,
-            Err(_) => return -1,
+            Err(e) => {
+                return fail(
+                    "command_make_move_vec",
+                    FfiError::new(-1, "utf8", e.to_string()).field("type_tag"),
+                )
+            }
         };
         match s.trim().parse::<TypeTag>() {
             Ok(t)  => Some(t),
-            Err(_) => return -2,
+            Err(e) => {
+                return fail(
+                    "command_make_move_vec",
+                    FfiError::new(-2, "invalid_type_tag", format!("invalid type tag `{s}`: {e}"))
+                        .field("type_tag"),
+                )
+            }
         }
     };
 
-    let elements = if count == 0 || elem_arg_ids_ptr.is_null() {
-        vec![]
-    } else {
-        slice::from_raw_parts(elem_arg_ids_ptr, count)
-            .iter().map(|&id| Argument::new(id as usize)).collect()
-    };
+    let element_ids = ids_from_raw(elem_arg_ids_ptr, count);
+    if let Err(e) = check_arguments(builder, &element_ids, "elements") {
+        return fail("command_make_move_vec", e);
+    }
+    let elements = element_ids.into_iter().map(Argument::new).collect();
 
     builder.make_move_vec(type_tag, elements).id as i64
 }
@@ -481,18 +859,17 @@ pub unsafe extern "C" fn command_publish(
     json_ptr: *const u8,
     json_len: usize,
 ) -> i64 {
-    #[derive(serde::Deserialize)]
-    struct Req {
-        modules: Vec<Vec<u8>>,
-        dependencies: Vec<Address>,
-    }
     let builder = &mut *builder;
     let bytes = slice::from_raw_parts(json_ptr, json_len);
-    let req: Req = match serde_json::from_slice(bytes) {
-        Ok(r)  => r,
-        Err(_) => return -1,
-    };
-    builder.publish(req.modules, req.dependencies).id as i64
+    let parsed = parse_object(bytes).and_then(|mut obj| {
+        let modules: Vec<Vec<u8>> = take_list(&mut obj, "modules", "json")?;
+        let dependencies: Vec<Address> = take_list(&mut obj, "dependencies", "invalid_address")?;
+        Ok((modules, dependencies))
+    });
+    match parsed {
+        Ok((modules, dependencies)) => builder.publish(modules, dependencies).id as i64,
+        Err(e) => fail("command_publish", e),
+    }
 }
 
 /// Upgrade — upgrade an existing Move package.
@@ -509,32 +886,30 @@ pub unsafe extern "C" fn command_publish(
 /// `ticket_arg_id` must be an Argument ID pointing to the `UpgradeTicket`
 /// produced by `0x2::package::authorize_upgrade`.
 ///
-/// Returns the `UpgradeReceipt` Argument ID (≥ 0), or -1 on JSON parse error.
+/// Returns the `UpgradeReceipt` Argument ID (≥ 0), -1 on JSON parse error,
+/// or -4 on an unknown ticket argument.
 #[no_mangle]
 pub unsafe extern "C" fn command_upgrade(
     builder: *mut TransactionBuilder,
     json_ptr: *const u8,
     json_len: usize,
 ) -> i64 {
-    #[derive(serde::Deserialize)]
-    struct Req {
-        modules: Vec<Vec<u8>>,
-        dependencies: Vec<Address>,
-        package: Address,
-        ticket_arg_id: usize,
-    }
     let builder = &mut *builder;
     let bytes = slice::from_raw_parts(json_ptr, json_len);
-    let req: Req = match serde_json::from_slice(bytes) {
-        Ok(r)  => r,
-        Err(_) => return -1,
-    };
-    builder.upgrade(
-        req.modules,
-        req.dependencies,
-        req.package,
-        Argument::new(req.ticket_arg_id),
-    ).id as i64
+    let parsed = parse_object(bytes).and_then(|mut obj| {
+        let modules: Vec<Vec<u8>> = take_list(&mut obj, "modules", "json")?;
+        let dependencies: Vec<Address> = take_list(&mut obj, "dependencies", "invalid_address")?;
+        let package: Address = take(&mut obj, "package", "invalid_address")?;
+        let ticket: usize = take(&mut obj, "ticket_arg_id", "json")?;
+        check_argument(builder, ticket, "ticket_arg_id")?;
+        Ok((modules, dependencies, package, ticket))
+    });
+    match parsed {
+        Ok((modules, dependencies, package, ticket)) => builder
+            .upgrade(modules, dependencies, package, Argument::new(ticket))
+            .id as i64,
+        Err(e) => fail("command_upgrade", e),
+    }
 }
 
 // ── Finalisation ─────────────────────────────────────────────────────────────
@@ -547,18 +922,20 @@ pub unsafe extern "C" fn command_upgrade(
 /// Free with `free_bytes(ptr, payload_len)` where `payload_len` is the u32
 /// read from the first 4 bytes.
 ///
-/// Returns NULL on any build or serialisation error.
+/// Returns NULL on any build or serialisation error; `last_error_message`
+/// describes it.
 ///
 /// IMPORTANT: this call consumes (drops) the builder.
 /// Do NOT call `free_builder` afterwards.
 #[no_mangle]
 pub unsafe extern "C" fn build_transaction(builder: *mut TransactionBuilder) -> *mut u8 {
     let builder = Box::from_raw(builder);
-    let payload = match builder.try_build().and_then(|tx| {
-        bcs::to_bytes(&tx).map_err(|e| crate::error::Error::Input(e.to_string()))
-    }) {
+    let payload = match builder.try_build_bcs().map_err(FfiError::from) {
         Ok(b)  => b,
-        Err(_) => return std::ptr::null_mut(),
+        Err(e) => {
+            fail("build_transaction", e);
+            return std::ptr::null_mut();
+        }
     };
     let total = 4 + payload.len();
     let mut buf = Vec::<u8>::with_capacity(total);
@@ -577,4 +954,4 @@ pub unsafe extern "C" fn build_transaction(builder: *mut TransactionBuilder) ->
 pub unsafe extern "C" fn free_bytes(ptr: *mut u8, payload_len: usize) {
     let total = 4 + payload_len;
     let _ = Vec::from_raw_parts(ptr, total, total);
-}
\ No newline at end of file
+}
@@ -5,5 +5,5 @@ mod ffi;
 #[cfg(feature = "intents")]
 pub(crate) mod intent;
 
-pub use builder::{TransactionBuilder, Argument, ObjectInput, Function};
+pub use builder::{TransactionBuilder, Argument, ObjectInput, Function, FundsWithdrawal, WithdrawFrom};
 pub use error::Error;
\ No newline at end of file
@@ -0,0 +1,306 @@
+// conformance.rs
+//
+// Builds the PTB scenarios of the Go conformance suite (conformance_test.go
+// at the repository root) with the Rust TransactionBuilder and compares the
+// BCS transactions with the golden vectors in testdata/conformance.  The
+// vectors are authored here; every Go backend, gobuilder included, is checked
+// against them.  Keep the scenarios in step with the Go table.
+// funds_withdrawal.hex and inline_bcs.hex still carry a comment saying they
+// were derived by hand from the Sui layouts; regenerating drops it.
+//
+// After an intended change to the encoding, regenerate the vectors with
+//
+//   UPDATE_CONFORMANCE=1 cargo test --test conformance
+
+use std::fmt::Write as _;
+use std::path::PathBuf;
+use std::str::FromStr;
+use sui_sdk_types::{Address, Digest, Identifier, TypeTag};
+use transaction_builder::{
+    Function, FundsWithdrawal, ObjectInput, TransactionBuilder, WithdrawFrom,
+};
+
+// ── Fixtures ──────────────────────────────────────────────────────────────────
+
+const SENDER: Address = address(0xa11ce);
+const RECIPIENT: Address = address(0xb0b);
+const PACKAGE: Address = address(0xc0ffee);
+const GAS: Address = address(0x9a5);
+const COIN_A: Address = address(0xc01a);
+const COIN_B: Address = address(0xc01b);
+const SHARED: Address = address(0x5ba4ed);
+const CAP: Address = address(0xca9);
+const FRAMEWORK: Address = address(0x2);
+
+const DIGEST: [u8; 32] = [
+    1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26,
+    27, 28, 29, 30, 31, 32,
+];
+
+const BUDGET: u64 = 5_000_000;
+const PRICE: u64 = 1_000;
+
+const SUI: &str = "0x0000000000000000000000000000000000000000000000000000000000000002::sui::SUI";
+const COIN_SUI: &str = "0x0000000000000000000000000000000000000000000000000000000000000002::coin::Coin<0x0000000000000000000000000000000000000000000000000000000000000002::sui::SUI>";
+
+const fn address(n: u64) -> Address {
+    let mut bytes = [0u8; 32];
+    let be = n.to_be_bytes();
+    let mut i = 0;
+    while i < 8 {
+        bytes[24 + i] = be[i];
+        i += 1;
+    }
+    Address::new(bytes)
+}
+
+fn digest() -> Digest {
+    Digest::new(DIGEST)
+}
+
+fn type_tag(s: &str) -> TypeTag {
+    s.parse().unwrap_or_else(|e| panic!("type tag `{s}`: {e}"))
+}
+
+fn function(package: Address, module: &str, function: &str) -> Function {
+    Function::new(
+        package,
+        Identifier::from_str(module).unwrap(),
+        Identifier::from_str(function).unwrap(),
+    )
+}
+
+fn modules() -> Vec<Vec<u8>> {
+    vec![
+        vec![0xa1, 0x1c, 0xeb, 0x0b, 1],
+        vec![0xa1, 0x1c, 0xeb, 0x0b, 2],
+    ]
+}
+
+fn dependencies() -> Vec<Address> {
+    vec![address(0x1), address(0x2)]
+}
+
+// ── Scenarios ─────────────────────────────────────────────────────────────────
+
+fn split_transfer(b: &mut TransactionBuilder) {
+    let gas = b.gas();
+    let amounts = vec![b.pure(&100u64), b.pure(&200u64)];
+    let coins = b.split_coins(gas, amounts);
+    let recipient = b.pure(&RECIPIENT);
+    b.transfer_objects(coins, recipient);
+}
+
+fn pure_values(b: &mut TransactionBuilder) {
+    let mut u256 = [0u8; 32];
+    u256[31] = 0x80; // 2^255, little endian
+    let args = vec![
+        b.pure(&true),
+        b.pure(&0x08u8),
+        b.pure(&0x1616u16),
+        b.pure(&0x32323232u32),
+        b.pure(&0x6464646464646464u64),
+        b.pure(&((1u128 << 64) | 2)),
+        b.pure_bytes(u256.to_vec()),
+        b.pure(&RECIPIENT),
+        b.pure(&SHARED),
+        b.pure(&"héllo"),
+        b.pure(&"hello"),
+        b.pure(&vec![0xdeu8, 0xad]),
+        b.pure_bytes(vec![0x2a]),
+        b.pure(&Some(9u64)),
+        b.pure(&None::<u64>),
+        b.pure(&vec![1u8, 2, 3]),
+    ];
+    b.move_call(function(PACKAGE, "pures", "all"), args);
+}
+
+fn objects(b: &mut TransactionBuilder) {
+    let owned = b.object(ObjectInput::owned(COIN_A, 3, digest()));
+    let imm = b.object(ObjectInput::immutable(COIN_B, 4, digest()));
+    let recv = b.object(ObjectInput::receiving(CAP, 5, digest()));
+    let shared = b.object(ObjectInput::shared(SHARED, 6, false));
+    b.move_call(
+        function(PACKAGE, "objects", "take")
+            .with_type_args(vec![type_tag(SUI), type_tag(COIN_SUI)]),
+        vec![owned, imm, recv, shared],
+    );
+}
+
+fn object_reuse(b: &mut TransactionBuilder) {
+    // The same shared object taken immutably then mutably is one mutable
+    // input; the same owned object twice is one input.
+    let first = b.object(ObjectInput::shared(SHARED, 6, false));
+    let second = b.object(ObjectInput::shared(SHARED, 6, true));
+    let coin = b.object(ObjectInput::owned(COIN_A, 3, digest()));
+    let again = b.object(ObjectInput::owned(COIN_A, 3, digest()));
+    b.move_call(function(PACKAGE, "objects", "read"), vec![first, coin]);
+    b.move_call(function(PACKAGE, "objects", "write"), vec![second, again]);
+}
+
+fn merge_coins(b: &mut TransactionBuilder) {
+    let a = b.object(ObjectInput::owned(COIN_A, 3, digest()));
+    let bb = b.object(ObjectInput::owned(COIN_B, 4, digest()));
+    let gas = b.gas();
+    b.merge_coins(gas, vec![a, bb]);
+}
+
+fn make_move_vec(b: &mut TransactionBuilder) {
+    let elements = vec![b.pure(&1u64), b.pure(&2u64)];
+    let typed = b.make_move_vec(Some(type_tag("u64")), elements);
+    let a = b.object(ObjectInput::owned(COIN_A, 3, digest()));
+    let bb = b.object(ObjectInput::owned(COIN_B, 4, digest()));
+    let untyped = b.make_move_vec(None, vec![a, bb]);
+    b.move_call(function(PACKAGE, "vec", "take"), vec![typed, untyped]);
+}
+
+fn move_call_results(b: &mut TransactionBuilder) {
+    // Raw BCS arguments mixed with Arguments, and results fed to later
+    // commands whole and by index.
+    let gas = b.gas();
+    let five = b.pure_bytes(vec![5, 0, 0, 0, 0, 0, 0, 0]);
+    let pair = b.move_call(
+        function(PACKAGE, "calls", "pair").with_type_args(vec![type_tag(SUI)]),
+        vec![five, gas],
+    );
+    let one = b.move_call(function(PACKAGE, "calls", "one"), vec![pair]);
+    let second = pair.to_nested(2)[1];
+    let flag = b.pure_bytes(vec![1]);
+    b.move_call(function(PACKAGE, "calls", "use"), vec![second, one, flag]);
+}
+
+fn inline_bcs(b: &mut TransactionBuilder) {
+    // Inline BCS arguments: a value equal to an existing pure input shares
+    // it, a vector<u8> with a two-byte length prefix, and bytes with the
+    // high bit set.
+    let mut long = vec![0xc8, 0x01];
+    long.extend(0..200u8);
+    let args = vec![
+        b.pure(&7u64),
+        b.pure_bytes(vec![7, 0, 0, 0, 0, 0, 0, 0]),
+        b.pure_bytes(long),
+        b.pure_bytes(vec![0xff, 0x80, 0x7f]),
+    ];
+    b.move_call(function(PACKAGE, "bcs", "take"), args);
+}
+
+fn publish(b: &mut TransactionBuilder) {
+    let cap = b.publish(modules(), dependencies());
+    let sender = b.pure(&SENDER);
+    b.transfer_objects(vec![cap], sender);
+}
+
+fn upgrade(b: &mut TransactionBuilder) {
+    let cap = b.object(ObjectInput::owned(CAP, 5, digest()));
+    let policy = b.pure(&0u8);
+    let package_digest = b.pure(&DIGEST.to_vec());
+    let ticket = b.move_call(
+        function(FRAMEWORK, "package", "authorize_upgrade"),
+        vec![cap, policy, package_digest],
+    );
+    let receipt = b.upgrade(modules(), dependencies(), PACKAGE, ticket);
+    b.move_call(
+        function(FRAMEWORK, "package", "commit_upgrade"),
+        vec![cap, receipt],
+    );
+}
+
+fn funds_withdrawal(b: &mut TransactionBuilder) {
+    // Identical withdrawals are separate inputs, unlike pure values.
+    let w = FundsWithdrawal {
+        amount: 5,
+        coin_type: type_tag(SUI),
+        source: WithdrawFrom::Sender,
+    };
+    let first = b.funds_withdrawal(w.clone());
+    let second = b.funds_withdrawal(w.clone());
+    let sponsor = b.funds_withdrawal(FundsWithdrawal {
+        source: WithdrawFrom::Sponsor,
+        ..w
+    });
+    b.move_call(
+        function(PACKAGE, "funds", "take").with_type_args(vec![type_tag(SUI)]),
+        vec![first, second, sponsor],
+    );
+}
+
+type Build = fn(&mut TransactionBuilder);
+
+const SCENARIOS: &[(&str, Build)] = &[
+    ("split_transfer", split_transfer),
+    ("pure_values", pure_values),
+    ("objects", objects),
+    ("object_reuse", object_reuse),
+    ("merge_coins", merge_coins),
+    ("make_move_vec", make_move_vec),
+    ("move_call_results", move_call_results),
+    ("inline_bcs", inline_bcs),
+    ("publish", publish),
+    ("upgrade", upgrade),
+    ("funds_withdrawal", funds_withdrawal),
+];
+
+// ── Golden vectors ────────────────────────────────────────────────────────────
+
+fn golden_path(name: &str) -> PathBuf {
+    PathBuf::from(env!("CARGO_MANIFEST_DIR"))
+        .join("../testdata/conformance")
+        .join(format!("{name}.hex"))
+}
+
+/// Hex, 32 bytes per line, as read by the Go suite.
+fn encode_golden(tx: &[u8]) -> String {
+    let mut s = String::new();
+    for line in tx.chunks(32) {
+        for byte in line {
+            write!(s, "{byte:02x}").unwrap();
+        }
+        s.push('\n');
+    }
+    s
+}
+
+/// Lines starting with `#` are comments.
+fn decode_golden(text: &str) -> Vec<u8> {
+    let hex: String = text
+        .lines()
+        .filter(|line| !line.starts_with('#'))
+        .flat_map(str::split_whitespace)
+        .collect();
+    (0..hex.len())
+        .step_by(2)
+        .map(|i| u8::from_str_radix(&hex[i..i + 2], 16).expect("malformed golden vector"))
+        .collect()
+}
+
+// ── Runner ────────────────────────────────────────────────────────────────────
+
+#[test]
+fn conformance() {
+    let update = std::env::var_os("UPDATE_CONFORMANCE").is_some();
+    let mut failures = Vec::new();
+    for &(name, build) in SCENARIOS {
+        let mut b = TransactionBuilder::new();
+        b.set_sender(SENDER);
+        b.set_gas_budget(BUDGET);
+        b.set_gas_price(PRICE);
+        b.add_gas_objects(vec![ObjectInput::owned(GAS, 7, digest())]);
+        build(&mut b);
+        let got = b.try_build_bcs().unwrap_or_else(|e| panic!("{name}: {e}"));
+
+        let path = golden_path(name);
+        if update {
+            std::fs::write(&path, encode_golden(&got)).unwrap();
+            continue;
+        }
+        let text = std::fs::read_to_string(&path)
+            .unwrap_or_else(|e| panic!("{}: {e} (run with UPDATE_CONFORMANCE=1)", path.display()));
+        if decode_golden(&text) != got {
+            failures.push(format!(
+                "{name}: differs from golden\n got {}",
+                encode_golden(&got)
+            ));
+        }
+    }
+    assert!(failures.is_empty(), "{}", failures.join("\n"));
+}
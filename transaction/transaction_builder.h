@@ -29,6 +29,15 @@
  *     -1   JSON/UTF-8 parse error  (or "count == 0" sentinel)
  *     -2   bad digest / bad type-tag
  *     -3   unknown object kind / bad function identifier
+ *     -4   unknown Argument ID (the ID was never returned by this builder)
+ *
+ * Every failure also records a JSON description for the calling thread;
+ * read it with last_error_length() / last_error_message():
+ *
+ *   {"function":"input_object","code":-2,"kind":"invalid_digest",
+ *    "message":"…","field":"digest","index":0,"object_id":"0x…"}
+ *
+ * `field`, `index` and `object_id` are present only when they apply.
  *
  * Copyright (c) Mysten Labs, Inc.  SPDX-License-Identifier: Apache-2.0
  */
@@ -71,6 +80,30 @@ uint8_t *alloc(size_t len);
 void dealloc(uint8_t *ptr, size_t len);
 
 
+/* ── Error reporting ─────────────────────────────────────────────────────── */
+
+/**
+ * last_error_length()
+ * Length in bytes of the JSON error recorded by the last failing call on
+ * this thread, or 0 if none has been recorded.
+ */
+size_t last_error_length(void);
+
+/**
+ * last_error_message(buf, len)
+ * Copy the last error JSON (not NUL-terminated) into `buf`.
+ * Returns the number of bytes written, or -1 if `len` is too small.
+ * The error is kept until the next failure or clear_last_error().
+ */
+int64_t last_error_message(uint8_t *buf, size_t len);
+
+/**
+ * clear_last_error()
+ * Forget the error recorded on this thread.
+ */
+void clear_last_error(void);
+
+
 /* ── Builder lifecycle ───────────────────────────────────────────────────── */
 
 /**
@@ -89,6 +122,16 @@ TransactionBuilder *new_builder(void);
  */
 void free_builder(TransactionBuilder *builder);
 
+/**
+ * clone_builder(builder)
+ * Copy a builder that has not been built, so the same commands can be built
+ * again (e.g. with a different gas budget; see set_gas).  Argument IDs are
+ * the same in both.  The copy is freed or consumed like any builder.
+ * Returns NULL, with last_error set, if the builder holds unresolved
+ * intents.
+ */
+TransactionBuilder *clone_builder(const TransactionBuilder *builder);
+
 
 /* ── Configuration ───────────────────────────────────────────────────────── */
 
@@ -121,6 +164,21 @@ int32_t add_gas_object(TransactionBuilder *builder,
                        const uint8_t      *json_ptr,
                        size_t              json_len);
 
+/**
+ * set_gas(builder, json_ptr, json_len)
+ * Replace gas settings from JSON; every field is optional.  A present
+ * "gas" list replaces the gas objects added so far.
+ *
+ * JSON shape:
+ *   {"gas_budget":10000000,"gas_price":1000,
+ *    "gas":[{"id":"0x<hex>","version":2,"digest":"<base58>"}]}
+ *
+ * Returns  1 on success, -1 on JSON parse error, -2 on invalid digest.
+ */
+int32_t set_gas(TransactionBuilder *builder,
+                const uint8_t      *json_ptr,
+                size_t              json_len);
+
 /**
  * gas_argument(builder)
  * Register (or retrieve) the gas-coin pseudo-input.
@@ -151,6 +209,23 @@ int64_t input_object(TransactionBuilder *builder,
                      const uint8_t      *json_ptr,
                      size_t              json_len);
 
+/**
+ * input_funds_withdrawal(builder, json_ptr, json_len)
+ *
+ * Push a withdrawal of up to `amount` from an address balance.  Every call
+ * adds a new input.
+ *   {"amount":N,"coin_type":"0x2::sui::SUI","source":"sender"}
+ * `source` is "sender" (default) or "sponsor".
+ *
+ * Returns Argument ID (>= 0) on success.
+ *   -1  JSON parse error
+ *   -2  bad coin type tag
+ *   -3  unknown source string
+ */
+int64_t input_funds_withdrawal(TransactionBuilder *builder,
+                               const uint8_t      *json_ptr,
+                               size_t              json_len);
+
 
 /* ── Pure-value helpers ──────────────────────────────────────────────────── */
 
@@ -208,7 +283,7 @@ int64_t pure_raw_bcs(TransactionBuilder *builder,
  *   base_id   – Argument ID returned by command_split_coins (or similar).
  *   sub_index – 0-based index into the result tuple.
  *
- * Returns the new Argument ID.
+ * Returns the new Argument ID, or -4 if base_id is unknown.
  * Example: to access the 2nd split coin — nested_result(builder, base, 1).
  */
 int64_t nested_result(TransactionBuilder *builder,
@@ -238,6 +313,7 @@ int64_t nested_result(TransactionBuilder *builder,
  *   -1  JSON parse error
  *   -2  invalid module identifier
  *   -3  invalid function identifier
+ *   -4  unknown Argument ID
  */
 int64_t command_move_call(TransactionBuilder *builder,
                           const uint8_t      *json_ptr,
@@ -254,7 +330,7 @@ int64_t command_move_call(TransactionBuilder *builder,
  *
  * Returns the BASE Argument ID shared by all result coins.
  * Address individual results with nested_result(base, 0..count-1).
- * Returns -1 if count == 0.
+ * Returns -1 if count == 0, -4 on an unknown Argument ID.
  */
 int64_t command_split_coins(TransactionBuilder *builder,
                             uint64_t            coin_arg_id,
@@ -269,7 +345,7 @@ int64_t command_split_coins(TransactionBuilder *builder,
  *   source_arg_ids_ptr – C array of `count` Argument IDs to merge.
  *   count              – number of source coins (must be >= 1).
  *
- * Returns 1 on success, -1 if count == 0.
+ * Returns 1 on success, -1 if count == 0, -4 on an unknown Argument ID.
  */
 int32_t command_merge_coins(TransactionBuilder *builder,
                             uint64_t            target_coin_arg_id,
@@ -284,7 +360,7 @@ int32_t command_merge_coins(TransactionBuilder *builder,
  *   count              – number of objects (must be >= 1).
  *   recipient_arg_id   – Argument ID from pure_address().
  *
- * Returns 1 on success, -1 if count == 0.
+ * Returns 1 on success, -1 if count == 0, -4 on an unknown Argument ID.
  */
 int32_t command_transfer_objects(TransactionBuilder *builder,
                                  const uint64_t     *object_arg_ids_ptr,
@@ -306,6 +382,7 @@ int32_t command_transfer_objects(TransactionBuilder *builder,
  * Returns result Argument ID (>= 0) on success.
  *   -1  bad type-tag UTF-8
  *   -2  type-tag parse error
+ *   -4  unknown Argument ID
  */
 int64_t command_make_move_vec(TransactionBuilder *builder,
                               const uint8_t      *type_tag_ptr,
@@ -343,7 +420,8 @@ int64_t command_publish(TransactionBuilder *builder,
  * `ticket_arg_id` must point to the UpgradeTicket from
  * 0x2::package::authorize_upgrade.
  *
- * Returns the UpgradeReceipt Argument ID (>= 0), or -1 on JSON parse error.
+ * Returns the UpgradeReceipt Argument ID (>= 0), -1 on JSON parse error,
+ * or -4 on an unknown ticket Argument ID.
  */
 int64_t command_upgrade(TransactionBuilder *builder,
                         const uint8_t      *json_ptr,
@@ -365,12 +443,11 @@ int64_t command_upgrade(TransactionBuilder *builder,
  *   2. Read the next payload_len bytes → your BCS payload.
  *   3. Call free_bytes(ptr, payload_len) to release the buffer.
  *
- * Returns NULL on any build or serialisation error.
+ * Returns NULL on any build or serialisation error; last_error_message()
+ * names the missing field or incomplete object.
  *
- * IMPORTANT: This call CONSUMES (drops) the builder.
- *            Do NOT call free_builder() after a successful call.
- *            On NULL return the builder is still valid and must be freed with
- *            free_builder().
+ * IMPORTANT: This call CONSUMES (drops) the builder, whether or not it
+ *            succeeds.  Do NOT call free_builder() afterwards.
  */
 uint8_t *build_transaction(TransactionBuilder *builder);
 
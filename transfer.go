@@ -0,0 +1,187 @@
+// transfer.go
+//
+// Transfer of an arbitrary coin type.
+//
+// The sender's Coin<T> objects are listed with an object_type filter, the
+// largest are selected until they cover Amount, and the PTB
+//
+//	MergeCoins(coin0, [coin1 … coinN])
+//	SplitCoins(coin0, [Amount])
+//	TransferObjects([result], Recipient)
+//
+// is built with the coins as owned inputs.  Gas is paid from separate SUI
+// coins.  For SUI itself the selected coins become the gas payment and the
+// amount is split off the gas coin instead.
+
+package gosuisdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/block-vision/sui-go-sdk/signer"
+	pb "github.com/pictorx/go-sui-sdk/sui_rpc_proto/generated"
+	"github.com/pictorx/go-sui-sdk/typetag"
+	"google.golang.org/grpc"
+)
+
+// isSuiType reports whether coinType is 0x2::sui::SUI.
+func isSuiType(coinType typetag.TypeTag) bool { return coinType.Equal(SuiCoin.Type) }
+
+// SelectCoins picks the largest coins until their balance covers amount.
+// At most limit coins are selected.
+func SelectCoins(coins []*pb.Object, amount uint64, limit int) ([]*pb.Object, error) {
+	sorted := make([]*pb.Object, len(coins))
+	copy(sorted, coins)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetBalance() > sorted[j].GetBalance()
+	})
+
+	var total uint64
+	for i, coin := range sorted {
+		if i == limit {
+			break
+		}
+		total += coin.GetBalance()
+		if total >= amount {
+			return sorted[:i+1], nil
+		}
+	}
+	return nil, fmt.Errorf("insufficient balance: need %d, found %d in %d coins", amount, total, min(len(sorted), limit))
+}
+
+// TransferCoin sends Amount of CoinType from Sender to Recipient.
+type TransferCoin struct {
+	Sender    Address
+	Recipient Address
+	CoinType  typetag.TypeTag // e.g. typetag.MustParse("0x…::usdc::USDC")
+	Amount    uint64
+	Gasbudget uint64
+	Gasprice  uint64
+
+	// GasCoins pays for gas.  When empty, the largest SUI coins covering
+	// Gasbudget are selected (excluding any coin being transferred).
+	GasCoins []*pb.Object
+}
+
+// selectInputs returns the coins to transfer from and the gas coins.
+func (t *TransferCoin) selectInputs(conn *grpc.ClientConn, ctx context.Context) (coins, gas []*pb.Object, err error) {
+	if isSuiType(t.CoinType) {
+		if len(t.GasCoins) > 0 {
+			return nil, t.GasCoins, nil
+		}
+		owned, err := listAllCoins(conn, t.Sender, SuiCoin.Type, ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		gas, err := SelectCoins(owned, t.Amount+t.Gasbudget, MaxGasObjects)
+		return nil, gas, err
+	}
+
+	owned, err := listAllCoins(conn, t.Sender, t.CoinType, ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	coins, err = SelectCoins(owned, t.Amount, MaxInputObjects)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", t.CoinType, err)
+	}
+
+	gas = t.GasCoins
+	if len(gas) == 0 {
+		suiCoins, err := listAllCoins(conn, t.Sender, SuiCoin.Type, ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if gas, err = SelectCoins(suiCoins, t.Gasbudget, MaxGasObjects); err != nil {
+			return nil, nil, fmt.Errorf("gas: %w", err)
+		}
+	}
+	return coins, gas, nil
+}
+
+func (t *TransferCoin) buildTx(backend Backend, budget uint64, coins, gas []*pb.Object) ([]byte, error) {
+	b, err := backend.NewBuilder()
+	if err != nil {
+		return nil, err
+	}
+	built := false
+	defer func() {
+		if !built {
+			b.Free()
+		}
+	}()
+
+	if err := b.SetConfig(t.Sender, budget, t.Gasprice); err != nil {
+		return nil, err
+	}
+	for _, g := range gas {
+		if err := addGasObject(b, g); err != nil {
+			return nil, err
+		}
+	}
+
+	var primary uint64
+	var total uint64
+	if len(coins) == 0 {
+		if primary, err = b.GasArgument(); err != nil {
+			return nil, err
+		}
+	} else {
+		args := make([]uint64, 0, len(coins))
+		for _, c := range coins {
+			arg, err := inputOwned(b, c)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			total += c.GetBalance()
+		}
+		primary = args[0]
+		for start := 1; start < len(args); start += MaxArguments {
+			end := min(start+MaxArguments, len(args))
+			if err := b.MergeCoins(primary, args[start:end]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Send the merged coin as is when it holds exactly Amount.
+	coin := primary
+	if len(coins) == 0 || total != t.Amount {
+		amt, err := b.PureU64(t.Amount)
+		if err != nil {
+			return nil, err
+		}
+		res, err := b.SplitCoins(primary, []uint64{amt})
+		if err != nil {
+			return nil, err
+		}
+		if coin, err = b.NestedResult(res, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	rec, err := b.PureAddress(t.Recipient)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.TransferObjects([]uint64{coin}, rec); err != nil {
+		return nil, err
+	}
+
+	built = true
+	return b.Build()
+}
+
+func (t *TransferCoin) SignExecuteTx(conn *grpc.ClientConn, backend Backend, account *signer.Signer, ctx context.Context) (*pb.ExecuteTransactionResponse, error) {
+	coins, gas, err := t.selectInputs(conn, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return estimateSignExecute(conn, account, t.Gasbudget, func(budget uint64) ([]byte, error) {
+		return t.buildTx(backend, budget, coins, gas)
+	}, nil, ctx)
+}
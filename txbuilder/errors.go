@@ -0,0 +1,136 @@
+// errors.go
+//
+// Structured errors reported by the transaction builder backends.
+
+package txbuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ErrorKind classifies a builder error.  The values match the "kind" field of
+// the Rust FFI's last_error record.
+type ErrorKind string
+
+const (
+	KindJSON              ErrorKind = "json"
+	KindUTF8              ErrorKind = "utf8"
+	KindInvalidAddress    ErrorKind = "invalid_address"
+	KindInvalidDigest     ErrorKind = "invalid_digest"
+	KindInvalidIdentifier ErrorKind = "invalid_identifier"
+	KindInvalidTypeTag    ErrorKind = "invalid_type_tag"
+	KindUnknownObjectKind ErrorKind = "unknown_object_kind"
+	KindUnknownSource     ErrorKind = "unknown_withdrawal_source"
+	KindEmptyList         ErrorKind = "empty_list"
+	KindUnknownArgument   ErrorKind = "unknown_argument"
+	KindSerialization     ErrorKind = "serialization"
+	KindInvalidValue      ErrorKind = "invalid_value" // Go-side pure value checks
+
+	// KindSignatureMismatch is a MoveCall that does not fit the on-chain
+	// function signature (Go-side validation at build time).
+	KindSignatureMismatch ErrorKind = "signature_mismatch"
+
+	// KindLimitExceeded is a call or transaction over a protocol limit
+	// (Go-side, see WithLimits).  Field names the ProtocolConfig attribute.
+	KindLimitExceeded ErrorKind = "limit_exceeded"
+
+	// Build-time kinds, one per Rust builder error variant.
+	KindInput                  ErrorKind = "input"
+	KindWrongGasObject         ErrorKind = "wrong_gas_object"
+	KindMissingObjectID        ErrorKind = "missing_object_id"
+	KindMissingVersion         ErrorKind = "missing_version"
+	KindMissingDigest          ErrorKind = "missing_digest"
+	KindMissingSender          ErrorKind = "missing_sender"
+	KindMissingGasObjects      ErrorKind = "missing_gas_objects"
+	KindMissingGasBudget       ErrorKind = "missing_gas_budget"
+	KindMissingGasPrice        ErrorKind = "missing_gas_price"
+	KindMissingObjectKind      ErrorKind = "missing_object_kind"
+	KindSharedObjectMutability ErrorKind = "shared_object_mutability"
+	KindIncompleteObject       ErrorKind = "incomplete_object"
+
+	// KindUnknown is used when a backend reported a failure code but no
+	// last_error record.
+	KindUnknown ErrorKind = "unknown"
+)
+
+// Error is a failed builder call.  Field, Index and ObjectID are set when the
+// failure can be pinned to a request field, list element or object.
+type Error struct {
+	Func     string    `json:"function"` // FFI function that failed
+	Code     int64     `json:"code"`     // negative return code, 0 for Build
+	Kind     ErrorKind `json:"kind"`
+	Message  string    `json:"message"`
+	Field    string    `json:"field,omitempty"`
+	Index    *int      `json:"index,omitempty"`
+	ObjectID string    `json:"object_id,omitempty"`
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Func)
+	if e.Field != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Field)
+		if e.Index != nil {
+			fmt.Fprintf(&b, "[%d]", *e.Index)
+		}
+	}
+	if e.ObjectID != "" {
+		fmt.Fprintf(&b, " (object %s)", e.ObjectID)
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// Is reports whether target is an *Error of the same Kind, so callers can
+// write errors.Is(err, txbuilder.ErrMissingGasBudget).
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Func == "" && t.Kind == e.Kind
+}
+
+// Sentinels for errors.Is.  They match any *Error of the same Kind.
+var (
+	ErrUnknownArgument   = &Error{Kind: KindUnknownArgument}
+	ErrEmptyList         = &Error{Kind: KindEmptyList}
+	ErrMissingSender     = &Error{Kind: KindMissingSender}
+	ErrMissingGasObjects = &Error{Kind: KindMissingGasObjects}
+	ErrMissingGasBudget  = &Error{Kind: KindMissingGasBudget}
+	ErrMissingGasPrice   = &Error{Kind: KindMissingGasPrice}
+	ErrIncompleteObject  = &Error{Kind: KindIncompleteObject}
+	ErrSignatureMismatch = &Error{Kind: KindSignatureMismatch}
+	ErrLimitExceeded     = &Error{Kind: KindLimitExceeded}
+)
+
+// NewError returns an *Error without a field.
+func NewError(fn string, code int64, kind ErrorKind, msg string) *Error {
+	return &Error{Func: fn, Code: code, Kind: kind, Message: msg}
+}
+
+// WithField sets Field and returns e.
+func (e *Error) WithField(field string) *Error {
+	e.Field = field
+	return e
+}
+
+// WithIndex sets Index and returns e.
+func (e *Error) WithIndex(i int) *Error {
+	e.Index = &i
+	return e
+}
+
+// DecodeError turns a last_error record into an *Error.  When the record is
+// empty or unreadable it falls back to KindUnknown with the bare code.
+func DecodeError(fn string, code int64, record []byte) *Error {
+	var e Error
+	if len(record) == 0 || json.Unmarshal(record, &e) != nil || e.Kind == "" {
+		return NewError(fn, code, KindUnknown, fmt.Sprintf("failed (code %d)", code))
+	}
+	if e.Func == "" {
+		e.Func = fn
+	}
+	return &e
+}
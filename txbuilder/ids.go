@@ -0,0 +1,172 @@
+// ids.go
+//
+// Addresses, object IDs and transaction digests.  All three are 32 bytes on
+// chain; the distinct types keep a digest from being passed where an object
+// ID is expected and move parsing to the edge of the program, where a typo
+// can be reported against the string the user wrote.
+
+package txbuilder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// Address is a Sui account address.
+type Address [32]byte
+
+// ObjectID is the ID of an object or package.
+type ObjectID [32]byte
+
+// Digest is a transaction, object or checkpoint digest.
+type Digest [32]byte
+
+// Well-known packages and system objects.
+var (
+	MoveStdlibPackageID   = ObjectID{31: 0x1}
+	SuiFrameworkPackageID = ObjectID{31: 0x2}
+	SuiSystemPackageID    = ObjectID{31: 0x3}
+
+	SuiSystemStateObjectID = ObjectID{31: 0x5}
+	ClockObjectID          = ObjectID{31: 0x6}
+	RandomObjectID         = ObjectID{31: 0x8}
+	DenyListObjectID       = ObjectID{30: 0x4, 31: 0x3}
+)
+
+// ── Parsing ───────────────────────────────────────────────────────────────────
+
+// ParseAddress decodes a 0x-prefixed hex address, left-padding short forms
+// such as "0x2" with zeros.
+func ParseAddress(s string) (Address, error) {
+	a, ok := parseHex32(s)
+	if !ok {
+		return Address{}, fmt.Errorf("invalid address %q", s)
+	}
+	return a, nil
+}
+
+// ParseObjectID decodes a 0x-prefixed hex object ID, left-padding short
+// forms such as "0x6" with zeros.
+func ParseObjectID(s string) (ObjectID, error) {
+	a, ok := parseHex32(s)
+	if !ok {
+		return ObjectID{}, fmt.Errorf("invalid object ID %q", s)
+	}
+	return a, nil
+}
+
+// ParseDigest decodes a base58 digest.
+func ParseDigest(s string) (Digest, error) {
+	var d Digest
+	b := base58.Decode(s)
+	if len(b) != len(d) {
+		return d, fmt.Errorf("invalid digest %q", s)
+	}
+	copy(d[:], b)
+	return d, nil
+}
+
+// MustParseAddress is ParseAddress for constants; it panics on error.
+func MustParseAddress(s string) Address {
+	a, err := ParseAddress(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// MustParseObjectID is ParseObjectID for constants; it panics on error.
+func MustParseObjectID(s string) ObjectID {
+	id, err := ParseObjectID(s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// MustParseDigest is ParseDigest for constants; it panics on error.
+func MustParseDigest(s string) Digest {
+	d, err := ParseDigest(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func parseHex32(s string) ([32]byte, bool) {
+	var out [32]byte
+	h, ok := strings.CutPrefix(s, "0x")
+	if !ok || h == "" || len(h) > 64 {
+		return out, false
+	}
+	if _, err := hex.Decode(out[:], []byte(strings.Repeat("0", 64-len(h))+h)); err != nil {
+		return out, false
+	}
+	return out, true
+}
+
+// ── Rendering ─────────────────────────────────────────────────────────────────
+
+// String renders a as 0x followed by 64 hex digits.
+func (a Address) String() string { return "0x" + hex.EncodeToString(a[:]) }
+
+// Short renders a without leading zeros, e.g. "0x2".
+func (a Address) Short() string { return shortHex(a) }
+
+// IsZero reports whether a is 0x0.
+func (a Address) IsZero() bool { return a == Address{} }
+
+// String renders id as 0x followed by 64 hex digits.
+func (id ObjectID) String() string { return "0x" + hex.EncodeToString(id[:]) }
+
+// Short renders id without leading zeros, e.g. "0x6".
+func (id ObjectID) Short() string { return shortHex(id) }
+
+// IsZero reports whether id is 0x0.
+func (id ObjectID) IsZero() bool { return id == ObjectID{} }
+
+// String renders d in base58.
+func (d Digest) String() string { return base58.Encode(d[:]) }
+
+// IsZero reports whether d is all zeros.
+func (d Digest) IsZero() bool { return d == Digest{} }
+
+func shortHex(b [32]byte) string {
+	h := strings.TrimLeft(hex.EncodeToString(b[:]), "0")
+	if h == "" {
+		h = "0"
+	}
+	return "0x" + h
+}
+
+// ── Text and JSON ─────────────────────────────────────────────────────────────
+
+// MarshalText implements encoding.TextMarshaler.
+func (a Address) MarshalText() ([]byte, error) { return []byte(a.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *Address) UnmarshalText(text []byte) (err error) {
+	*a, err = ParseAddress(string(text))
+	return err
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id ObjectID) MarshalText() ([]byte, error) { return []byte(id.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *ObjectID) UnmarshalText(text []byte) (err error) {
+	*id, err = ParseObjectID(string(text))
+	return err
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Digest) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Digest) UnmarshalText(text []byte) (err error) {
+	*d, err = ParseDigest(string(text))
+	return err
+}
@@ -0,0 +1,348 @@
+// limits.go
+//
+// Protocol limits enforced on the Go side before a transaction is sent.
+//
+// The validators reject a PTB that exceeds the limits of the current
+// protocol version; WithLimits makes any Builder fail at the offending call
+// instead, with an *Error of KindLimitExceeded naming the protocol config
+// attribute.
+//
+//	b := txbuilder.WithLimits(inner, limits)
+//	_, err := b.MoveCall(...) // errors.Is(err, txbuilder.ErrLimitExceeded)
+
+package txbuilder
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+// Limits is a protocol limits profile.  Each field is named after its
+// ProtocolConfig attribute; zero leaves the limit unchecked.
+type Limits struct {
+	MaxCommands          uint64 // max_programmable_tx_commands
+	MaxInputObjects      uint64 // max_input_objects
+	MaxArguments         uint64 // max_arguments, per command
+	MaxPureArgumentSize  uint64 // max_pure_argument_size, in BCS bytes
+	MaxTxSize            uint64 // max_tx_size_bytes, of the built TransactionData
+	MaxTypeArgumentDepth uint64 // max_type_argument_depth
+	MaxGasBudget         uint64 // max_tx_gas
+	MaxGasPaymentObjects uint64 // max_gas_payment_objects
+}
+
+// Mainnet values of the limits that size batches, as untyped constants for
+// code that splits work across commands or transactions.
+const (
+	DefaultMaxCommands          = 1024
+	DefaultMaxInputObjects      = 2048
+	DefaultMaxArguments         = 512
+	DefaultMaxGasPaymentObjects = 256
+)
+
+// DefaultLimits are the mainnet values at the time of writing.  Prefer
+// limits loaded from the node, which follow protocol upgrades.
+var DefaultLimits = Limits{
+	MaxCommands:          DefaultMaxCommands,
+	MaxInputObjects:      DefaultMaxInputObjects,
+	MaxArguments:         DefaultMaxArguments,
+	MaxPureArgumentSize:  16 * 1024,
+	MaxTxSize:            128 * 1024,
+	MaxTypeArgumentDepth: 16,
+	MaxGasBudget:         50_000_000_000,
+	MaxGasPaymentObjects: DefaultMaxGasPaymentObjects,
+}
+
+// LimitsFromAttributes reads a profile from ProtocolConfig attributes.
+// Attributes missing from attrs leave their limit unchecked.
+func LimitsFromAttributes(attrs map[string]string) (Limits, error) {
+	var l Limits
+	for _, f := range []struct {
+		name string
+		dst  *uint64
+	}{
+		{"max_programmable_tx_commands", &l.MaxCommands},
+		{"max_input_objects", &l.MaxInputObjects},
+		{"max_arguments", &l.MaxArguments},
+		{"max_pure_argument_size", &l.MaxPureArgumentSize},
+		{"max_tx_size_bytes", &l.MaxTxSize},
+		{"max_type_argument_depth", &l.MaxTypeArgumentDepth},
+		{"max_tx_gas", &l.MaxGasBudget},
+		{"max_gas_payment_objects", &l.MaxGasPaymentObjects},
+	} {
+		v, ok := attrs[f.name]
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return Limits{}, fmt.Errorf("protocol config %s: %w", f.name, err)
+		}
+		*f.dst = n
+	}
+	return l, nil
+}
+
+// exceeded reports whether n is over limit, which is unchecked when zero.
+func exceeded(n, limit uint64) bool { return limit != 0 && n > limit }
+
+func limitErr(fn, attr string, n, limit uint64, what string) *Error {
+	return NewError(fn, 0, KindLimitExceeded,
+		fmt.Sprintf("%s %d exceeds the protocol limit of %d", what, n, limit)).WithField(attr)
+}
+
+// ── Limited builder ───────────────────────────────────────────────────────────
+
+// Limited is a Builder checking every call against a Limits profile before
+// forwarding it.  Input objects are counted by ID, as the backends merge
+// repeated uses of one object into one input.
+type Limited struct {
+	b        Builder
+	l        Limits
+	commands uint64
+	gas      uint64
+	objects  map[ObjectID]struct{}
+}
+
+// limitedCloner is a Limited over a Cloner.
+type limitedCloner struct{ *Limited }
+
+var (
+	_ Builder = (*Limited)(nil)
+	_ Cloner  = limitedCloner{}
+)
+
+// WithLimits wraps b so that calls exceeding l fail before they reach it.
+// The result implements Cloner when b does.
+func WithLimits(b Builder, l Limits) Builder {
+	lb := &Limited{b: b, l: l, objects: map[ObjectID]struct{}{}}
+	if _, ok := b.(Cloner); ok {
+		return limitedCloner{lb}
+	}
+	return lb
+}
+
+// Limits returns the profile being enforced.
+func (l *Limited) Limits() Limits { return l.l }
+
+func (l *Limited) checkBudget(fn string, budget uint64) error {
+	if exceeded(budget, l.l.MaxGasBudget) {
+		return limitErr(fn, "max_tx_gas", budget, l.l.MaxGasBudget, "gas budget")
+	}
+	return nil
+}
+
+func (l *Limited) checkPure(fn string, bcsBytes []byte) error {
+	if n := uint64(len(bcsBytes)); exceeded(n, l.l.MaxPureArgumentSize) {
+		return limitErr(fn, "max_pure_argument_size", n, l.l.MaxPureArgumentSize, "pure argument of size")
+	}
+	return nil
+}
+
+func (l *Limited) checkTypeArgs(fn string, typeArgs ...typetag.TypeTag) error {
+	for i, t := range typeArgs {
+		if d := uint64(t.Depth()); exceeded(d, l.l.MaxTypeArgumentDepth) {
+			return limitErr(fn, "max_type_argument_depth", d, l.l.MaxTypeArgumentDepth, "type argument depth").WithIndex(i)
+		}
+	}
+	return nil
+}
+
+// command checks one more command taking nArgs arguments.  It is counted
+// once the backend has accepted it.
+func (l *Limited) command(fn string, nArgs int) error {
+	if n := l.commands + 1; exceeded(n, l.l.MaxCommands) {
+		return limitErr(fn, "max_programmable_tx_commands", n, l.l.MaxCommands, "command count")
+	}
+	if n := uint64(nArgs); exceeded(n, l.l.MaxArguments) {
+		return limitErr(fn, "max_arguments", n, l.l.MaxArguments, "argument count")
+	}
+	return nil
+}
+
+func (l *Limited) counted(id uint64, err error) (uint64, error) {
+	if err == nil {
+		l.commands++
+	}
+	return id, err
+}
+
+func (l *Limited) checkSize(tx []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	if n := uint64(len(tx)); exceeded(n, l.l.MaxTxSize) {
+		return nil, limitErr("build_transaction", "max_tx_size_bytes", n, l.l.MaxTxSize, "transaction size")
+	}
+	return tx, nil
+}
+
+func (l *Limited) SetConfig(sender Address, gasBudget, gasPrice uint64) error {
+	if err := l.checkBudget("set_config", gasBudget); err != nil {
+		return err
+	}
+	return l.b.SetConfig(sender, gasBudget, gasPrice)
+}
+
+func (l *Limited) AddGasObject(id ObjectID, version uint64, digest Digest) error {
+	if n := l.gas + 1; exceeded(n, l.l.MaxGasPaymentObjects) {
+		return limitErr("add_gas_object", "max_gas_payment_objects", n, l.l.MaxGasPaymentObjects, "gas object count")
+	}
+	if err := l.b.AddGasObject(id, version, digest); err != nil {
+		return err
+	}
+	l.gas++
+	return nil
+}
+
+func (l *Limited) GasArgument() (uint64, error) { return l.b.GasArgument() }
+
+func (l *Limited) InputObject(id ObjectID, version uint64, digest Digest, kind ObjectKind, mutable bool) (uint64, error) {
+	_, seen := l.objects[id]
+	if n := uint64(len(l.objects)) + 1; !seen && exceeded(n, l.l.MaxInputObjects) {
+		e := limitErr("input_object", "max_input_objects", n, l.l.MaxInputObjects, "input object count")
+		e.ObjectID = id.String()
+		return 0, e
+	}
+	argID, err := l.b.InputObject(id, version, digest, kind, mutable)
+	if err == nil {
+		l.objects[id] = struct{}{}
+	}
+	return argID, err
+}
+
+func (l *Limited) InputFundsWithdrawal(w FundsWithdrawal) (uint64, error) {
+	if err := l.checkTypeArgs("input_funds_withdrawal", w.CoinType); err != nil {
+		return 0, err
+	}
+	return l.b.InputFundsWithdrawal(w)
+}
+
+func (l *Limited) PureBool(v bool) (uint64, error)          { return l.b.PureBool(v) }
+func (l *Limited) PureU8(v uint8) (uint64, error)           { return l.b.PureU8(v) }
+func (l *Limited) PureU16(v uint16) (uint64, error)         { return l.b.PureU16(v) }
+func (l *Limited) PureU32(v uint32) (uint64, error)         { return l.b.PureU32(v) }
+func (l *Limited) PureU64(v uint64) (uint64, error)         { return l.b.PureU64(v) }
+func (l *Limited) PureU128(hi, lo uint64) (uint64, error)   { return l.b.PureU128(hi, lo) }
+func (l *Limited) PureAddress(addr Address) (uint64, error) { return l.b.PureAddress(addr) }
+
+func (l *Limited) PureRawBCS(bcsBytes []byte) (uint64, error) {
+	if err := l.checkPure("pure_raw_bcs", bcsBytes); err != nil {
+		return 0, err
+	}
+	return l.b.PureRawBCS(bcsBytes)
+}
+
+func (l *Limited) PureString(s string) (uint64, error)      { return PureString(l, s) }
+func (l *Limited) PureASCIIString(s string) (uint64, error) { return PureASCIIString(l, s) }
+func (l *Limited) PureBytes(v []byte) (uint64, error)       { return l.PureRawBCS(EncodeBytes(v)) }
+func (l *Limited) PureU256(v *big.Int) (uint64, error)      { return PureU256(l, v) }
+func (l *Limited) PureID(id ObjectID) (uint64, error)       { return PureID(l, id) }
+func (l *Limited) PureOption(value []byte) (uint64, error)  { return l.PureRawBCS(EncodeOption(value)) }
+func (l *Limited) PureVector(elems [][]byte) (uint64, error) {
+	return l.PureRawBCS(EncodeVectorOf(elems))
+}
+
+func (l *Limited) NestedResult(baseID, subIndex uint64) (uint64, error) {
+	return l.b.NestedResult(baseID, subIndex)
+}
+
+func (l *Limited) MoveCall(pkg ObjectID, module, function string, typeArgs []typetag.TypeTag, args []MoveCallArg) (uint64, error) {
+	const fn = "command_move_call"
+	if err := l.command(fn, len(args)); err != nil {
+		return 0, err
+	}
+	if err := l.checkTypeArgs(fn, typeArgs...); err != nil {
+		return 0, err
+	}
+	for i, a := range args {
+		if n := uint64(len(a.PureBCS)); a.ArgID == nil && exceeded(n, l.l.MaxPureArgumentSize) {
+			return 0, limitErr(fn, "max_pure_argument_size", n, l.l.MaxPureArgumentSize, "pure argument of size").WithIndex(i)
+		}
+	}
+	return l.counted(l.b.MoveCall(pkg, module, function, typeArgs, args))
+}
+
+func (l *Limited) SplitCoins(coinArgID uint64, amountArgIDs []uint64) (uint64, error) {
+	if err := l.command("command_split_coins", len(amountArgIDs)+1); err != nil {
+		return 0, err
+	}
+	return l.counted(l.b.SplitCoins(coinArgID, amountArgIDs))
+}
+
+func (l *Limited) MergeCoins(targetCoinArgID uint64, sourceArgIDs []uint64) error {
+	if err := l.command("command_merge_coins", len(sourceArgIDs)+1); err != nil {
+		return err
+	}
+	_, err := l.counted(0, l.b.MergeCoins(targetCoinArgID, sourceArgIDs))
+	return err
+}
+
+func (l *Limited) TransferObjects(objectArgIDs []uint64, recipientArgID uint64) error {
+	if err := l.command("command_transfer_objects", len(objectArgIDs)+1); err != nil {
+		return err
+	}
+	_, err := l.counted(0, l.b.TransferObjects(objectArgIDs, recipientArgID))
+	return err
+}
+
+func (l *Limited) MakeMoveVec(elemType *typetag.TypeTag, elemArgIDs []uint64) (uint64, error) {
+	const fn = "command_make_move_vec"
+	if err := l.command(fn, len(elemArgIDs)); err != nil {
+		return 0, err
+	}
+	if elemType != nil {
+		if err := l.checkTypeArgs(fn, *elemType); err != nil {
+			return 0, err
+		}
+	}
+	return l.counted(l.b.MakeMoveVec(elemType, elemArgIDs))
+}
+
+func (l *Limited) Publish(modules [][]byte, dependencies []ObjectID) (uint64, error) {
+	if err := l.command("command_publish", 0); err != nil {
+		return 0, err
+	}
+	return l.counted(l.b.Publish(modules, dependencies))
+}
+
+func (l *Limited) Upgrade(modules [][]byte, dependencies []ObjectID, packageID ObjectID, ticketArgID uint64) (uint64, error) {
+	if err := l.command("command_upgrade", 1); err != nil {
+		return 0, err
+	}
+	return l.counted(l.b.Upgrade(modules, dependencies, packageID, ticketArgID))
+}
+
+// Build builds the transaction and checks its size.  The builder is
+// consumed even when the size is over the limit.
+func (l *Limited) Build() ([]byte, error) { return l.checkSize(l.b.Build()) }
+
+func (l *Limited) Free() { l.b.Free() }
+
+// Clone copies the builder together with its counts.
+func (c limitedCloner) Clone() (Builder, error) {
+	inner, err := c.b.(Cloner).Clone()
+	if err != nil {
+		return nil, err
+	}
+	lb := *c.Limited
+	lb.b = inner
+	lb.objects = make(map[ObjectID]struct{}, len(c.objects))
+	for id := range c.objects {
+		lb.objects[id] = struct{}{}
+	}
+	return limitedCloner{&lb}, nil
+}
+
+// BuildWith checks the new gas settings and the size of the result.
+func (c limitedCloner) BuildWith(gasBudget, gasPrice uint64, gas []GasObject) ([]byte, error) {
+	if err := c.checkBudget("set_gas", gasBudget); err != nil {
+		return nil, err
+	}
+	if n := uint64(len(gas)); exceeded(n, c.l.MaxGasPaymentObjects) {
+		return nil, limitErr("set_gas", "max_gas_payment_objects", n, c.l.MaxGasPaymentObjects, "gas object count")
+	}
+	return c.checkSize(c.b.(Cloner).BuildWith(gasBudget, gasPrice, gas))
+}
@@ -0,0 +1,194 @@
+package txbuilder_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pictorx/go-sui-sdk/gobuilder"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+// wantLimit fails unless err is ErrLimitExceeded for the attribute field.
+func wantLimit(t *testing.T, err error, field string) *txbuilder.Error {
+	t.Helper()
+	var e *txbuilder.Error
+	if !errors.Is(err, txbuilder.ErrLimitExceeded) || !errors.As(err, &e) || e.Field != field {
+		t.Fatalf("got %v, want limit_exceeded for %s", err, field)
+	}
+	return e
+}
+
+func split(b txbuilder.Builder) error {
+	gas, err := b.GasArgument()
+	if err != nil {
+		return err
+	}
+	amount, err := b.PureU64(1)
+	if err != nil {
+		return err
+	}
+	_, err = b.SplitCoins(gas, []uint64{amount})
+	return err
+}
+
+func input(id txbuilder.ObjectID) func(txbuilder.Builder) error {
+	return func(b txbuilder.Builder) error {
+		_, err := b.InputObject(id, 3, digest, txbuilder.ObjectKindOwned, true)
+		return err
+	}
+}
+
+func moveCall(typeArgs []typetag.TypeTag, args ...txbuilder.MoveCallArg) func(txbuilder.Builder) error {
+	return func(b txbuilder.Builder) error {
+		_, err := b.MoveCall(pkg, "m", "f", typeArgs, args)
+		return err
+	}
+}
+
+func pureArgs(n int) []txbuilder.MoveCallArg {
+	args := make([]txbuilder.MoveCallArg, n)
+	for i := range args {
+		args[i] = txbuilder.ArgBCS([]byte{byte(i)})
+	}
+	return args
+}
+
+// Each limit lets every call up to it through and fails the call crossing
+// it.
+func TestLimited(t *testing.T) {
+	coinSUI := typetag.Coin(typetag.SUI).Tag()
+	tests := []struct {
+		name   string
+		limits txbuilder.Limits
+		// steps must succeed; cross must fail for field.
+		steps []func(txbuilder.Builder) error
+		cross func(txbuilder.Builder) error
+		field string
+		index int // -1 when the error has no index
+	}{
+		{"commands", txbuilder.Limits{MaxCommands: 2},
+			[]func(txbuilder.Builder) error{split, split}, split,
+			"max_programmable_tx_commands", -1},
+		{"arguments", txbuilder.Limits{MaxArguments: 3},
+			[]func(txbuilder.Builder) error{moveCall(nil, pureArgs(3)...)}, moveCall(nil, pureArgs(4)...),
+			"max_arguments", -1},
+		{"pure size", txbuilder.Limits{MaxPureArgumentSize: 8},
+			[]func(txbuilder.Builder) error{func(b txbuilder.Builder) error {
+				_, err := b.PureBytes(make([]byte, 7))
+				return err
+			}},
+			func(b txbuilder.Builder) error {
+				_, err := b.PureBytes(make([]byte, 8))
+				return err
+			},
+			"max_pure_argument_size", -1},
+		{"inline pure size", txbuilder.Limits{MaxPureArgumentSize: 8},
+			[]func(txbuilder.Builder) error{moveCall(nil, txbuilder.ArgBCS(make([]byte, 8)))},
+			moveCall(nil, txbuilder.ArgBCS([]byte{1}), txbuilder.ArgBCS(make([]byte, 9))),
+			"max_pure_argument_size", 1},
+		{"type argument depth", txbuilder.Limits{MaxTypeArgumentDepth: 2},
+			[]func(txbuilder.Builder) error{moveCall([]typetag.TypeTag{coinSUI})},
+			moveCall([]typetag.TypeTag{typetag.SUI, typetag.VectorOf(coinSUI)}),
+			"max_type_argument_depth", 1},
+		{"gas budget", txbuilder.Limits{MaxGasBudget: 100},
+			[]func(txbuilder.Builder) error{func(b txbuilder.Builder) error { return b.SetConfig(sender, 100, 1) }},
+			func(b txbuilder.Builder) error { return b.SetConfig(sender, 101, 1) },
+			"max_tx_gas", -1},
+		{"gas objects", txbuilder.Limits{MaxGasPaymentObjects: 1},
+			[]func(txbuilder.Builder) error{func(b txbuilder.Builder) error { return b.AddGasObject(gasID, 7, digest) }},
+			func(b txbuilder.Builder) error { return b.AddGasObject(coinA, 3, digest) },
+			"max_gas_payment_objects", -1},
+		// Repeated uses of one object are one input.
+		{"input objects", txbuilder.Limits{MaxInputObjects: 2},
+			[]func(txbuilder.Builder) error{input(coinA), input(coinA), input(coinB), input(coinA)}, input(shared),
+			"max_input_objects", -1},
+		{"transaction size", txbuilder.Limits{MaxTxSize: 200},
+			[]func(txbuilder.Builder) error{
+				func(b txbuilder.Builder) error { return b.SetConfig(sender, 5_000_000, 1_000) },
+				func(b txbuilder.Builder) error { return b.AddGasObject(gasID, 7, digest) },
+				moveCall(nil, txbuilder.ArgBCS(make([]byte, 100))),
+			},
+			func(b txbuilder.Builder) error {
+				_, err := b.Build()
+				return err
+			},
+			"max_tx_size_bytes", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := txbuilder.WithLimits(gobuilder.NewBuilder(), tt.limits)
+			defer b.Free()
+			for i, step := range tt.steps {
+				if err := step(b); err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+			}
+			e := wantLimit(t, tt.cross(b), tt.field)
+			switch {
+			case tt.index < 0 && e.Index != nil:
+				t.Errorf("index = %d, want none", *e.Index)
+			case tt.index >= 0 && (e.Index == nil || *e.Index != tt.index):
+				t.Errorf("index = %v, want %d", e.Index, tt.index)
+			}
+			if tt.field == "max_input_objects" && e.ObjectID != shared.String() {
+				t.Errorf("object_id = %q, want %s", e.ObjectID, shared)
+			}
+		})
+	}
+}
+
+// Clones carry the command and object counts; the original does not see
+// what is added to a clone.
+func TestLimitedClone(t *testing.T) {
+	limits := txbuilder.Limits{MaxCommands: 2, MaxInputObjects: 1, MaxGasBudget: 100, MaxGasPaymentObjects: 1}
+	b := txbuilder.WithLimits(gobuilder.NewBuilder(), limits)
+	defer b.Free()
+	for _, step := range []func(txbuilder.Builder) error{
+		func(b txbuilder.Builder) error { return b.SetConfig(sender, 100, 1) },
+		func(b txbuilder.Builder) error { return b.AddGasObject(gasID, 7, digest) },
+		input(coinA),
+		split,
+	} {
+		if err := step(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := b.(txbuilder.Cloner).Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Free()
+	if err := input(coinA)(c); err != nil {
+		t.Errorf("clone: repeated object: %v", err)
+	}
+	wantLimit(t, input(coinB)(c), "max_input_objects")
+	if err := split(c); err != nil {
+		t.Fatalf("clone: second command: %v", err)
+	}
+	wantLimit(t, split(c), "max_programmable_tx_commands")
+
+	if err := split(b); err != nil {
+		t.Errorf("original: second command after the clone's: %v", err)
+	}
+
+	cl := b.(txbuilder.Cloner)
+	_, err = cl.BuildWith(101, 1, nil)
+	wantLimit(t, err, "max_tx_gas")
+	_, err = cl.BuildWith(100, 1, []txbuilder.GasObject{{ID: gasID, Version: 7, Digest: digest}, {ID: coinB, Version: 4, Digest: digest}})
+	wantLimit(t, err, "max_gas_payment_objects")
+	if _, err := cl.BuildWith(100, 1, nil); err != nil {
+		t.Errorf("BuildWith within limits: %v", err)
+	}
+}
+
+// Builders that cannot clone are not wrapped as Cloners.
+func TestWithLimitsCloner(t *testing.T) {
+	if _, ok := txbuilder.WithLimits(gobuilder.NewBuilder(), txbuilder.DefaultLimits).(txbuilder.Cloner); !ok {
+		t.Error("WithLimits(gobuilder) is not a Cloner")
+	}
+	if _, ok := txbuilder.WithLimits(txbuilder.NewRecorder(nil), txbuilder.DefaultLimits).(txbuilder.Cloner); ok {
+		t.Error("WithLimits(Recorder) is a Cloner")
+	}
+}
@@ -0,0 +1,719 @@
+// plan.go
+//
+// Transaction plans: an unbuilt PTB as versioned JSON.
+//
+//	{
+//	  "version": 1,
+//	  "sender": "0xa11ce…",
+//	  "gas_budget": 10000000,
+//	  "gas_price": 1000,
+//	  "inputs": [
+//	    {"object": {"id": "0x5a7e…"}},
+//	    {"pure": {"type": "u64", "value": "100"}},
+//	    {"pure": {"type": "address", "value": "0xb0b…"}}
+//	  ],
+//	  "commands": [
+//	    {"split_coins": {"coin": "gas", "amounts": [{"input": 1}]}},
+//	    {"transfer_objects": {"objects": [{"nested_result": [0, 0]}], "recipient": {"input": 2}}}
+//	  ]
+//	}
+//
+// Command fields follow the JSON payloads of the FFI (ffi.rs).  Arguments
+// are symbolic: "gas", {"input": i}, {"result": i} or
+// {"nested_result": [i, j]}.  An object input with only an id is resolved
+// when the plan is replayed into a builder implementing ObjectResolver.
+// Pure inputs are either typed values or raw BCS bytes ({"bcs": [1, 0]}).
+// A withdrawal from an address balance is
+// {"funds_withdrawal": {"amount": 5, "coin_type": "0x2::sui::SUI"}}.
+//
+// Replay pushes a plan into any Builder; a Recorder wrapped around a
+// Builder exports the calls made on it as a plan.
+
+package txbuilder
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pictorx/go-sui-sdk/bcs"
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+// PlanVersion is the plan format written by this package.
+const PlanVersion = 1
+
+// Plan is an unbuilt programmable transaction.
+type Plan struct {
+	Version   int         `json:"version"`
+	Sender    Address     `json:"sender,omitzero"`
+	GasBudget uint64      `json:"gas_budget,omitempty"`
+	GasPrice  uint64      `json:"gas_price,omitempty"`
+	Gas       []GasObject `json:"gas,omitempty"`
+	Inputs    []Input     `json:"inputs"`
+	Commands  []Command   `json:"commands"`
+}
+
+// GasObject is an owned gas coin.
+type GasObject struct {
+	ID      ObjectID `json:"id"`
+	Version uint64   `json:"version"`
+	Digest  Digest   `json:"digest"`
+}
+
+// Input is an object, pure or funds-withdrawal input; exactly one field is
+// set.
+type Input struct {
+	Object          *ObjectInput     `json:"object,omitempty"`
+	Pure            *PureInput       `json:"pure,omitempty"`
+	FundsWithdrawal *FundsWithdrawal `json:"funds_withdrawal,omitempty"`
+}
+
+// kinds is the number of fields set.
+func (in *Input) kinds() int {
+	n := 0
+	if in.Object != nil {
+		n++
+	}
+	if in.Pure != nil {
+		n++
+	}
+	if in.FundsWithdrawal != nil {
+		n++
+	}
+	return n
+}
+
+// ObjectInput is an object input.  With Kind empty only ID is used and the
+// rest is looked up at build time.
+type ObjectInput struct {
+	ID      ObjectID   `json:"id"`
+	Kind    ObjectKind `json:"kind,omitempty"`
+	Version uint64     `json:"version,omitzero"` // initial shared version for shared objects
+	Digest  Digest     `json:"digest,omitzero"`  // unset for shared objects
+	Mutable bool       `json:"mutable,omitempty"`
+}
+
+// PureInput is a pure value: Type and Value, or pre-encoded BCS.
+//
+// Types are bool, u8, u16, u32, u64, u128, u256 (numbers or decimal
+// strings), address and id (hex strings), string and ascii_string, and
+// vector<u8> (a 0x-prefixed hex string).
+type PureInput struct {
+	Type  string          `json:"type,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+	BCS   Bytes           `json:"bcs,omitempty"`
+}
+
+// Command is one PTB command; exactly one field is set.
+type Command struct {
+	MoveCall        *MoveCallCommand        `json:"move_call,omitempty"`
+	SplitCoins      *SplitCoinsCommand      `json:"split_coins,omitempty"`
+	MergeCoins      *MergeCoinsCommand      `json:"merge_coins,omitempty"`
+	TransferObjects *TransferObjectsCommand `json:"transfer_objects,omitempty"`
+	MakeMoveVec     *MakeMoveVecCommand     `json:"make_move_vec,omitempty"`
+	Publish         *PublishCommand         `json:"publish,omitempty"`
+	Upgrade         *UpgradeCommand         `json:"upgrade,omitempty"`
+}
+
+// MoveCallCommand calls package::module::function.
+type MoveCallCommand struct {
+	Package   ObjectID          `json:"package"`
+	Module    string            `json:"module"`
+	Function  string            `json:"function"`
+	TypeArgs  []typetag.TypeTag `json:"type_args,omitempty"`
+	Arguments []Ref             `json:"arguments"`
+}
+
+// SplitCoinsCommand splits one coin per amount off Coin.
+type SplitCoinsCommand struct {
+	Coin    Ref   `json:"coin"`
+	Amounts []Ref `json:"amounts"`
+}
+
+// MergeCoinsCommand merges Sources into Coin; it has no result.
+type MergeCoinsCommand struct {
+	Coin    Ref   `json:"coin"`
+	Sources []Ref `json:"sources"`
+}
+
+// TransferObjectsCommand sends Objects to Recipient; it has no result.
+type TransferObjectsCommand struct {
+	Objects   []Ref `json:"objects"`
+	Recipient Ref   `json:"recipient"`
+}
+
+// MakeMoveVecCommand builds a vector<Type> from Elements.
+type MakeMoveVecCommand struct {
+	Type     *typetag.TypeTag `json:"type,omitempty"` // inferred from the elements when nil
+	Elements []Ref            `json:"elements"`
+}
+
+// PublishCommand publishes Modules; the result is the UpgradeCap.
+type PublishCommand struct {
+	Modules      []Bytes    `json:"modules"`
+	Dependencies []ObjectID `json:"dependencies"`
+}
+
+// UpgradeCommand upgrades Package with Ticket; the result is the
+// UpgradeReceipt.
+type UpgradeCommand struct {
+	Modules      []Bytes    `json:"modules"`
+	Dependencies []ObjectID `json:"dependencies"`
+	Package      ObjectID   `json:"package"`
+	Ticket       Ref        `json:"ticket"`
+}
+
+// name returns the set command's JSON name, or "" when none or several are
+// set.
+func (c *Command) name() string {
+	name := ""
+	for _, k := range []struct {
+		name string
+		set  bool
+	}{
+		{"move_call", c.MoveCall != nil},
+		{"split_coins", c.SplitCoins != nil},
+		{"merge_coins", c.MergeCoins != nil},
+		{"transfer_objects", c.TransferObjects != nil},
+		{"make_move_vec", c.MakeMoveVec != nil},
+		{"publish", c.Publish != nil},
+		{"upgrade", c.Upgrade != nil},
+	} {
+		if k.set {
+			if name != "" {
+				return ""
+			}
+			name = k.name
+		}
+	}
+	return name
+}
+
+// hasResult reports whether the command produces a value.
+func (c *Command) hasResult() bool {
+	return c.MergeCoins == nil && c.TransferObjects == nil
+}
+
+// refs returns every argument the command uses.
+func (c *Command) refs() []Ref {
+	switch {
+	case c.MoveCall != nil:
+		return c.MoveCall.Arguments
+	case c.SplitCoins != nil:
+		return append([]Ref{c.SplitCoins.Coin}, c.SplitCoins.Amounts...)
+	case c.MergeCoins != nil:
+		return append([]Ref{c.MergeCoins.Coin}, c.MergeCoins.Sources...)
+	case c.TransferObjects != nil:
+		return append(append([]Ref(nil), c.TransferObjects.Objects...), c.TransferObjects.Recipient)
+	case c.MakeMoveVec != nil:
+		return c.MakeMoveVec.Elements
+	case c.Upgrade != nil:
+		return []Ref{c.Upgrade.Ticket}
+	}
+	return nil
+}
+
+// ── Refs ──────────────────────────────────────────────────────────────────────
+
+// RefKind is the kind of argument a Ref names.
+type RefKind uint8
+
+const (
+	RefGas RefKind = iota
+	RefInput
+	RefResult
+	RefNestedResult
+)
+
+// Ref is a symbolic argument.  Index is the input or command index; Sub is
+// the value index of a nested result.
+type Ref struct {
+	Kind  RefKind
+	Index int
+	Sub   int
+}
+
+// GasRef is the gas coin.
+var GasRef = Ref{Kind: RefGas}
+
+// InputRef returns input i.
+func InputRef(i int) Ref { return Ref{Kind: RefInput, Index: i} }
+
+// ResultRef returns the result of command i.
+func ResultRef(i int) Ref { return Ref{Kind: RefResult, Index: i} }
+
+// NestedResultRef returns value j of the result of command i.
+func NestedResultRef(i, j int) Ref { return Ref{Kind: RefNestedResult, Index: i, Sub: j} }
+
+func (r Ref) String() string {
+	switch r.Kind {
+	case RefGas:
+		return "gas"
+	case RefInput:
+		return fmt.Sprintf("input %d", r.Index)
+	case RefResult:
+		return fmt.Sprintf("result %d", r.Index)
+	default:
+		return fmt.Sprintf("nested result %d.%d", r.Index, r.Sub)
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r Ref) MarshalJSON() ([]byte, error) {
+	switch r.Kind {
+	case RefGas:
+		return []byte(`"gas"`), nil
+	case RefInput:
+		return fmt.Appendf(nil, `{"input":%d}`, r.Index), nil
+	case RefResult:
+		return fmt.Appendf(nil, `{"result":%d}`, r.Index), nil
+	case RefNestedResult:
+		return fmt.Appendf(nil, `{"nested_result":[%d,%d]}`, r.Index, r.Sub), nil
+	}
+	return nil, fmt.Errorf("invalid ref kind %d", r.Kind)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *Ref) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == `"gas"` {
+		*r = GasRef
+		return nil
+	}
+	var v struct {
+		Input        *int    `json:"input"`
+		Result       *int    `json:"result"`
+		NestedResult *[2]int `json:"nested_result"`
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&v); err != nil {
+		return fmt.Errorf("invalid argument %s: want \"gas\", {\"input\": i}, {\"result\": i} or {\"nested_result\": [i, j]}", data)
+	}
+	switch {
+	case v.Input != nil && v.Result == nil && v.NestedResult == nil:
+		*r = InputRef(*v.Input)
+	case v.Result != nil && v.Input == nil && v.NestedResult == nil:
+		*r = ResultRef(*v.Result)
+	case v.NestedResult != nil && v.Input == nil && v.Result == nil:
+		*r = NestedResultRef(v.NestedResult[0], v.NestedResult[1])
+	default:
+		return fmt.Errorf("invalid argument %s: want exactly one of input, result or nested_result", data)
+	}
+	return nil
+}
+
+// ── Pure values ───────────────────────────────────────────────────────────────
+
+// Encode returns the BCS encoding of the value.
+func (p *PureInput) Encode() ([]byte, error) {
+	if p.Type == "" {
+		if p.BCS == nil {
+			return nil, fmt.Errorf("pure input needs type and value, or bcs")
+		}
+		return p.BCS, nil
+	}
+	if p.BCS != nil {
+		return nil, fmt.Errorf("pure input has both type and bcs")
+	}
+	if len(p.Value) == 0 {
+		return nil, fmt.Errorf("pure %s input has no value", p.Type)
+	}
+
+	switch p.Type {
+	case "bool":
+		var v bool
+		if err := json.Unmarshal(p.Value, &v); err != nil {
+			return nil, fmt.Errorf("invalid bool %s", p.Value)
+		}
+		return EncodePrimitive(v), nil
+	case "u8", "u16", "u32", "u64":
+		bits, _ := strconv.Atoi(p.Type[1:])
+		s, err := numberText(p.Value)
+		if err != nil {
+			return nil, err
+		}
+		v, err := strconv.ParseUint(s, 10, bits)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %s", p.Type, p.Value)
+		}
+		switch bits {
+		case 8:
+			return EncodePrimitive(uint8(v)), nil
+		case 16:
+			return EncodePrimitive(uint16(v)), nil
+		case 32:
+			return EncodePrimitive(uint32(v)), nil
+		}
+		return EncodePrimitive(v), nil
+	case "u128", "u256":
+		s, err := numberText(p.Value)
+		if err != nil {
+			return nil, err
+		}
+		v, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s %s", p.Type, p.Value)
+		}
+		var wide any = bcs.U256{Int: v}
+		if p.Type == "u128" {
+			wide = bcs.U128{Int: v}
+		}
+		buf, err := bcs.Marshal(wide)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %s", p.Type, p.Value)
+		}
+		return buf, nil
+	case "address", "id":
+		var a Address
+		if err := json.Unmarshal(p.Value, &a); err != nil {
+			return nil, err
+		}
+		return a[:], nil
+	case "string", "ascii_string":
+		var s string
+		if err := json.Unmarshal(p.Value, &s); err != nil {
+			return nil, fmt.Errorf("invalid %s %s", p.Type, p.Value)
+		}
+		if !utf8.ValidString(s) {
+			return nil, fmt.Errorf("string is not valid UTF-8")
+		}
+		if p.Type == "ascii_string" {
+			if err := checkASCII(s); err != nil {
+				return nil, err
+			}
+		}
+		return EncodeBytes([]byte(s)), nil
+	case "vector<u8>":
+		var s string
+		if err := json.Unmarshal(p.Value, &s); err != nil || !strings.HasPrefix(s, "0x") {
+			return nil, fmt.Errorf("invalid vector<u8> %s: want a 0x-prefixed hex string", p.Value)
+		}
+		b, err := hex.DecodeString(s[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid vector<u8> %s: %w", p.Value, err)
+		}
+		return EncodeBytes(b), nil
+	}
+	return nil, fmt.Errorf("unknown pure type %q", p.Type)
+}
+
+// numberText returns a JSON number or decimal string as text.
+func numberText(raw json.RawMessage) (string, error) {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return "", fmt.Errorf("invalid number %s", raw)
+	}
+	return n.String(), nil
+}
+
+// typedPure returns a pure input holding a typed value.
+func typedPure(typ string, value any) *PureInput {
+	raw, _ := json.Marshal(value)
+	return &PureInput{Type: typ, Value: raw}
+}
+
+// ── Parsing and validation ────────────────────────────────────────────────────
+
+// ParsePlan decodes and validates a JSON plan.  Unknown fields are
+// rejected so that typos in hand-written plans are caught.
+func ParsePlan(data []byte) (*Plan, error) {
+	var p Plan
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&p); err != nil {
+		return nil, NewError("plan", -1, KindJSON, err.Error())
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Format renders p as indented JSON without HTML escaping, the form to
+// check into git.
+func (p *Plan) Format() ([]byte, error) {
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	if err := e.Encode(p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Validate checks the version, that every input and command has exactly
+// one kind, that pure values encode, and that every argument names an
+// input or the value of an earlier command.
+func (p *Plan) Validate() error {
+	if p.Version != PlanVersion {
+		return NewError("plan", -1, KindInvalidValue,
+			fmt.Sprintf("unsupported plan version %d (want %d)", p.Version, PlanVersion)).WithField("version")
+	}
+	for i, in := range p.Inputs {
+		switch {
+		case in.kinds() != 1:
+			return NewError("plan", -1, KindJSON, "input needs exactly one of object, pure or funds_withdrawal").
+				WithField("inputs").WithIndex(i)
+		case in.Object != nil:
+			switch in.Object.Kind {
+			case "", ObjectKindOwned, ObjectKindImmutable, ObjectKindReceiving, ObjectKindShared:
+			default:
+				return NewError("plan", -1, KindUnknownObjectKind,
+					fmt.Sprintf("unknown object kind %q", in.Object.Kind)).WithField("inputs").WithIndex(i)
+			}
+		case in.Pure != nil:
+			if _, err := in.Pure.Encode(); err != nil {
+				return NewError("plan", -1, KindInvalidValue, err.Error()).WithField("inputs").WithIndex(i)
+			}
+		case in.FundsWithdrawal != nil:
+			switch in.FundsWithdrawal.Source {
+			case "", WithdrawFromSender, WithdrawFromSponsor:
+			default:
+				return NewError("plan", -1, KindUnknownSource,
+					fmt.Sprintf("unknown withdrawal source %q", in.FundsWithdrawal.Source)).WithField("inputs").WithIndex(i)
+			}
+		}
+	}
+	for i := range p.Commands {
+		c := &p.Commands[i]
+		if c.name() == "" {
+			return NewError("plan", -1, KindJSON, "command needs exactly one kind").
+				WithField("commands").WithIndex(i)
+		}
+		for _, r := range c.refs() {
+			if err := p.checkRef(r, i); err != nil {
+				return NewError("plan", -1, KindUnknownArgument, err.Error()).
+					WithField("commands").WithIndex(i)
+			}
+		}
+	}
+	return nil
+}
+
+// checkRef reports whether r is usable by command cmd.
+func (p *Plan) checkRef(r Ref, cmd int) error {
+	switch r.Kind {
+	case RefGas:
+		return nil
+	case RefInput:
+		if r.Index < 0 || r.Index >= len(p.Inputs) {
+			return fmt.Errorf("%s out of range (%d inputs)", r, len(p.Inputs))
+		}
+		return nil
+	case RefResult, RefNestedResult:
+		if r.Index < 0 || r.Index >= cmd {
+			return fmt.Errorf("%s does not name an earlier command", r)
+		}
+		if !p.Commands[r.Index].hasResult() {
+			return fmt.Errorf("%s: %s has no result", r, p.Commands[r.Index].name())
+		}
+		if r.Sub < 0 {
+			return fmt.Errorf("%s: negative index", r)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid ref kind %d", r.Kind)
+}
+
+// ── Replay ────────────────────────────────────────────────────────────────────
+
+// ObjectResolver is a Builder that can take an object by ID alone and fill
+// in its version, digest and kind when built, such as
+// gosuisdk.IntentBuilder.
+type ObjectResolver interface {
+	InputObjectByID(id ObjectID) (uint64, error)
+}
+
+// Replay validates p and pushes it into b: config (when any field is set),
+// gas, inputs in order, then commands.  It does not build or free b.
+func (p *Plan) Replay(b Builder) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if !p.Sender.IsZero() || p.GasBudget != 0 || p.GasPrice != 0 {
+		if err := b.SetConfig(p.Sender, p.GasBudget, p.GasPrice); err != nil {
+			return err
+		}
+	}
+	for _, g := range p.Gas {
+		if err := b.AddGasObject(g.ID, g.Version, g.Digest); err != nil {
+			return err
+		}
+	}
+
+	r := &replay{b: b, results: make([]uint64, len(p.Commands)), nested: map[[2]int]uint64{}}
+	for i, in := range p.Inputs {
+		id, err := r.input(in)
+		if err != nil {
+			return fmt.Errorf("plan input %d: %w", i, err)
+		}
+		r.inputs = append(r.inputs, id)
+	}
+	for i := range p.Commands {
+		if err := r.command(i, &p.Commands[i]); err != nil {
+			return fmt.Errorf("plan command %d (%s): %w", i, p.Commands[i].name(), err)
+		}
+	}
+	return nil
+}
+
+// replay maps plan refs to the Argument IDs of one builder.
+type replay struct {
+	b       Builder
+	gas     *uint64
+	inputs  []uint64
+	results []uint64
+	nested  map[[2]int]uint64
+}
+
+func (r *replay) input(in Input) (uint64, error) {
+	if in.Pure != nil {
+		value, err := in.Pure.Encode()
+		if err != nil {
+			return 0, err
+		}
+		return r.b.PureRawBCS(value)
+	}
+	if in.FundsWithdrawal != nil {
+		return r.b.InputFundsWithdrawal(*in.FundsWithdrawal)
+	}
+	o := in.Object
+	if o.Kind != "" {
+		return r.b.InputObject(o.ID, o.Version, o.Digest, o.Kind, o.Mutable)
+	}
+	res, ok := r.b.(ObjectResolver)
+	if !ok {
+		return 0, NewError("input_object", -1, KindIncompleteObject,
+			fmt.Sprintf("object %s has no kind and %T cannot resolve objects by ID", o.ID.Short(), r.b))
+	}
+	return res.InputObjectByID(o.ID)
+}
+
+func (r *replay) arg(ref Ref) (uint64, error) {
+	switch ref.Kind {
+	case RefGas:
+		if r.gas == nil {
+			id, err := r.b.GasArgument()
+			if err != nil {
+				return 0, err
+			}
+			r.gas = &id
+		}
+		return *r.gas, nil
+	case RefInput:
+		return r.inputs[ref.Index], nil
+	case RefResult:
+		return r.results[ref.Index], nil
+	}
+	key := [2]int{ref.Index, ref.Sub}
+	if id, ok := r.nested[key]; ok {
+		return id, nil
+	}
+	id, err := r.b.NestedResult(r.results[ref.Index], uint64(ref.Sub))
+	if err != nil {
+		return 0, err
+	}
+	r.nested[key] = id
+	return id, nil
+}
+
+func (r *replay) args(refs []Ref) ([]uint64, error) {
+	ids := make([]uint64, len(refs))
+	for i, ref := range refs {
+		id, err := r.arg(ref)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func (r *replay) command(i int, c *Command) error {
+	var (
+		res uint64
+		err error
+	)
+	switch {
+	case c.MoveCall != nil:
+		var ids []uint64
+		if ids, err = r.args(c.MoveCall.Arguments); err != nil {
+			return err
+		}
+		args := make([]MoveCallArg, len(ids))
+		for j, id := range ids {
+			args[j] = ArgID(id)
+		}
+		m := c.MoveCall
+		res, err = r.b.MoveCall(m.Package, m.Module, m.Function, m.TypeArgs, args)
+	case c.SplitCoins != nil:
+		var coin uint64
+		var amounts []uint64
+		if coin, err = r.arg(c.SplitCoins.Coin); err != nil {
+			return err
+		}
+		if amounts, err = r.args(c.SplitCoins.Amounts); err != nil {
+			return err
+		}
+		res, err = r.b.SplitCoins(coin, amounts)
+	case c.MergeCoins != nil:
+		var coin uint64
+		var sources []uint64
+		if coin, err = r.arg(c.MergeCoins.Coin); err != nil {
+			return err
+		}
+		if sources, err = r.args(c.MergeCoins.Sources); err != nil {
+			return err
+		}
+		err = r.b.MergeCoins(coin, sources)
+	case c.TransferObjects != nil:
+		var objects []uint64
+		var recipient uint64
+		if objects, err = r.args(c.TransferObjects.Objects); err != nil {
+			return err
+		}
+		if recipient, err = r.arg(c.TransferObjects.Recipient); err != nil {
+			return err
+		}
+		err = r.b.TransferObjects(objects, recipient)
+	case c.MakeMoveVec != nil:
+		var elems []uint64
+		if elems, err = r.args(c.MakeMoveVec.Elements); err != nil {
+			return err
+		}
+		res, err = r.b.MakeMoveVec(c.MakeMoveVec.Type, elems)
+	case c.Publish != nil:
+		res, err = r.b.Publish(moduleSlices(c.Publish.Modules), c.Publish.Dependencies)
+	case c.Upgrade != nil:
+		var ticket uint64
+		if ticket, err = r.arg(c.Upgrade.Ticket); err != nil {
+			return err
+		}
+		u := c.Upgrade
+		res, err = r.b.Upgrade(moduleSlices(u.Modules), u.Dependencies, u.Package, ticket)
+	}
+	if err != nil {
+		return err
+	}
+	r.results[i] = res
+	return nil
+}
+
+func moduleSlices(modules []Bytes) [][]byte {
+	out := make([][]byte, len(modules))
+	for i, m := range modules {
+		out[i] = m
+	}
+	return out
+}
@@ -0,0 +1,184 @@
+package txbuilder_test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/pictorx/go-sui-sdk/gobuilder"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+var (
+	sender  = txbuilder.MustParseAddress("0xa11ce")
+	pkg     = txbuilder.MustParseObjectID("0xc0ffee")
+	coinA   = txbuilder.MustParseObjectID("0xc01a")
+	coinB   = txbuilder.MustParseObjectID("0xc01b")
+	shared  = txbuilder.MustParseObjectID("0x5ba4ed")
+	capID   = txbuilder.MustParseObjectID("0xca9")
+	gasID   = txbuilder.MustParseObjectID("0x9a5")
+	digest  = txbuilder.Digest{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32}
+	modules = [][]byte{{0xa1, 0x1c, 0xeb, 0x0b, 1}}
+	deps    = []txbuilder.ObjectID{txbuilder.MustParseObjectID("0x1"), txbuilder.MustParseObjectID("0x2")}
+)
+
+// build drives b through every kind of input and command, keeping the
+// first error.
+func build(b txbuilder.Builder) error {
+	var err error
+	id := func(v uint64, e error) uint64 {
+		if err == nil {
+			err = e
+		}
+		return v
+	}
+	do := func(e error) {
+		if err == nil {
+			err = e
+		}
+	}
+
+	do(b.SetConfig(sender, 5_000_000, 1_000))
+	do(b.AddGasObject(gasID, 7, digest))
+	// The first input has Argument ID 0 and is used after a MergeCoins.
+	a := id(b.InputObject(coinA, 3, digest, txbuilder.ObjectKindOwned, true))
+	bb := id(b.InputObject(coinB, 4, digest, txbuilder.ObjectKindOwned, true))
+	do(b.MergeCoins(a, []uint64{bb}))
+	split := id(b.SplitCoins(a, []uint64{id(b.PureU64(1)), id(b.PureU64(2))}))
+
+	pures := []uint64{
+		id(b.PureBool(true)), id(b.PureU8(8)), id(b.PureU16(16)), id(b.PureU32(32)),
+		id(b.PureU128(1, 2)), id(b.PureU256(new(big.Int).Lsh(big.NewInt(1), 255))),
+		id(b.PureAddress(sender)), id(b.PureID(shared)), id(b.PureString("héllo")),
+		id(b.PureASCIIString("hello")), id(b.PureBytes([]byte{0xde, 0xad})),
+		id(b.PureOption([]byte{9, 0, 0, 0, 0, 0, 0, 0})), id(b.PureVector([][]byte{{1}, {2}})),
+		id(b.PureRawBCS([]byte{0x2a})),
+	}
+	args := []txbuilder.MoveCallArg{txbuilder.ArgBCS([]byte{7, 0, 0, 0, 0, 0, 0, 0})}
+	for _, p := range pures {
+		args = append(args, txbuilder.ArgID(p))
+	}
+	id(b.MoveCall(pkg, "pures", "all", nil, args))
+
+	imm := id(b.InputObject(coinB, 4, digest, txbuilder.ObjectKindImmutable, false))
+	recv := id(b.InputObject(capID, 5, digest, txbuilder.ObjectKindReceiving, false))
+	sh := id(b.InputObject(shared, 6, txbuilder.Digest{}, txbuilder.ObjectKindShared, true))
+	w := id(b.InputFundsWithdrawal(txbuilder.FundsWithdrawal{Amount: 5, CoinType: typetag.SUI, Source: txbuilder.WithdrawFromSponsor}))
+	pair := id(b.MoveCall(pkg, "objects", "take", []typetag.TypeTag{typetag.SUI, typetag.Coin(typetag.SUI).Tag()},
+		[]txbuilder.MoveCallArg{txbuilder.ArgID(imm), txbuilder.ArgID(recv), txbuilder.ArgID(sh), txbuilder.ArgID(w)}))
+
+	u64 := typetag.MustParse("u64")
+	vec := id(b.MakeMoveVec(&u64, []uint64{id(b.PureU64(1)), id(b.PureU64(3))}))
+	coins := id(b.MakeMoveVec(nil, []uint64{id(b.NestedResult(split, 0)), id(b.NestedResult(split, 1))}))
+	id(b.MoveCall(pkg, "vec", "take", nil, []txbuilder.MoveCallArg{
+		txbuilder.ArgID(vec), txbuilder.ArgID(coins), txbuilder.ArgID(id(b.NestedResult(pair, 1))),
+	}))
+
+	upgradeCap := id(b.Publish(modules, deps))
+	ticket := id(b.MoveCall(txbuilder.MustParseObjectID("0x2"), "package", "authorize_upgrade", nil,
+		[]txbuilder.MoveCallArg{txbuilder.ArgID(upgradeCap), txbuilder.ArgID(id(b.PureU8(0)))}))
+	receipt := id(b.Upgrade(modules, deps, pkg, ticket))
+	id(b.MoveCall(txbuilder.MustParseObjectID("0x2"), "package", "commit_upgrade", nil,
+		[]txbuilder.MoveCallArg{txbuilder.ArgID(upgradeCap), txbuilder.ArgID(receipt)}))
+
+	do(b.TransferObjects([]uint64{a, upgradeCap, id(b.GasArgument())}, id(b.PureAddress(sender))))
+	return err
+}
+
+// A plan recorded over gobuilder, formatted, parsed and replayed into a
+// fresh gobuilder builds the same transaction.
+func TestPlanRoundTrip(t *testing.T) {
+	rec := txbuilder.NewRecorder(gobuilder.NewBuilder())
+	if err := build(rec); err != nil {
+		t.Fatal(err)
+	}
+	plan := rec.Plan()
+	want, err := rec.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := plan.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := txbuilder.ParsePlan(text)
+	if err != nil {
+		t.Fatalf("ParsePlan: %v\n%s", err, text)
+	}
+	b := gobuilder.NewBuilder()
+	if err := parsed.Replay(b); err != nil {
+		b.Free()
+		t.Fatal(err)
+	}
+	got, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("replayed transaction differs\n got %x\nwant %x\nplan:\n%s", got, want, text)
+	}
+}
+
+func TestPlanValidate(t *testing.T) {
+	var (
+		object = txbuilder.Input{Object: &txbuilder.ObjectInput{ID: coinA, Kind: txbuilder.ObjectKindOwned, Version: 3, Digest: digest}}
+		pure   = txbuilder.Input{Pure: &txbuilder.PureInput{BCS: txbuilder.Bytes{1}}}
+		merge  = txbuilder.Command{MergeCoins: &txbuilder.MergeCoinsCommand{
+			Coin: txbuilder.GasRef, Sources: []txbuilder.Ref{txbuilder.InputRef(0)},
+		}}
+		transfer = txbuilder.Command{TransferObjects: &txbuilder.TransferObjectsCommand{
+			Objects: []txbuilder.Ref{txbuilder.InputRef(0)}, Recipient: txbuilder.InputRef(1),
+		}}
+		split = func(coin txbuilder.Ref) txbuilder.Command {
+			return txbuilder.Command{SplitCoins: &txbuilder.SplitCoinsCommand{Coin: coin, Amounts: []txbuilder.Ref{txbuilder.InputRef(1)}}}
+		}
+	)
+	tests := []struct {
+		name     string
+		inputs   []txbuilder.Input
+		commands []txbuilder.Command
+		kind     txbuilder.ErrorKind // empty when the plan is valid
+		field    string
+		index    int
+	}{
+		{"valid", []txbuilder.Input{object, pure}, []txbuilder.Command{split(txbuilder.InputRef(0)), split(txbuilder.ResultRef(0))}, "", "", 0},
+		{"forward result", []txbuilder.Input{object, pure}, []txbuilder.Command{split(txbuilder.ResultRef(1)), split(txbuilder.InputRef(0))},
+			txbuilder.KindUnknownArgument, "commands", 0},
+		{"own result", []txbuilder.Input{object, pure}, []txbuilder.Command{split(txbuilder.ResultRef(0))},
+			txbuilder.KindUnknownArgument, "commands", 0},
+		{"forward nested result", []txbuilder.Input{object, pure}, []txbuilder.Command{split(txbuilder.InputRef(0)), split(txbuilder.NestedResultRef(2, 0)), split(txbuilder.InputRef(0))},
+			txbuilder.KindUnknownArgument, "commands", 1},
+		{"merge_coins result", []txbuilder.Input{object, pure}, []txbuilder.Command{merge, split(txbuilder.ResultRef(0))},
+			txbuilder.KindUnknownArgument, "commands", 1},
+		{"transfer_objects nested result", []txbuilder.Input{object, pure}, []txbuilder.Command{transfer, split(txbuilder.NestedResultRef(0, 0))},
+			txbuilder.KindUnknownArgument, "commands", 1},
+		{"input out of range", []txbuilder.Input{object}, []txbuilder.Command{split(txbuilder.InputRef(0))},
+			txbuilder.KindUnknownArgument, "commands", 0},
+		{"object and pure input", []txbuilder.Input{object, {Object: object.Object, Pure: pure.Pure}}, nil,
+			txbuilder.KindJSON, "inputs", 1},
+		{"pure and funds withdrawal input", []txbuilder.Input{{Pure: pure.Pure, FundsWithdrawal: &txbuilder.FundsWithdrawal{Amount: 1, CoinType: typetag.SUI}}}, nil,
+			txbuilder.KindJSON, "inputs", 0},
+		{"empty input", []txbuilder.Input{object, {}}, nil, txbuilder.KindJSON, "inputs", 1},
+		{"command with two kinds", []txbuilder.Input{object, pure}, []txbuilder.Command{{MergeCoins: merge.MergeCoins, SplitCoins: split(txbuilder.InputRef(0)).SplitCoins}},
+			txbuilder.KindJSON, "commands", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &txbuilder.Plan{Version: txbuilder.PlanVersion, Inputs: tt.inputs, Commands: tt.commands}
+			err := p.Validate()
+			if tt.kind == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var e *txbuilder.Error
+			if !errors.As(err, &e) || e.Kind != tt.kind || e.Field != tt.field || e.Index == nil || *e.Index != tt.index {
+				t.Fatalf("Validate() = %v, want %s at %s[%d]", err, tt.kind, tt.field, tt.index)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// pure.go
+//
+// Pure argument encodings.  Every backend implements the Pure* helpers of
+// Builder by pushing these bytes through PureRawBCS; the BCS itself comes
+// from the bcs package.
+
+package txbuilder
+
+import (
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+
+	"github.com/pictorx/go-sui-sdk/bcs"
+)
+
+// Primitive is a Move primitive with a fixed-size BCS encoding.
+type Primitive interface {
+	bool | uint8 | uint16 | uint32 | uint64
+}
+
+// ── Encoders ──────────────────────────────────────────────────────────────────
+
+// EncodePrimitive encodes v.
+func EncodePrimitive[T Primitive](v T) []byte {
+	buf, _ := bcs.Marshal(v) // primitives always encode
+	return buf
+}
+
+// EncodeU128 encodes the u128 hi<<64 | lo.
+func EncodeU128(hi, lo uint64) []byte {
+	e := bcs.NewEncoder()
+	e.WriteU64(lo)
+	e.WriteU64(hi)
+	return e.Bytes()
+}
+
+// EncodeVector encodes vs as a Move vector<T> of primitives.
+func EncodeVector[T Primitive](vs []T) []byte {
+	buf, _ := bcs.Marshal(vs)
+	return buf
+}
+
+// EncodeVectorOf encodes a Move vector whose elements are already encoded,
+// e.g. vector<vector<u8>> or vector<Option<u64>>.
+func EncodeVectorOf(elems [][]byte) []byte {
+	e := bcs.NewEncoder()
+	e.WriteULEB128(uint64(len(elems)))
+	for _, elem := range elems {
+		e.WriteFixed(elem)
+	}
+	return e.Bytes()
+}
+
+// EncodeBytes encodes b as a Move vector<u8>.
+func EncodeBytes(b []byte) []byte {
+	e := bcs.NewEncoder()
+	e.WriteBytes(b)
+	return e.Bytes()
+}
+
+// EncodeOption encodes an Option<T> from T's encoding; nil means None.
+// No BCS value encodes to zero bytes, so nil is unambiguous.
+func EncodeOption(value []byte) []byte {
+	if value == nil {
+		return []byte{0}
+	}
+	return append([]byte{1}, value...)
+}
+
+// EncodeU256 encodes v as a little-endian u256.
+func EncodeU256(v *big.Int) ([]byte, error) {
+	if v == nil {
+		return nil, fmt.Errorf("value %v out of range for u256", v)
+	}
+	return bcs.Marshal(bcs.U256{Int: v})
+}
+
+// ── Builder helpers ───────────────────────────────────────────────────────────
+
+// PureString pushes a Move String (0x1::string::String).  s must be valid
+// UTF-8.
+func PureString(b Builder, s string) (uint64, error) {
+	if !utf8.ValidString(s) {
+		return 0, NewError("pure_string", -1, KindUTF8, "string is not valid UTF-8")
+	}
+	return b.PureRawBCS(EncodeBytes([]byte(s)))
+}
+
+// PureASCIIString pushes a Move ascii::String.  Every byte of s must be
+// ASCII (at most 0x7f), as 0x1::ascii::is_valid_char requires; control
+// characters such as '\n' are allowed.
+func PureASCIIString(b Builder, s string) (uint64, error) {
+	if err := checkASCII(s); err != nil {
+		return 0, NewError("pure_ascii_string", -1, KindInvalidValue, err.Error())
+	}
+	return b.PureRawBCS(EncodeBytes([]byte(s)))
+}
+
+// checkASCII reports the first byte of s above 0x7f.
+func checkASCII(s string) error {
+	for i := 0; i < len(s); i++ {
+		if s[i] > 0x7f {
+			return fmt.Errorf("byte 0x%02x at offset %d is not ASCII", s[i], i)
+		}
+	}
+	return nil
+}
+
+// PureU256 pushes a u256.  v must be in [0, 2^256).
+func PureU256(b Builder, v *big.Int) (uint64, error) {
+	buf, err := EncodeU256(v)
+	if err != nil {
+		return 0, NewError("pure_u256", -1, KindInvalidValue, err.Error())
+	}
+	return b.PureRawBCS(buf)
+}
+
+// PureID pushes an object ID (0x2::object::ID).
+func PureID(b Builder, id ObjectID) (uint64, error) {
+	return b.PureRawBCS(id[:])
+}
+
+// PureVec pushes a vector<T> of primitives, e.g. PureVec(b, []uint64{1, 2}).
+func PureVec[T Primitive](b Builder, vs []T) (uint64, error) {
+	return b.PureRawBCS(EncodeVector(vs))
+}
@@ -0,0 +1,432 @@
+// recorder.go
+//
+// Recorder exports the calls made on a Builder as a Plan.
+
+package txbuilder
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+// Recorder is a Builder that records every call as a Plan and forwards it
+// to an inner Builder.  With a nil inner Builder it only records, handing
+// out its own Argument IDs, and Build fails with ErrRecordOnly.
+//
+//	rec := txbuilder.NewRecorder(nil)
+//	bindings.Deposit(rec, pool, coin) // any code written against Builder
+//	data, _ := json.MarshalIndent(rec.Plan(), "", "  ")
+type Recorder struct {
+	inner    Builder
+	plan     Plan
+	refs     map[uint64]Ref
+	next     uint64
+	consumed bool
+}
+
+var (
+	_ Builder        = (*Recorder)(nil)
+	_ ObjectResolver = (*Recorder)(nil)
+)
+
+// ErrRecordOnly is returned by Build on a Recorder without an inner
+// Builder.
+var ErrRecordOnly = errors.New("txbuilder: recorder has no inner builder to build with")
+
+// NewRecorder returns a Recorder forwarding to inner, which may be nil.
+func NewRecorder(inner Builder) *Recorder {
+	return &Recorder{inner: inner, plan: Plan{Version: PlanVersion}, refs: map[uint64]Ref{}}
+}
+
+// Plan returns a copy of the plan recorded so far.
+func (r *Recorder) Plan() *Plan {
+	p := r.plan
+	p.Gas = slices.Clone(p.Gas)
+	p.Inputs = slices.Clone(p.Inputs)
+	p.Commands = slices.Clone(p.Commands)
+	return &p
+}
+
+// forward runs call on the inner builder, or allocates an ID when there is
+// none, and binds the ID to ref.
+func (r *Recorder) forward(ref Ref, call func(Builder) (uint64, error)) (uint64, error) {
+	if r.consumed {
+		return 0, ErrConsumed
+	}
+	var id uint64
+	if r.inner != nil {
+		var err error
+		if id, err = call(r.inner); err != nil {
+			return 0, err
+		}
+	} else {
+		id = r.next
+		r.next++
+	}
+	r.refs[id] = ref
+	return id, nil
+}
+
+// ref looks up the Ref bound to an Argument ID.
+func (r *Recorder) ref(fn, field string, id uint64) (Ref, error) {
+	ref, ok := r.refs[id]
+	if !ok {
+		return Ref{}, NewError(fn, -4, KindUnknownArgument, fmt.Sprintf("unknown argument ID %d", id)).WithField(field)
+	}
+	return ref, nil
+}
+
+func (r *Recorder) refList(fn, field string, ids []uint64) ([]Ref, error) {
+	refs := make([]Ref, len(ids))
+	for i, id := range ids {
+		ref, ok := r.refs[id]
+		if !ok {
+			return nil, NewError(fn, -4, KindUnknownArgument, fmt.Sprintf("unknown argument ID %d", id)).
+				WithField(field).WithIndex(i)
+		}
+		refs[i] = ref
+	}
+	return refs, nil
+}
+
+// pushInput appends an input once the inner builder accepted it.
+func (r *Recorder) pushInput(in Input, call func(Builder) (uint64, error)) (uint64, error) {
+	id, err := r.forward(InputRef(len(r.plan.Inputs)), call)
+	if err != nil {
+		return 0, err
+	}
+	r.plan.Inputs = append(r.plan.Inputs, in)
+	return id, nil
+}
+
+// pushCommand appends a command once the inner builder accepted it.  Only
+// commands with a result bind an Argument ID.
+func (r *Recorder) pushCommand(c Command, call func(Builder) (uint64, error)) (uint64, error) {
+	if !c.hasResult() {
+		if r.consumed {
+			return 0, ErrConsumed
+		}
+		if r.inner != nil {
+			if _, err := call(r.inner); err != nil {
+				return 0, err
+			}
+		}
+		r.plan.Commands = append(r.plan.Commands, c)
+		return 0, nil
+	}
+	id, err := r.forward(ResultRef(len(r.plan.Commands)), call)
+	if err != nil {
+		return 0, err
+	}
+	r.plan.Commands = append(r.plan.Commands, c)
+	return id, nil
+}
+
+// ── Builder ───────────────────────────────────────────────────────────────────
+
+// SetConfig sets the sender address, gas budget, and gas price.
+func (r *Recorder) SetConfig(sender Address, gasBudget, gasPrice uint64) error {
+	if r.consumed {
+		return ErrConsumed
+	}
+	if r.inner != nil {
+		if err := r.inner.SetConfig(sender, gasBudget, gasPrice); err != nil {
+			return err
+		}
+	}
+	r.plan.Sender, r.plan.GasBudget, r.plan.GasPrice = sender, gasBudget, gasPrice
+	return nil
+}
+
+// AddGasObject adds an owned gas coin.
+func (r *Recorder) AddGasObject(id ObjectID, version uint64, digest Digest) error {
+	if r.consumed {
+		return ErrConsumed
+	}
+	if r.inner != nil {
+		if err := r.inner.AddGasObject(id, version, digest); err != nil {
+			return err
+		}
+	}
+	r.plan.Gas = append(r.plan.Gas, GasObject{ID: id, Version: version, Digest: digest})
+	return nil
+}
+
+// GasArgument returns the Argument ID for the transaction's gas coin.
+func (r *Recorder) GasArgument() (uint64, error) {
+	return r.forward(GasRef, func(b Builder) (uint64, error) { return b.GasArgument() })
+}
+
+// InputObject pushes an object input and returns its Argument ID.
+func (r *Recorder) InputObject(id ObjectID, version uint64, digest Digest, kind ObjectKind, mutable bool) (uint64, error) {
+	in := Input{Object: &ObjectInput{ID: id, Kind: kind, Version: version, Digest: digest, Mutable: mutable}}
+	return r.pushInput(in, func(b Builder) (uint64, error) {
+		return b.InputObject(id, version, digest, kind, mutable)
+	})
+}
+
+// InputFundsWithdrawal pushes a withdrawal from an address balance and
+// returns its Argument ID.
+func (r *Recorder) InputFundsWithdrawal(w FundsWithdrawal) (uint64, error) {
+	return r.pushInput(Input{FundsWithdrawal: &w}, func(b Builder) (uint64, error) {
+		return b.InputFundsWithdrawal(w)
+	})
+}
+
+// InputObjectByID records an object input to be resolved when the plan is
+// replayed.  The inner builder, if any, must implement ObjectResolver.
+func (r *Recorder) InputObjectByID(id ObjectID) (uint64, error) {
+	if r.inner != nil {
+		if _, ok := r.inner.(ObjectResolver); !ok {
+			return 0, NewError("input_object", -1, KindIncompleteObject,
+				fmt.Sprintf("%T cannot resolve objects by ID", r.inner))
+		}
+	}
+	return r.pushInput(Input{Object: &ObjectInput{ID: id}}, func(b Builder) (uint64, error) {
+		return b.(ObjectResolver).InputObjectByID(id)
+	})
+}
+
+func (r *Recorder) pure(p *PureInput, call func(Builder) (uint64, error)) (uint64, error) {
+	return r.pushInput(Input{Pure: p}, call)
+}
+
+func (r *Recorder) PureBool(v bool) (uint64, error) {
+	return r.pure(typedPure("bool", v), func(b Builder) (uint64, error) { return b.PureBool(v) })
+}
+
+func (r *Recorder) PureU8(v uint8) (uint64, error) {
+	return r.pure(typedPure("u8", v), func(b Builder) (uint64, error) { return b.PureU8(v) })
+}
+
+func (r *Recorder) PureU16(v uint16) (uint64, error) {
+	return r.pure(typedPure("u16", v), func(b Builder) (uint64, error) { return b.PureU16(v) })
+}
+
+func (r *Recorder) PureU32(v uint32) (uint64, error) {
+	return r.pure(typedPure("u32", v), func(b Builder) (uint64, error) { return b.PureU32(v) })
+}
+
+// PureU64 records the value as a decimal string, which JSON readers that
+// parse numbers as doubles cannot round.
+func (r *Recorder) PureU64(v uint64) (uint64, error) {
+	return r.pure(typedPure("u64", fmt.Sprint(v)), func(b Builder) (uint64, error) { return b.PureU64(v) })
+}
+
+func (r *Recorder) PureU128(hi, lo uint64) (uint64, error) {
+	v := new(big.Int).Lsh(new(big.Int).SetUint64(hi), 64)
+	v.Or(v, new(big.Int).SetUint64(lo))
+	return r.pure(typedPure("u128", v.String()), func(b Builder) (uint64, error) { return b.PureU128(hi, lo) })
+}
+
+func (r *Recorder) PureAddress(addr Address) (uint64, error) {
+	return r.pure(typedPure("address", addr), func(b Builder) (uint64, error) { return b.PureAddress(addr) })
+}
+
+// PureRawBCS pushes already-BCS-encoded bytes as a pure argument.
+func (r *Recorder) PureRawBCS(bcsBytes []byte) (uint64, error) {
+	return r.pure(&PureInput{BCS: Bytes(bcsBytes)}, func(b Builder) (uint64, error) { return b.PureRawBCS(bcsBytes) })
+}
+
+func (r *Recorder) PureString(s string) (uint64, error) {
+	return r.pure(typedPure("string", s), func(b Builder) (uint64, error) { return b.PureString(s) })
+}
+
+func (r *Recorder) PureASCIIString(s string) (uint64, error) {
+	return r.pure(typedPure("ascii_string", s), func(b Builder) (uint64, error) { return b.PureASCIIString(s) })
+}
+
+func (r *Recorder) PureBytes(v []byte) (uint64, error) {
+	return r.pure(typedPure("vector<u8>", "0x"+hex.EncodeToString(v)), func(b Builder) (uint64, error) { return b.PureBytes(v) })
+}
+
+func (r *Recorder) PureU256(v *big.Int) (uint64, error) {
+	if _, err := EncodeU256(v); err != nil {
+		return 0, NewError("pure_u256", -1, KindInvalidValue, err.Error())
+	}
+	return r.pure(typedPure("u256", v.String()), func(b Builder) (uint64, error) { return b.PureU256(v) })
+}
+
+func (r *Recorder) PureID(id ObjectID) (uint64, error) {
+	return r.pure(typedPure("id", id), func(b Builder) (uint64, error) { return b.PureID(id) })
+}
+
+// PureOption pushes an Option<T> from T's BCS encoding; nil is None.
+func (r *Recorder) PureOption(value []byte) (uint64, error) {
+	return r.pure(&PureInput{BCS: EncodeOption(value)}, func(b Builder) (uint64, error) { return b.PureOption(value) })
+}
+
+// PureVector pushes a vector<T> from its elements' BCS encodings.
+func (r *Recorder) PureVector(elems [][]byte) (uint64, error) {
+	return r.pure(&PureInput{BCS: EncodeVectorOf(elems)}, func(b Builder) (uint64, error) { return b.PureVector(elems) })
+}
+
+// NestedResult returns the Argument ID for the Nth sub-result of a
+// multi-output command.
+func (r *Recorder) NestedResult(baseID, subIndex uint64) (uint64, error) {
+	if r.consumed {
+		return 0, ErrConsumed
+	}
+	base, err := r.ref("nested_result", "base_id", baseID)
+	if err != nil {
+		return 0, err
+	}
+	if base.Kind != RefResult {
+		return 0, NewError("nested_result", -4, KindUnknownArgument,
+			fmt.Sprintf("argument %d is %s, not a command result", baseID, base)).WithField("base_id")
+	}
+	return r.forward(NestedResultRef(base.Index, int(subIndex)), func(b Builder) (uint64, error) {
+		return b.NestedResult(baseID, subIndex)
+	})
+}
+
+// MoveCall records pure BCS arguments as pure inputs.
+func (r *Recorder) MoveCall(pkg ObjectID, module, function string, typeArgs []typetag.TypeTag, args []MoveCallArg) (uint64, error) {
+	if r.consumed {
+		return 0, ErrConsumed
+	}
+	refs := make([]Ref, len(args))
+	var pures []Input
+	for i, a := range args {
+		if a.ArgID == nil {
+			refs[i] = InputRef(len(r.plan.Inputs) + len(pures))
+			pures = append(pures, Input{Pure: &PureInput{BCS: Bytes(a.PureBCS)}})
+			continue
+		}
+		ref, ok := r.refs[*a.ArgID]
+		if !ok {
+			return 0, NewError("move_call", -4, KindUnknownArgument, fmt.Sprintf("unknown argument ID %d", *a.ArgID)).
+				WithField("arguments").WithIndex(i)
+		}
+		refs[i] = ref
+	}
+
+	c := Command{MoveCall: &MoveCallCommand{
+		Package: pkg, Module: module, Function: function,
+		TypeArgs: slices.Clone(typeArgs), Arguments: refs,
+	}}
+	// The inner builder pushes pure arguments as inputs too, so the
+	// recorded inputs keep its order.
+	id, err := r.forward(ResultRef(len(r.plan.Commands)), func(b Builder) (uint64, error) {
+		return b.MoveCall(pkg, module, function, typeArgs, args)
+	})
+	if err != nil {
+		return 0, err
+	}
+	r.plan.Inputs = append(r.plan.Inputs, pures...)
+	r.plan.Commands = append(r.plan.Commands, c)
+	return id, nil
+}
+
+func (r *Recorder) SplitCoins(coinArgID uint64, amountArgIDs []uint64) (uint64, error) {
+	if r.consumed {
+		return 0, ErrConsumed
+	}
+	coin, err := r.ref("split_coins", "coin", coinArgID)
+	if err != nil {
+		return 0, err
+	}
+	amounts, err := r.refList("split_coins", "amounts", amountArgIDs)
+	if err != nil {
+		return 0, err
+	}
+	return r.pushCommand(Command{SplitCoins: &SplitCoinsCommand{Coin: coin, Amounts: amounts}},
+		func(b Builder) (uint64, error) { return b.SplitCoins(coinArgID, amountArgIDs) })
+}
+
+func (r *Recorder) MergeCoins(targetCoinArgID uint64, sourceArgIDs []uint64) error {
+	if r.consumed {
+		return ErrConsumed
+	}
+	coin, err := r.ref("merge_coins", "coin", targetCoinArgID)
+	if err != nil {
+		return err
+	}
+	sources, err := r.refList("merge_coins", "sources", sourceArgIDs)
+	if err != nil {
+		return err
+	}
+	_, err = r.pushCommand(Command{MergeCoins: &MergeCoinsCommand{Coin: coin, Sources: sources}},
+		func(b Builder) (uint64, error) { return 0, b.MergeCoins(targetCoinArgID, sourceArgIDs) })
+	return err
+}
+
+func (r *Recorder) TransferObjects(objectArgIDs []uint64, recipientArgID uint64) error {
+	if r.consumed {
+		return ErrConsumed
+	}
+	objects, err := r.refList("transfer_objects", "objects", objectArgIDs)
+	if err != nil {
+		return err
+	}
+	recipient, err := r.ref("transfer_objects", "recipient", recipientArgID)
+	if err != nil {
+		return err
+	}
+	_, err = r.pushCommand(Command{TransferObjects: &TransferObjectsCommand{Objects: objects, Recipient: recipient}},
+		func(b Builder) (uint64, error) { return 0, b.TransferObjects(objectArgIDs, recipientArgID) })
+	return err
+}
+
+func (r *Recorder) MakeMoveVec(elemType *typetag.TypeTag, elemArgIDs []uint64) (uint64, error) {
+	if r.consumed {
+		return 0, ErrConsumed
+	}
+	elems, err := r.refList("make_move_vec", "elements", elemArgIDs)
+	if err != nil {
+		return 0, err
+	}
+	return r.pushCommand(Command{MakeMoveVec: &MakeMoveVecCommand{Type: elemType, Elements: elems}},
+		func(b Builder) (uint64, error) { return b.MakeMoveVec(elemType, elemArgIDs) })
+}
+
+func (r *Recorder) Publish(modules [][]byte, dependencies []ObjectID) (uint64, error) {
+	c := Command{Publish: &PublishCommand{Modules: ModuleBytes(modules), Dependencies: slices.Clone(dependencies)}}
+	return r.pushCommand(c, func(b Builder) (uint64, error) { return b.Publish(modules, dependencies) })
+}
+
+func (r *Recorder) Upgrade(modules [][]byte, dependencies []ObjectID, packageID ObjectID, ticketArgID uint64) (uint64, error) {
+	if r.consumed {
+		return 0, ErrConsumed
+	}
+	ticket, err := r.ref("upgrade", "ticket_arg_id", ticketArgID)
+	if err != nil {
+		return 0, err
+	}
+	c := Command{Upgrade: &UpgradeCommand{
+		Modules: ModuleBytes(modules), Dependencies: slices.Clone(dependencies),
+		Package: packageID, Ticket: ticket,
+	}}
+	return r.pushCommand(c, func(b Builder) (uint64, error) {
+		return b.Upgrade(modules, dependencies, packageID, ticketArgID)
+	})
+}
+
+// Build builds with the inner builder and consumes the Recorder.  The plan
+// stays readable.
+func (r *Recorder) Build() ([]byte, error) {
+	if r.consumed {
+		return nil, ErrConsumed
+	}
+	r.consumed = true
+	if r.inner == nil {
+		return nil, ErrRecordOnly
+	}
+	return r.inner.Build()
+}
+
+// Free releases the inner builder.
+func (r *Recorder) Free() {
+	if r.consumed {
+		return
+	}
+	r.consumed = true
+	if r.inner != nil {
+		r.inner.Free()
+	}
+}
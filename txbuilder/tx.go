@@ -0,0 +1,414 @@
+// tx.go
+//
+// Typed, fluent layer over Builder.
+//
+//	tx := txbuilder.NewTx(b)
+//	tx.SetConfig(sender, 10_000_000, 1_000)
+//	tx.AddGasObject(id, version, digest)
+//	tx.SplitCoins(tx.Gas(), tx.U64(100)).Nested(0).TransferTo(recipient)
+//	bcsBytes, err := tx.Build()
+//
+// Arguments are values bound to the Tx that made them instead of bare IDs.
+// Where a command needs a coin it takes a CoinArg, and only command results
+// have Nested, so those mistakes do not compile.  Misuse the types cannot
+// catch (an argument from another Tx, the zero Arg) and backend failures
+// are recorded instead of returned: the first one is sticky, later calls
+// are no-ops, and Build reports it and frees the builder.
+
+package txbuilder
+
+import (
+	"math/big"
+
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+// Tx wraps a Builder with typed arguments.  Like the Builder it is NOT safe
+// for concurrent use.
+type Tx struct {
+	b   Builder
+	err error
+	gas *CoinArg
+}
+
+// NewTx wraps b.  The Tx takes ownership: Build consumes b, and Free
+// releases it.
+func NewTx(b Builder) *Tx {
+	return &Tx{b: b}
+}
+
+// Argument is implemented by Arg, CoinArg, ResultArg and CoinsResult.
+type Argument interface {
+	arg() Arg
+}
+
+// Arg is a command argument bound to the Tx that created it.  The zero Arg
+// is invalid; passing it to a Tx fails Build.
+type Arg struct {
+	tx *Tx
+	id uint64
+	ok bool
+}
+
+func (a Arg) arg() Arg { return a }
+
+// CoinArg is an argument holding a Coin<T>: the gas coin, a coin input, or
+// a coin produced by SplitCoins.
+type CoinArg struct{ Arg }
+
+// ResultArg is the result of a command that may return several values.
+// Used directly it is the whole result; Nested selects one value.
+type ResultArg struct{ Arg }
+
+// CoinsResult is the result of SplitCoins; every value is a coin.
+type CoinsResult struct{ ResultArg }
+
+// Err returns the first recorded error, if any.
+func (t *Tx) Err() error { return t.err }
+
+// fail records err unless an earlier error is already recorded.
+func (t *Tx) fail(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+// ok reports whether the Tx can still accept calls.
+func (t *Tx) ok() bool { return t.err == nil }
+
+// id returns the Argument ID of a, recording an error when a belongs to
+// another Tx or is invalid.
+func (t *Tx) id(fn string, a Argument) (uint64, bool) {
+	if a == nil {
+		t.fail(NewError(fn, -1, KindUnknownArgument, "nil argument"))
+		return 0, false
+	}
+	arg := a.arg()
+	switch {
+	case arg.tx == nil:
+		t.fail(NewError(fn, -1, KindUnknownArgument, "zero Arg"))
+		return 0, false
+	case arg.tx != t:
+		t.fail(NewError(fn, -1, KindUnknownArgument, "argument belongs to another transaction"))
+		return 0, false
+	case !arg.ok:
+		// Made after the sticky error; it is already recorded.
+		return 0, false
+	}
+	return arg.id, true
+}
+
+func (t *Tx) ids(fn string, args []Argument) ([]uint64, bool) {
+	out := make([]uint64, len(args))
+	for i, a := range args {
+		id, ok := t.id(fn, a)
+		if !ok {
+			return nil, false
+		}
+		out[i] = id
+	}
+	return out, true
+}
+
+// bind wraps the result of a Builder call.
+func (t *Tx) bind(id uint64, err error) Arg {
+	if err != nil {
+		t.fail(err)
+		return Arg{tx: t}
+	}
+	return Arg{tx: t, id: id, ok: true}
+}
+
+// ── Configuration ─────────────────────────────────────────────────────────────
+
+// SetConfig sets the sender address, gas budget, and gas price.
+func (t *Tx) SetConfig(sender Address, gasBudget, gasPrice uint64) {
+	if t.ok() {
+		t.fail(t.b.SetConfig(sender, gasBudget, gasPrice))
+	}
+}
+
+// AddGasObject adds an owned gas coin.
+func (t *Tx) AddGasObject(id ObjectID, version uint64, digest Digest) {
+	if t.ok() {
+		t.fail(t.b.AddGasObject(id, version, digest))
+	}
+}
+
+// Gas returns the transaction's gas coin.
+func (t *Tx) Gas() CoinArg {
+	if t.gas == nil {
+		if !t.ok() {
+			return CoinArg{Arg{tx: t}}
+		}
+		gas := CoinArg{t.bind(t.b.GasArgument())}
+		t.gas = &gas
+	}
+	return *t.gas
+}
+
+// ── Inputs ────────────────────────────────────────────────────────────────────
+
+// Object pushes an object input.
+func (t *Tx) Object(id ObjectID, version uint64, digest Digest, kind ObjectKind, mutable bool) Arg {
+	if !t.ok() {
+		return Arg{tx: t}
+	}
+	return t.bind(t.b.InputObject(id, version, digest, kind, mutable))
+}
+
+// OwnedObject pushes an owned object input.
+func (t *Tx) OwnedObject(id ObjectID, version uint64, digest Digest) Arg {
+	return t.Object(id, version, digest, ObjectKindOwned, true)
+}
+
+// SharedObject pushes a shared object input at its initial shared version.
+func (t *Tx) SharedObject(id ObjectID, initialVersion uint64, mutable bool) Arg {
+	return t.Object(id, initialVersion, Digest{}, ObjectKindShared, mutable)
+}
+
+// ReceivingObject pushes an object sent to another object, to be passed
+// as a Receiving<T> argument to the parent's module.
+func (t *Tx) ReceivingObject(id ObjectID, version uint64, digest Digest) Arg {
+	return t.Object(id, version, digest, ObjectKindReceiving, false)
+}
+
+// FundsWithdrawal pushes a withdrawal of up to amount of Balance<coinType>
+// from the sender's address balance.
+func (t *Tx) FundsWithdrawal(amount uint64, coinType typetag.TypeTag) Arg {
+	if !t.ok() {
+		return Arg{tx: t}
+	}
+	return t.bind(t.b.InputFundsWithdrawal(FundsWithdrawal{Amount: amount, CoinType: coinType}))
+}
+
+// CoinObject pushes an owned Coin<T> input.
+func (t *Tx) CoinObject(id ObjectID, version uint64, digest Digest) CoinArg {
+	return CoinArg{t.OwnedObject(id, version, digest)}
+}
+
+// ── Pure values ───────────────────────────────────────────────────────────────
+
+func (t *Tx) pure(push func() (uint64, error)) Arg {
+	if !t.ok() {
+		return Arg{tx: t}
+	}
+	return t.bind(push())
+}
+
+// Bool pushes a bool.
+func (t *Tx) Bool(v bool) Arg { return t.pure(func() (uint64, error) { return t.b.PureBool(v) }) }
+
+// U8 pushes a u8.
+func (t *Tx) U8(v uint8) Arg { return t.pure(func() (uint64, error) { return t.b.PureU8(v) }) }
+
+// U16 pushes a u16.
+func (t *Tx) U16(v uint16) Arg { return t.pure(func() (uint64, error) { return t.b.PureU16(v) }) }
+
+// U32 pushes a u32.
+func (t *Tx) U32(v uint32) Arg { return t.pure(func() (uint64, error) { return t.b.PureU32(v) }) }
+
+// U64 pushes a u64.
+func (t *Tx) U64(v uint64) Arg { return t.pure(func() (uint64, error) { return t.b.PureU64(v) }) }
+
+// U128 pushes a u128 from its high and low halves.
+func (t *Tx) U128(hi, lo uint64) Arg {
+	return t.pure(func() (uint64, error) { return t.b.PureU128(hi, lo) })
+}
+
+// U256 pushes a u256.
+func (t *Tx) U256(v *big.Int) Arg { return t.pure(func() (uint64, error) { return t.b.PureU256(v) }) }
+
+// Address pushes an address.
+func (t *Tx) Address(a Address) Arg {
+	return t.pure(func() (uint64, error) { return t.b.PureAddress(a) })
+}
+
+// ID pushes an object ID (0x2::object::ID).
+func (t *Tx) ID(id ObjectID) Arg { return t.pure(func() (uint64, error) { return t.b.PureID(id) }) }
+
+// MoveString pushes a Move String (0x1::string::String).
+func (t *Tx) MoveString(s string) Arg {
+	return t.pure(func() (uint64, error) { return t.b.PureString(s) })
+}
+
+// ASCIIString pushes a Move ascii::String.
+func (t *Tx) ASCIIString(s string) Arg {
+	return t.pure(func() (uint64, error) { return t.b.PureASCIIString(s) })
+}
+
+// Bytes pushes a vector<u8>.
+func (t *Tx) Bytes(v []byte) Arg { return t.pure(func() (uint64, error) { return t.b.PureBytes(v) }) }
+
+// Pure pushes pre-encoded BCS bytes.
+func (t *Tx) Pure(bcsBytes []byte) Arg {
+	return t.pure(func() (uint64, error) { return t.b.PureRawBCS(bcsBytes) })
+}
+
+// ── Commands ──────────────────────────────────────────────────────────────────
+
+// MoveCall calls pkg::module::function.
+func (t *Tx) MoveCall(pkg ObjectID, module, function string, typeArgs []typetag.TypeTag, args ...Argument) ResultArg {
+	if !t.ok() {
+		return ResultArg{Arg{tx: t}}
+	}
+	ids, ok := t.ids("move_call", args)
+	if !ok {
+		return ResultArg{Arg{tx: t}}
+	}
+	callArgs := make([]MoveCallArg, len(ids))
+	for i, id := range ids {
+		callArgs[i] = ArgID(id)
+	}
+	return ResultArg{t.bind(t.b.MoveCall(pkg, module, function, typeArgs, callArgs))}
+}
+
+// SplitCoins splits one coin per amount off coin.
+func (t *Tx) SplitCoins(coin CoinArg, amounts ...Argument) CoinsResult {
+	if !t.ok() {
+		return CoinsResult{ResultArg{Arg{tx: t}}}
+	}
+	c, ok := t.id("split_coins", coin)
+	if !ok {
+		return CoinsResult{ResultArg{Arg{tx: t}}}
+	}
+	ids, ok := t.ids("split_coins", amounts)
+	if !ok {
+		return CoinsResult{ResultArg{Arg{tx: t}}}
+	}
+	return CoinsResult{ResultArg{t.bind(t.b.SplitCoins(c, ids))}}
+}
+
+// MergeCoins merges sources into target.
+func (t *Tx) MergeCoins(target CoinArg, sources ...CoinArg) {
+	if !t.ok() {
+		return
+	}
+	dst, ok := t.id("merge_coins", target)
+	if !ok {
+		return
+	}
+	srcs := make([]uint64, len(sources))
+	for i, s := range sources {
+		if srcs[i], ok = t.id("merge_coins", s); !ok {
+			return
+		}
+	}
+	t.fail(t.b.MergeCoins(dst, srcs))
+}
+
+// TransferObjects sends objects to recipient, an address argument.
+func (t *Tx) TransferObjects(recipient Argument, objects ...Argument) {
+	if !t.ok() {
+		return
+	}
+	rec, ok := t.id("transfer_objects", recipient)
+	if !ok {
+		return
+	}
+	ids, ok := t.ids("transfer_objects", objects)
+	if !ok {
+		return
+	}
+	t.fail(t.b.TransferObjects(ids, rec))
+}
+
+// MakeMoveVec builds a vector<elemType> from elems.  A nil elemType is
+// inferred from the elements.
+func (t *Tx) MakeMoveVec(elemType *typetag.TypeTag, elems ...Argument) Arg {
+	if !t.ok() {
+		return Arg{tx: t}
+	}
+	ids, ok := t.ids("make_move_vec", elems)
+	if !ok {
+		return Arg{tx: t}
+	}
+	return t.bind(t.b.MakeMoveVec(elemType, ids))
+}
+
+// Publish publishes modules and returns the UpgradeCap.
+func (t *Tx) Publish(modules [][]byte, dependencies []ObjectID) Arg {
+	if !t.ok() {
+		return Arg{tx: t}
+	}
+	return t.bind(t.b.Publish(modules, dependencies))
+}
+
+// Upgrade upgrades packageID with ticket and returns the UpgradeReceipt.
+func (t *Tx) Upgrade(modules [][]byte, dependencies []ObjectID, packageID ObjectID, ticket Argument) Arg {
+	if !t.ok() {
+		return Arg{tx: t}
+	}
+	id, ok := t.id("upgrade", ticket)
+	if !ok {
+		return Arg{tx: t}
+	}
+	return t.bind(t.b.Upgrade(modules, dependencies, packageID, id))
+}
+
+// ── Argument methods ──────────────────────────────────────────────────────────
+
+// TransferTo sends a to recipient.
+func (a Arg) TransferTo(recipient Address) {
+	if a.tx == nil {
+		return
+	}
+	a.tx.TransferObjects(a.tx.Address(recipient), a)
+}
+
+// Nested returns the value at index i of a multi-value result.
+func (r ResultArg) Nested(i uint64) Arg {
+	t := r.tx
+	if t == nil {
+		return Arg{}
+	}
+	if !t.ok() || !r.ok {
+		return Arg{tx: t}
+	}
+	return t.bind(t.b.NestedResult(r.id, i))
+}
+
+// Nested returns coin i of a SplitCoins result.
+func (r CoinsResult) Nested(i uint64) CoinArg {
+	return CoinArg{r.ResultArg.Nested(i)}
+}
+
+// Coins returns the first n coins of a SplitCoins result.
+func (r CoinsResult) Coins(n int) []CoinArg {
+	out := make([]CoinArg, n)
+	for i := range out {
+		out[i] = r.Nested(uint64(i))
+	}
+	return out
+}
+
+// AsCoin treats a as a coin, e.g. a Coin<T> returned by a Move call.
+func (a Arg) AsCoin() CoinArg { return CoinArg{a} }
+
+// ── Build ─────────────────────────────────────────────────────────────────────
+
+// Build serialises the transaction and consumes the builder.  If a call
+// failed earlier, the builder is freed and that error returned.
+func (t *Tx) Build() ([]byte, error) {
+	if t.err != nil {
+		t.b.Free()
+		return nil, t.err
+	}
+	out, err := t.b.Build()
+	if err != nil {
+		t.fail(err)
+	}
+	return out, err
+}
+
+// Free releases the builder without building.
+func (t *Tx) Free() {
+	t.b.Free()
+	if t.err == nil {
+		t.err = ErrConsumed
+	}
+}
+
+// Builder returns the wrapped Builder for calls the Tx does not cover.
+// Argument IDs from it are not bound to the Tx.
+func (t *Tx) Builder() Builder { return t.b }
@@ -0,0 +1,130 @@
+package txbuilder_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pictorx/go-sui-sdk/gobuilder"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+)
+
+// spyBuilder counts Build and Free calls on a Recorder over gobuilder.
+type spyBuilder struct {
+	*txbuilder.Recorder
+	built, freed int
+}
+
+func (s *spyBuilder) Build() ([]byte, error) {
+	s.built++
+	return s.Recorder.Build()
+}
+
+func (s *spyBuilder) Free() {
+	s.freed++
+	s.Recorder.Free()
+}
+
+func newSpyTx() (*txbuilder.Tx, *spyBuilder) {
+	spy := &spyBuilder{Recorder: txbuilder.NewRecorder(gobuilder.NewBuilder())}
+	tx := txbuilder.NewTx(spy)
+	tx.SetConfig(sender, 5_000_000, 1_000)
+	tx.AddGasObject(gasID, 7, digest)
+	return tx, spy
+}
+
+// The first error is sticky: later calls reach no builder, and Build
+// returns it and frees the builder without building.
+func TestTxStickyError(t *testing.T) {
+	other, _ := newSpyTx()
+	defer other.Free()
+
+	tests := []struct {
+		name string
+		fail func(tx *txbuilder.Tx)
+		kind txbuilder.ErrorKind
+		msg  string
+	}{
+		{"foreign argument", func(tx *txbuilder.Tx) {
+			tx.SplitCoins(other.Gas(), tx.U64(1))
+		}, txbuilder.KindUnknownArgument, "another transaction"},
+		{"zero Arg", func(tx *txbuilder.Tx) {
+			tx.TransferObjects(tx.Address(sender), txbuilder.Arg{})
+		}, txbuilder.KindUnknownArgument, "zero Arg"},
+		{"zero CoinArg", func(tx *txbuilder.Tx) {
+			tx.MergeCoins(tx.Gas(), txbuilder.CoinArg{})
+		}, txbuilder.KindUnknownArgument, "zero Arg"},
+		{"backend error", func(tx *txbuilder.Tx) {
+			tx.SplitCoins(tx.Gas())
+		}, txbuilder.KindEmptyList, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, spy := newSpyTx()
+			coin := tx.SplitCoins(tx.Gas(), tx.U64(5)).Nested(0)
+			tt.fail(tx)
+			first := tx.Err()
+			var e *txbuilder.Error
+			if !errors.As(first, &e) || e.Kind != tt.kind || !strings.Contains(e.Message, tt.msg) {
+				t.Fatalf("Err() = %v, want %s %q", first, tt.kind, tt.msg)
+			}
+
+			// Later calls, including valid ones and arguments made before
+			// the error, are no-ops.
+			before := spy.Plan()
+			coin.TransferTo(sender)
+			tx.MoveCall(pkg, "m", "f", nil, tx.U64(1), tx.Object(coinA, 3, digest, txbuilder.ObjectKindOwned, true))
+			tx.SplitCoins(other.Gas())
+			if after := spy.Plan(); len(after.Inputs) != len(before.Inputs) || len(after.Commands) != len(before.Commands) {
+				t.Errorf("calls after the error reached the builder: %d inputs, %d commands; had %d, %d",
+					len(after.Inputs), len(after.Commands), len(before.Inputs), len(before.Commands))
+			}
+			if tx.Err() != first {
+				t.Errorf("Err() = %v after later calls, want %v", tx.Err(), first)
+			}
+
+			if _, err := tx.Build(); err != first {
+				t.Errorf("Build() = %v, want %v", err, first)
+			}
+			if spy.built != 0 || spy.freed != 1 {
+				t.Errorf("Build called the builder's Build %d times and Free %d times, want 0 and 1", spy.built, spy.freed)
+			}
+		})
+	}
+}
+
+func TestTxBuildError(t *testing.T) {
+	spy := &spyBuilder{Recorder: txbuilder.NewRecorder(gobuilder.NewBuilder())}
+	tx := txbuilder.NewTx(spy)
+	tx.AddGasObject(gasID, 7, digest)
+	_, err := tx.Build()
+	if !errors.Is(err, txbuilder.ErrMissingSender) {
+		t.Fatalf("Build() = %v, want missing sender", err)
+	}
+	if tx.Err() != err {
+		t.Errorf("Err() = %v, want the Build error", tx.Err())
+	}
+}
+
+func TestTxFreeThenBuild(t *testing.T) {
+	tx, spy := newSpyTx()
+	tx.SplitCoins(tx.Gas(), tx.U64(5)).Nested(0).TransferTo(sender)
+	tx.Free()
+	if _, err := tx.Build(); !errors.Is(err, txbuilder.ErrConsumed) {
+		t.Errorf("Build() after Free = %v, want ErrConsumed", err)
+	}
+	if spy.built != 0 {
+		t.Errorf("Build after Free reached the builder")
+	}
+}
+
+func TestTxBuild(t *testing.T) {
+	tx, spy := newSpyTx()
+	tx.SplitCoins(tx.Gas(), tx.U64(5)).Nested(0).TransferTo(sender)
+	if _, err := tx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if spy.built != 1 || spy.freed != 0 {
+		t.Errorf("Build called Build %d times and Free %d times, want 1 and 0", spy.built, spy.freed)
+	}
+}
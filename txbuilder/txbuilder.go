@@ -0,0 +1,205 @@
+// Package txbuilder defines the backend-neutral transaction builder API.
+//
+// Three backends implement Builder: the WASM builder (package gosuisdk,
+// builder.go) and the CGo builder (package v2, builder_cgo.go) over the
+// Rust TransactionBuilder, and the pure-Go builder (package gobuilder).
+// Code written against Builder works with any of them.
+//
+// Wrappers add behaviour on top of any Builder: Limited (WithLimits)
+// enforces a protocol limits profile, Recorder exports the calls made as a
+// Plan, and Tx threads a sticky error through a chain of calls.
+package txbuilder
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+// ObjectKind describes how an object is used as an input.
+type ObjectKind string
+
+const (
+	ObjectKindOwned     ObjectKind = "owned"
+	ObjectKindImmutable ObjectKind = "immutable"
+	ObjectKindReceiving ObjectKind = "receiving"
+	ObjectKindShared    ObjectKind = "shared"
+)
+
+// WithdrawalSource names the address balance a FundsWithdrawal draws from.
+type WithdrawalSource string
+
+const (
+	WithdrawFromSender  WithdrawalSource = "sender"
+	WithdrawFromSponsor WithdrawalSource = "sponsor"
+)
+
+// FundsWithdrawal reserves up to Amount of Balance<CoinType> from an address
+// balance.  The input is a Withdrawal<Balance<CoinType>> value, redeemed
+// for the funds by a framework call.  Source defaults to the sender.
+type FundsWithdrawal struct {
+	Amount   uint64           `json:"amount"`
+	CoinType typetag.TypeTag  `json:"coin_type"` // e.g. typetag.SUI
+	Source   WithdrawalSource `json:"source,omitempty"`
+}
+
+// CheckTypeTag returns an invalid_type_tag error for field of fn when t is
+// malformed (see typetag.TypeTag.Validate).  Tags from typetag.Parse always
+// pass; backends call it before encoding hand-built tags.
+func CheckTypeTag(fn string, code int64, field string, t typetag.TypeTag) error {
+	if err := t.Validate(); err != nil {
+		return NewError(fn, code, KindInvalidTypeTag, err.Error()).WithField(field)
+	}
+	return nil
+}
+
+// CheckTypeArgs is CheckTypeTag for the type_args list of a Move call.
+func CheckTypeArgs(fn string, code int64, typeArgs []typetag.TypeTag) error {
+	for i, t := range typeArgs {
+		if err := t.Validate(); err != nil {
+			return NewError(fn, code, KindInvalidTypeTag, err.Error()).WithField("type_args").WithIndex(i)
+		}
+	}
+	return nil
+}
+
+// MoveCallArg describes a single argument to a Move call.
+// Supply exactly one of ArgID (existing Argument) or PureBCS (raw bytes).
+type MoveCallArg struct {
+	ArgID   *uint64 // reference an existing Argument by ID
+	PureBCS []byte  // pre-encoded BCS bytes
+}
+
+// ArgID is a convenience constructor for a MoveCallArg that references an
+// existing Argument by ID.
+func ArgID(id uint64) MoveCallArg { return MoveCallArg{ArgID: &id} }
+
+// ArgBCS is a convenience constructor for a MoveCallArg that passes raw
+// pre-encoded BCS bytes.
+func ArgBCS(bcs []byte) MoveCallArg { return MoveCallArg{PureBCS: bcs} }
+
+// Builder is implemented by every transaction builder backend.
+// Argument IDs are only meaningful within the builder that returned them.
+// Every method reports failures as errors; after Build or Free the builder
+// returns ErrConsumed.  Implementations are NOT safe for concurrent use.
+type Builder interface {
+	// SetConfig sets the sender address, gas budget, and gas price.
+	SetConfig(sender Address, gasBudget, gasPrice uint64) error
+	// AddGasObject adds an owned gas coin.
+	AddGasObject(id ObjectID, version uint64, digest Digest) error
+	// GasArgument returns the Argument ID for the transaction's gas coin.
+	GasArgument() (uint64, error)
+
+	// InputObject pushes an object input and returns its Argument ID.
+	InputObject(id ObjectID, version uint64, digest Digest, kind ObjectKind, mutable bool) (uint64, error)
+	// InputFundsWithdrawal pushes a withdrawal from an address balance
+	// and returns its Argument ID.  Every call adds a new input.
+	InputFundsWithdrawal(w FundsWithdrawal) (uint64, error)
+
+	PureBool(v bool) (uint64, error)
+	PureU8(v uint8) (uint64, error)
+	PureU16(v uint16) (uint64, error)
+	PureU32(v uint32) (uint64, error)
+	PureU64(v uint64) (uint64, error)
+	PureU128(hi, lo uint64) (uint64, error)
+	PureAddress(addr Address) (uint64, error)
+	PureRawBCS(bcsBytes []byte) (uint64, error)
+
+	// Encoded in Go and pushed through PureRawBCS; see pure.go.
+	PureString(s string) (uint64, error)
+	PureASCIIString(s string) (uint64, error)
+	PureBytes(v []byte) (uint64, error)
+	PureU256(v *big.Int) (uint64, error)
+	PureID(id ObjectID) (uint64, error)
+	// PureOption pushes an Option<T> from T's BCS encoding; nil is None.
+	PureOption(value []byte) (uint64, error)
+	// PureVector pushes a vector<T> from its elements' BCS encodings.
+	PureVector(elems [][]byte) (uint64, error)
+
+	// NestedResult returns the Argument ID for the Nth sub-result of a
+	// multi-output command.
+	NestedResult(baseID, subIndex uint64) (uint64, error)
+
+	MoveCall(pkg ObjectID, module, function string, typeArgs []typetag.TypeTag, args []MoveCallArg) (uint64, error)
+	SplitCoins(coinArgID uint64, amountArgIDs []uint64) (uint64, error)
+	MergeCoins(targetCoinArgID uint64, sourceArgIDs []uint64) error
+	TransferObjects(objectArgIDs []uint64, recipientArgID uint64) error
+	// MakeMoveVec builds a vector<elemType>; a nil elemType is inferred
+	// from the elements, which must then be non-empty.
+	MakeMoveVec(elemType *typetag.TypeTag, elemArgIDs []uint64) (uint64, error)
+	Publish(modules [][]byte, dependencies []ObjectID) (uint64, error)
+	Upgrade(modules [][]byte, dependencies []ObjectID, packageID ObjectID, ticketArgID uint64) (uint64, error)
+
+	// Build serialises the transaction to BCS and consumes the builder.
+	Build() ([]byte, error)
+	// Free releases a builder that was not consumed by Build.  It is a
+	// no-op on a consumed builder.
+	Free()
+}
+
+// Cloner is a Builder that can copy an unbuilt transaction, so one command
+// graph is built more than once (simulated, then rebuilt with the estimated
+// budget) without being reconstructed.  The WASM, CGo and pure-Go builders
+// implement it.
+type Cloner interface {
+	Builder
+	// Clone returns an independent copy.  Argument IDs are the same in
+	// both; each copy is built or freed on its own.
+	Clone() (Builder, error)
+	// BuildWith builds a copy with the gas budget and price replaced, and
+	// the gas objects too when gas is non-nil.  The builder itself is not
+	// consumed.
+	BuildWith(gasBudget, gasPrice uint64, gas []GasObject) ([]byte, error)
+}
+
+// ErrConsumed is returned by a builder used after Build or Free.
+var ErrConsumed = errors.New("txbuilder: builder already built or freed")
+
+// Bytes is a byte vector in an FFI JSON payload.  It marshals as an array
+// of numbers, the form the FFI's serde expects for Vec<u8>, rather than
+// encoding/json's base64 string.  Every []byte in a payload, such as an
+// inline BCS MoveCall argument, goes through Bytes (or ModuleBytes for
+// Vec<Vec<u8>>).
+type Bytes []byte
+
+// MarshalJSON implements json.Marshaler.
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+	out := make([]byte, 0, 2+4*len(b))
+	out = append(out, '[')
+	for i, v := range b {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = strconv.AppendUint(out, uint64(v), 10)
+	}
+	return append(out, ']'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, reading an array of numbers.
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*b = nil
+		return nil
+	}
+	var vs []uint8
+	if err := json.Unmarshal(data, &vs); err != nil {
+		return err
+	}
+	*b = vs
+	return nil
+}
+
+// ModuleBytes converts compiled modules for JSON encoding as Vec<Vec<u8>>.
+func ModuleBytes(modules [][]byte) []Bytes {
+	out := make([]Bytes, len(modules))
+	for i, m := range modules {
+		out[i] = m
+	}
+	return out
+}
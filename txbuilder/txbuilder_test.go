@@ -0,0 +1,189 @@
+package txbuilder
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/pictorx/go-sui-sdk/typetag"
+)
+
+// FFI payloads carry byte vectors as JSON number arrays (serde's Vec<u8>);
+// a base64 string is rejected on the Rust side.
+func TestBytesJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"bytes", Bytes{1, 2, 255}, `[1,2,255]`},
+		{"empty", Bytes{}, `[]`},
+		{"nil", Bytes(nil), `null`},
+		{"pure_bcs", struct {
+			PureBCS Bytes `json:"pure_bcs,omitempty"`
+		}{Bytes{5, 0}}, `{"pure_bcs":[5,0]}`},
+		{"modules", map[string]any{"modules": ModuleBytes([][]byte{{0xa1, 0x1c}, {}})}, `{"modules":[[161,28],[]]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBytesJSONRoundTrip(t *testing.T) {
+	in := Bytes{0, 1, 127, 128, 255}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out Bytes
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(in, out) {
+		t.Errorf("round trip: got %v, want %v", out, in)
+	}
+}
+
+// recordOnly returns a Recorder that only records, for exercising the
+// Builder helpers without a backend.
+func recordOnly() *Recorder { return NewRecorder(nil) }
+
+func TestPureASCIIString(t *testing.T) {
+	for _, s := range []string{"", "plain", "a\nb", "tab\there", "\x00\x7f"} {
+		if _, err := PureASCIIString(recordOnly(), s); err != nil {
+			t.Errorf("%q: %v", s, err)
+		}
+	}
+	for _, s := range []string{"café", "\x80", "ok\xff"} {
+		var e *Error
+		if _, err := PureASCIIString(recordOnly(), s); !errors.As(err, &e) || e.Kind != KindInvalidValue {
+			t.Errorf("%q: %v, want an invalid_value error", s, err)
+		}
+	}
+}
+
+func TestPlanASCIIString(t *testing.T) {
+	encode := func(s string) error {
+		value, _ := json.Marshal(s)
+		_, err := (&PureInput{Type: "ascii_string", Value: value}).Encode()
+		return err
+	}
+	if err := encode("line\nbreak"); err != nil {
+		t.Errorf("control characters: %v", err)
+	}
+	if err := encode("café"); err == nil {
+		t.Error("non-ASCII string accepted")
+	}
+}
+
+// Typed plan values encode as BCS of their width; out-of-range values fail.
+func TestPlanPureEncode(t *testing.T) {
+	tests := []struct {
+		typ, value string
+		want       string // hex; "" when invalid
+	}{
+		{"bool", `true`, "01"},
+		{"u8", `255`, "ff"},
+		{"u8", `256`, ""},
+		{"u16", `"258"`, "0201"},
+		{"u32", `1`, "01000000"},
+		{"u64", `"18446744073709551615"`, "ffffffffffffffff"},
+		{"u64", `-1`, ""},
+		{"u128", `"340282366920938463463374607431768211455"`, "ffffffffffffffffffffffffffffffff"},
+		{"u128", `"340282366920938463463374607431768211456"`, ""},
+		{"u256", `1`, "01" + strings.Repeat("00", 31)},
+		{"u256", `-1`, ""},
+		{"vector<u8>", `"0x0102"`, "020102"},
+		{"string", `"hi"`, "026869"},
+	}
+	for _, tt := range tests {
+		got, err := (&PureInput{Type: tt.typ, Value: json.RawMessage(tt.value)}).Encode()
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%s %s: encoded as %x, want an error", tt.typ, tt.value, got)
+			}
+			continue
+		}
+		if err != nil || hex.EncodeToString(got) != tt.want {
+			t.Errorf("%s %s: %x, %v, want %s", tt.typ, tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestEncoders(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []byte
+		want string
+	}{
+		{"u128", EncodeU128(2, 1), "0100000000000000" + "0200000000000000"},
+		{"vector<u16>", EncodeVector([]uint16{1, 0x0203}), "0201000302"},
+		{"vector<bool> nil", EncodeVector[bool](nil), "00"},
+		{"vector<vector<u8>>", EncodeVectorOf([][]byte{EncodeBytes([]byte{9}), EncodeBytes(nil)}), "02010900"},
+		{"long vector<u8>", EncodeBytes(make([]byte, 200))[:2], "c801"},
+		{"none", EncodeOption(nil), "00"},
+		{"some", EncodeOption(EncodePrimitive(true)), "0101"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(tt.got); got != tt.want {
+			t.Errorf("%s: %s, want %s", tt.name, got, tt.want)
+		}
+	}
+	if _, err := EncodeU256(nil); err == nil {
+		t.Error("EncodeU256(nil) succeeded")
+	}
+	if _, err := EncodeU256(new(big.Int).Lsh(big.NewInt(1), 256)); err == nil {
+		t.Error("EncodeU256(2^256) succeeded")
+	}
+}
+
+// Plans carry type tags as canonical strings and reject malformed ones on
+// load; hand-built tags are rejected before they are recorded.
+func TestPlanTypeTags(t *testing.T) {
+	r := recordOnly()
+	coin := typetag.MustParse("0x2::coin::Coin<0x2::sui::SUI>")
+	if _, err := r.MoveCall(ObjectID{31: 2}, "pay", "keep", []typetag.TypeTag{coin}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.MakeMoveVec(&typetag.SUI, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(r.Plan())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Commands[0].MoveCall.TypeArgs; len(got) != 1 || !got[0].Equal(coin) {
+		t.Errorf("type_args round trip: %v", got)
+	}
+	if got := p.Commands[1].MakeMoveVec.Type; got == nil || !got.Equal(typetag.SUI) {
+		t.Errorf("make_move_vec type round trip: %v", got)
+	}
+
+	bad := []byte(`{"move_call": {"package": "0x2", "module": "m", "function": "f", "type_args": ["vector<"], "arguments": []}}`)
+	var c Command
+	if err := json.Unmarshal(bad, &c); err == nil {
+		t.Error("malformed type_args accepted")
+	}
+
+	err = CheckTypeArgs("command_move_call", -1, []typetag.TypeTag{typetag.SUI, {Kind: typetag.Struct}})
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != KindInvalidTypeTag || e.Field != "type_args" || e.Index == nil || *e.Index != 1 {
+		t.Errorf("CheckTypeArgs: %v, want invalid_type_tag at type_args[1]", err)
+	}
+}
@@ -0,0 +1,105 @@
+// bcs.go
+//
+// BCS encoding of TypeTag and StructTag.
+
+package typetag
+
+import (
+	"fmt"
+
+	"github.com/pictorx/go-sui-sdk/bcs"
+)
+
+// MarshalBCS implements bcs.Marshaler.
+func (t TypeTag) MarshalBCS(e *bcs.Encoder) error {
+	e.WriteULEB128(uint64(t.Kind))
+	switch t.Kind {
+	case Vector:
+		if t.Elem == nil {
+			return fmt.Errorf("typetag: vector without element type")
+		}
+		return t.Elem.MarshalBCS(e)
+	case Struct:
+		if t.Struct == nil {
+			return fmt.Errorf("typetag: struct kind without struct tag")
+		}
+		return t.Struct.MarshalBCS(e)
+	}
+	if t.Kind > U256 {
+		return fmt.Errorf("typetag: unknown kind %d", t.Kind)
+	}
+	return nil
+}
+
+// MarshalBCS implements bcs.Marshaler.
+func (s StructTag) MarshalBCS(e *bcs.Encoder) error {
+	e.WriteFixed(s.Address[:])
+	e.WriteString(s.Module)
+	e.WriteString(s.Name)
+	e.WriteULEB128(uint64(len(s.TypeParams)))
+	for _, p := range s.TypeParams {
+		if err := p.MarshalBCS(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (t *TypeTag) UnmarshalBCS(d *bcs.Decoder) error { return t.decode(d, 0) }
+
+// UnmarshalBCS implements bcs.Unmarshaler.
+func (s *StructTag) UnmarshalBCS(d *bcs.Decoder) error { return s.decode(d, 0) }
+
+func (t *TypeTag) decode(d *bcs.Decoder, depth int) error {
+	if depth >= maxDepth {
+		return fmt.Errorf("typetag: nesting deeper than %d", maxDepth)
+	}
+	kind, err := d.ReadULEB128()
+	if err != nil {
+		return err
+	}
+	if kind > uint64(U256) {
+		return fmt.Errorf("typetag: unknown kind %d", kind)
+	}
+	*t = TypeTag{Kind: Kind(kind)}
+	switch t.Kind {
+	case Vector:
+		t.Elem = new(TypeTag)
+		return t.Elem.decode(d, depth+1)
+	case Struct:
+		t.Struct = new(StructTag)
+		return t.Struct.decode(d, depth)
+	}
+	return nil
+}
+
+// decode reads a struct tag at the depth of its TypeTag; its type parameters
+// are one level deeper.
+func (s *StructTag) decode(d *bcs.Decoder, depth int) error {
+	addr, err := d.ReadFixed(32)
+	if err != nil {
+		return err
+	}
+	copy(s.Address[:], addr)
+	if s.Module, err = d.ReadString(); err != nil {
+		return err
+	}
+	if s.Name, err = d.ReadString(); err != nil {
+		return err
+	}
+	n, err := d.ReadLength()
+	if err != nil {
+		return err
+	}
+	s.TypeParams = nil
+	if n > 0 {
+		s.TypeParams = make([]TypeTag, n)
+	}
+	for i := range s.TypeParams {
+		if err := s.TypeParams[i].decode(d, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,467 @@
+// Package typetag parses, normalizes and compares Move type tags such as
+// "u64", "vector<u8>" or "0x2::coin::Coin<0x2::sui::SUI>".
+//
+// Addresses are held as 32 bytes, so "0x2::sui::SUI" and its 64-digit form
+// parse to equal tags.  String renders the canonical form Sui reports in
+// object types (0x + 64 hex digits); Short trims leading zeros ("0x2").
+// TypeTag and StructTag encode as sui_sdk_types::TypeTag / StructTag in
+// BCS, and as their canonical string in JSON.
+package typetag
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Kind is a TypeTag variant.  The values are the BCS variant indices.
+type Kind uint8
+
+const (
+	Bool    Kind = 0
+	U8      Kind = 1
+	U64     Kind = 2
+	U128    Kind = 3
+	Address Kind = 4
+	Signer  Kind = 5
+	Vector  Kind = 6
+	Struct  Kind = 7
+	U16     Kind = 8
+	U32     Kind = 9
+	U256    Kind = 10
+)
+
+// maxDepth bounds the nesting of a type tag, as Move does.  Parse, Validate
+// and BCS decoding all reject a 65th level.
+const maxDepth = 64
+
+var primitives = map[string]Kind{
+	"bool":    Bool,
+	"u8":      U8,
+	"u16":     U16,
+	"u32":     U32,
+	"u64":     U64,
+	"u128":    U128,
+	"u256":    U256,
+	"address": Address,
+	"signer":  Signer,
+}
+
+// TypeTag is a Move type.  Elem is set for Vector and Struct for Struct.
+type TypeTag struct {
+	Kind   Kind
+	Elem   *TypeTag
+	Struct *StructTag
+}
+
+// StructTag is a struct type with its type arguments.
+type StructTag struct {
+	Address    [32]byte
+	Module     string
+	Name       string
+	TypeParams []TypeTag
+}
+
+// Common tags.
+var (
+	SUI = MustParse("0x2::sui::SUI")
+)
+
+// VectorOf returns vector<elem>.
+func VectorOf(elem TypeTag) TypeTag {
+	return TypeTag{Kind: Vector, Elem: &elem}
+}
+
+// Coin returns 0x2::coin::Coin<t>.
+func Coin(t TypeTag) StructTag {
+	return StructTag{Address: framework, Module: "coin", Name: "Coin", TypeParams: []TypeTag{t}}
+}
+
+var framework = [32]byte{31: 2}
+
+// Tag wraps s as a TypeTag.
+func (s StructTag) Tag() TypeTag {
+	return TypeTag{Kind: Struct, Struct: &s}
+}
+
+// ── Parsing ───────────────────────────────────────────────────────────────────
+
+// Parse parses a type tag.  Addresses may be short ("0x2") or full.  Tags
+// nested deeper than 64 levels are rejected.
+func Parse(s string) (TypeTag, error) {
+	p := &parser{s: s}
+	tag, err := p.typeTag()
+	if err != nil {
+		return TypeTag{}, fmt.Errorf("invalid type tag %q: %w", s, err)
+	}
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return TypeTag{}, fmt.Errorf("invalid type tag %q: trailing input at offset %d", s, p.pos)
+	}
+	return tag, nil
+}
+
+// ParseStruct parses a struct type tag.
+func ParseStruct(s string) (StructTag, error) {
+	t, err := Parse(s)
+	if err != nil {
+		return StructTag{}, err
+	}
+	if t.Kind != Struct {
+		return StructTag{}, fmt.Errorf("invalid struct tag %q: not a struct type", s)
+	}
+	return *t.Struct, nil
+}
+
+// MustParse is Parse for constants; it panics on error.
+func MustParse(s string) TypeTag {
+	t, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Normalize returns the canonical form of the type tag s.
+func Normalize(s string) (string, error) {
+	t, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return t.String(), nil
+}
+
+type parser struct {
+	s     string
+	pos   int
+	depth int // enclosing vector and struct type parameter lists
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *parser) consume(tok string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.s[p.pos:], tok) {
+		p.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+// word reads an identifier-like token (letters, digits, '_' and the 'x' of
+// a 0x prefix).
+func (p *parser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *parser) typeTag() (TypeTag, error) {
+	start := p.pos
+	w := p.word()
+	if w == "" {
+		return TypeTag{}, fmt.Errorf("expected type at offset %d", p.pos)
+	}
+
+	if w == "vector" && !strings.HasPrefix(p.s[p.pos:], "::") {
+		if !p.consume("<") {
+			return TypeTag{}, fmt.Errorf("expected '<' after vector")
+		}
+		if err := p.enter(); err != nil {
+			return TypeTag{}, err
+		}
+		elem, err := p.typeTag()
+		if err != nil {
+			return TypeTag{}, err
+		}
+		p.depth--
+		if !p.consume(">") {
+			return TypeTag{}, fmt.Errorf("expected '>' to close vector")
+		}
+		return VectorOf(elem), nil
+	}
+	if kind, ok := primitives[w]; ok && !strings.HasPrefix(p.s[p.pos:], "::") {
+		return TypeTag{Kind: kind}, nil
+	}
+
+	p.pos = start
+	st, err := p.structTag()
+	if err != nil {
+		return TypeTag{}, err
+	}
+	return st.Tag(), nil
+}
+
+// enter descends into a type parameter list.
+func (p *parser) enter() error {
+	if p.depth++; p.depth >= maxDepth {
+		return fmt.Errorf("nesting deeper than %d at offset %d", maxDepth, p.pos)
+	}
+	return nil
+}
+
+func (p *parser) structTag() (StructTag, error) {
+	addr, err := parseAddress(p.word())
+	if err != nil {
+		return StructTag{}, err
+	}
+	if !p.consume("::") {
+		return StructTag{}, fmt.Errorf("expected '::' after address")
+	}
+	module := p.word()
+	if !IsValidIdentifier(module) {
+		return StructTag{}, fmt.Errorf("invalid module name %q", module)
+	}
+	if !p.consume("::") {
+		return StructTag{}, fmt.Errorf("expected '::' after module")
+	}
+	name := p.word()
+	if !IsValidIdentifier(name) {
+		return StructTag{}, fmt.Errorf("invalid struct name %q", name)
+	}
+
+	st := StructTag{Address: addr, Module: module, Name: name}
+	if p.consume("<") {
+		if err := p.enter(); err != nil {
+			return StructTag{}, err
+		}
+		defer func() { p.depth-- }()
+		for {
+			param, err := p.typeTag()
+			if err != nil {
+				return StructTag{}, err
+			}
+			st.TypeParams = append(st.TypeParams, param)
+			if p.consume(",") {
+				continue
+			}
+			if p.consume(">") {
+				break
+			}
+			return StructTag{}, fmt.Errorf("expected ',' or '>' in type parameters")
+		}
+	}
+	return st, nil
+}
+
+// parseAddress decodes a 0x-prefixed hex address, left-padding short forms.
+func parseAddress(s string) ([32]byte, error) {
+	var a [32]byte
+	h, ok := strings.CutPrefix(s, "0x")
+	if !ok || h == "" || len(h) > 64 {
+		return a, fmt.Errorf("invalid address %q", s)
+	}
+	if _, err := hex.Decode(a[:], []byte(strings.Repeat("0", 64-len(h))+h)); err != nil {
+		return a, fmt.Errorf("invalid address %q", s)
+	}
+	return a, nil
+}
+
+// IsValidIdentifier reports whether s is a valid Move identifier.
+func IsValidIdentifier(s string) bool {
+	if s == "" || s == "_" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ── Rendering ─────────────────────────────────────────────────────────────────
+
+// String renders t with 64-digit addresses, e.g.
+// "0x0000…0002::coin::Coin<0x0000…0002::sui::SUI>".
+func (t TypeTag) String() string { return t.format(false) }
+
+// Short renders t with leading address zeros trimmed, e.g.
+// "0x2::coin::Coin<0x2::sui::SUI>".
+func (t TypeTag) Short() string { return t.format(true) }
+
+func (s StructTag) String() string { return s.format(false) }
+func (s StructTag) Short() string  { return s.format(true) }
+
+func (t TypeTag) format(short bool) string {
+	switch t.Kind {
+	case Vector:
+		if t.Elem == nil {
+			return "vector<?>"
+		}
+		return "vector<" + t.Elem.format(short) + ">"
+	case Struct:
+		if t.Struct == nil {
+			return "?"
+		}
+		return t.Struct.format(short)
+	}
+	for name, kind := range primitives {
+		if kind == t.Kind {
+			return name
+		}
+	}
+	return fmt.Sprintf("TypeTag(%d)", t.Kind)
+}
+
+func (s StructTag) format(short bool) string {
+	var b strings.Builder
+	b.WriteString(formatAddress(s.Address, short))
+	b.WriteString("::" + s.Module + "::" + s.Name)
+	if len(s.TypeParams) > 0 {
+		b.WriteByte('<')
+		for i, p := range s.TypeParams {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(p.format(short))
+		}
+		b.WriteByte('>')
+	}
+	return b.String()
+}
+
+func formatAddress(a [32]byte, short bool) string {
+	s := fmt.Sprintf("%x", a[:])
+	if short {
+		if s = strings.TrimLeft(s, "0"); s == "" {
+			s = "0"
+		}
+	}
+	return "0x" + s
+}
+
+// MarshalText implements encoding.TextMarshaler with the canonical form.
+func (t TypeTag) MarshalText() ([]byte, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler with Parse.
+func (t *TypeTag) UnmarshalText(text []byte) error {
+	tag, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*t = tag
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler with the canonical form.
+func (s StructTag) MarshalText() ([]byte, error) { return s.Tag().MarshalText() }
+
+// UnmarshalText implements encoding.TextUnmarshaler with ParseStruct.
+func (s *StructTag) UnmarshalText(text []byte) error {
+	st, err := ParseStruct(string(text))
+	if err != nil {
+		return err
+	}
+	*s = st
+	return nil
+}
+
+// Validate reports a tag that Parse would not produce: a vector or struct
+// kind without its element or struct tag, an unknown kind, an invalid
+// identifier, or nesting deeper than 64 levels.
+func (t TypeTag) Validate() error { return t.check(0) }
+
+func (t TypeTag) check(depth int) error {
+	if depth >= maxDepth {
+		return fmt.Errorf("typetag: nesting deeper than %d", maxDepth)
+	}
+	switch t.Kind {
+	case Vector:
+		if t.Elem == nil {
+			return fmt.Errorf("typetag: vector without element type")
+		}
+		return t.Elem.check(depth + 1)
+	case Struct:
+		if t.Struct == nil {
+			return fmt.Errorf("typetag: struct kind without struct tag")
+		}
+		if !IsValidIdentifier(t.Struct.Module) || !IsValidIdentifier(t.Struct.Name) {
+			return fmt.Errorf("typetag: invalid struct %s::%s", t.Struct.Module, t.Struct.Name)
+		}
+		for _, p := range t.Struct.TypeParams {
+			if err := p.check(depth + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if t.Kind > U256 {
+		return fmt.Errorf("typetag: unknown kind %d", t.Kind)
+	}
+	return nil
+}
+
+// Depth returns the nesting depth of t: 1 for "u64" or "0x2::sui::SUI",
+// 2 for "vector<u8>" or "0x2::coin::Coin<0x2::sui::SUI>", and so on.
+func (t TypeTag) Depth() int {
+	switch {
+	case t.Kind == Vector && t.Elem != nil:
+		return 1 + t.Elem.Depth()
+	case t.Kind == Struct && t.Struct != nil:
+		deepest := 0
+		for _, p := range t.Struct.TypeParams {
+			deepest = max(deepest, p.Depth())
+		}
+		return 1 + deepest
+	}
+	return 1
+}
+
+// ── Comparison ────────────────────────────────────────────────────────────────
+
+// Equal reports whether t and u are the same type.
+func (t TypeTag) Equal(u TypeTag) bool {
+	if t.Kind != u.Kind {
+		return false
+	}
+	switch t.Kind {
+	case Vector:
+		return t.Elem != nil && u.Elem != nil && t.Elem.Equal(*u.Elem)
+	case Struct:
+		return t.Struct != nil && u.Struct != nil && t.Struct.Equal(*u.Struct)
+	}
+	return true
+}
+
+// Equal reports whether s and u are the same struct type, including type
+// arguments.
+func (s StructTag) Equal(u StructTag) bool {
+	if s.Address != u.Address || s.Module != u.Module || s.Name != u.Name ||
+		len(s.TypeParams) != len(u.TypeParams) {
+		return false
+	}
+	for i := range s.TypeParams {
+		if !s.TypeParams[i].Equal(u.TypeParams[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// SameStruct reports whether s and u are the same struct ignoring type
+// arguments, e.g. any Coin<T>.
+func (s StructTag) SameStruct(u StructTag) bool {
+	return s.Address == u.Address && s.Module == u.Module && s.Name == u.Name
+}
@@ -0,0 +1,218 @@
+package typetag
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pictorx/go-sui-sdk/bcs"
+)
+
+const (
+	two = "0x0000000000000000000000000000000000000000000000000000000000000002"
+	usd = "0x00000000000000000000000000000000000000000000000000000000000000ab"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, want, short string
+		depth           int
+	}{
+		{"u64", "u64", "u64", 1},
+		{"  bool ", "bool", "bool", 1},
+		{"address", "address", "address", 1},
+		{"vector<u8>", "vector<u8>", "vector<u8>", 2},
+		{"vector<vector<u256>>", "vector<vector<u256>>", "vector<vector<u256>>", 3},
+		{"0x2::sui::SUI", two + "::sui::SUI", "0x2::sui::SUI", 1},
+		{two + "::sui::SUI", two + "::sui::SUI", "0x2::sui::SUI", 1},
+		{"0x02::sui::SUI", two + "::sui::SUI", "0x2::sui::SUI", 1},
+		{"0x2::coin::Coin<0x2::sui::SUI>", two + "::coin::Coin<" + two + "::sui::SUI>", "0x2::coin::Coin<0x2::sui::SUI>", 2},
+		{
+			"0x2::dynamic_field::Field< 0x1::string::String ,vector<0xab::usd::USD>>",
+			two + "::dynamic_field::Field<0x0000000000000000000000000000000000000000000000000000000000000001::string::String, vector<" + usd + "::usd::USD>>",
+			"0x2::dynamic_field::Field<0x1::string::String, vector<0xab::usd::USD>>",
+			3,
+		},
+		{"0x2::vector::vector", two + "::vector::vector", "0x2::vector::vector", 1},
+		{"0x0::u64::u64", "0x0000000000000000000000000000000000000000000000000000000000000000::u64::u64", "0x0::u64::u64", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			tag, err := Parse(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tag.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			if got := tag.Short(); got != tt.short {
+				t.Errorf("Short() = %s, want %s", got, tt.short)
+			}
+			if got := tag.Depth(); got != tt.depth {
+				t.Errorf("Depth() = %d, want %d", got, tt.depth)
+			}
+			again, err := Parse(tag.String())
+			if err != nil || !again.Equal(tag) {
+				t.Errorf("String() does not round trip: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"u65",
+		"vector",
+		"vector<>",
+		"vector<u8",
+		"vector<u8>>",
+		"vector(u8)",
+		"0x2::sui",
+		"0x2::sui::",
+		"0x2::sui::SUI<",
+		"0x2::sui::SUI<>",
+		"0x2::coin::Coin<0x2::sui::SUI",
+		"0x2::coin::Coin<0x2::sui::SUI,>",
+		"0x2::coin::Coin<u8 u8>",
+		"2::sui::SUI",
+		"0x::sui::SUI",
+		"0xg::sui::SUI",
+		"0x" + strings.Repeat("1", 65) + "::sui::SUI",
+		"0x2::1sui::SUI",
+		"0x2::_::SUI",
+		"0x2::sui::SUI::extra",
+	} {
+		if tag, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %s, want error", in, tag)
+		}
+	}
+}
+
+func TestParseDepth(t *testing.T) {
+	nest := func(n int) string {
+		return strings.Repeat("vector<", n-1) + "u8" + strings.Repeat(">", n-1)
+	}
+	tag, err := Parse(nest(maxDepth))
+	if err != nil {
+		t.Fatalf("depth %d: %v", maxDepth, err)
+	}
+	if tag.Depth() != maxDepth {
+		t.Errorf("Depth() = %d, want %d", tag.Depth(), maxDepth)
+	}
+	if _, err := Parse(nest(maxDepth + 1)); err == nil {
+		t.Errorf("depth %d accepted", maxDepth+1)
+	}
+	structs := strings.Repeat("0x2::m::S<", maxDepth) + "u8" + strings.Repeat(">", maxDepth)
+	if _, err := Parse(structs); err == nil {
+		t.Errorf("struct nesting of depth %d accepted", maxDepth+1)
+	}
+	if _, err := VectorOf(tag).MarshalText(); err == nil {
+		t.Errorf("MarshalText accepted depth %d", maxDepth+1)
+	}
+
+	// BCS decoding draws the line at the same depth.
+	decode := func(tag TypeTag) error {
+		data, err := bcs.Marshal(tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out TypeTag
+		return bcs.Unmarshal(data, &out)
+	}
+	if err := decode(tag); err != nil {
+		t.Errorf("BCS decoding of depth %d: %v", maxDepth, err)
+	}
+	if err := decode(VectorOf(tag)); err == nil {
+		t.Errorf("BCS decoding accepted depth %d", maxDepth+1)
+	}
+	deep := MustParse(strings.Repeat("0x2::m::S<", maxDepth-1) + "u8" + strings.Repeat(">", maxDepth-1))
+	if err := decode(deep); err != nil {
+		t.Errorf("BCS decoding of struct nesting of depth %d: %v", maxDepth, err)
+	}
+	if err := decode(Coin(deep).Tag()); err == nil {
+		t.Errorf("BCS decoding accepted struct nesting of depth %d", maxDepth+1)
+	}
+}
+
+func TestDepth(t *testing.T) {
+	for s, want := range map[string]int{
+		"u64":                            1,
+		"0x2::sui::SUI":                  1,
+		"vector<u8>":                     2,
+		"0x2::coin::Coin<0x2::sui::SUI>": 2,
+		"0x2::table::Table<vector<u8>, 0x2::coin::Coin<0x2::sui::SUI>>": 3,
+		"0x2::m::Pair<u8, vector<vector<u8>>>":                          4,
+	} {
+		if got := MustParse(s).Depth(); got != want {
+			t.Errorf("Depth(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"0x2::sui::SUI", two + "::sui::SUI", true},
+		{"0x2::coin::Coin<0x2::sui::SUI>", "0x0002::coin::Coin<" + two + "::sui::SUI>", true},
+		{"0x2::coin::Coin<0x2::sui::SUI>", "0x2::coin::Coin<0xab::usd::USD>", false},
+		{"vector<u8>", "vector<u16>", false},
+		{"0x2::sui::SUI", "0x3::sui::SUI", false},
+		{"0x2::sui::SUI", "0x2::coin::Coin<0x2::sui::SUI>", false},
+	}
+	for _, tt := range tests {
+		if got := MustParse(tt.a).Equal(MustParse(tt.b)); got != tt.want {
+			t.Errorf("Equal(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	in := struct {
+		Type   TypeTag    `json:"type"`
+		Struct StructTag  `json:"struct"`
+		Opt    *TypeTag   `json:"opt,omitempty"`
+		Args   []TypeTag  `json:"args"`
+		Coin   *StructTag `json:"coin,omitempty"`
+	}{
+		Type:   MustParse("vector<u8>"),
+		Struct: Coin(SUI),
+		Args:   []TypeTag{SUI, MustParse("u64")},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var text struct {
+		Type, Struct string
+		Args         []string
+	}
+	if err := json.Unmarshal(data, &text); err != nil {
+		t.Fatal(err)
+	}
+	if text.Type != "vector<u8>" || text.Struct != two+"::coin::Coin<"+two+"::sui::SUI>" ||
+		strings.Join(text.Args, " ") != two+"::sui::SUI u64" {
+		t.Errorf("got %s", data)
+	}
+
+	out := in
+	out.Args = nil
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.Type.Equal(in.Type) || !out.Struct.Equal(in.Struct) || len(out.Args) != 2 || !out.Args[0].Equal(SUI) {
+		t.Errorf("round trip: got %+v", out)
+	}
+
+	if err := json.Unmarshal([]byte(`{"type":"vector<"}`), &out); err == nil {
+		t.Error("malformed type accepted")
+	}
+	if err := json.Unmarshal([]byte(`{"struct":"u64"}`), &out); err == nil {
+		t.Error("primitive accepted as a struct tag")
+	}
+	if _, err := json.Marshal(TypeTag{Kind: Vector}); err == nil {
+		t.Error("vector without element marshalled")
+	}
+}
@@ -1,3 +1,5 @@
+//go:build txbuilder_cgo
+
 package v2
 
 // builder_cgo.go
@@ -5,17 +7,18 @@ package v2
 // CGo bindings for the transaction_builder static library.
 //
 // This file is a drop-in replacement for builder.go (the WASM version).
-// The public API is identical — only the build tag and import differ.
+// The public API is identical — both implement txbuilder.Builder, and both
+// report rejected calls as *txbuilder.Error read from the library's
+// per-thread last_error record.
 //
 // Build tags
 // ----------
 // WASM build (original):
-//   go build                          (no tag — builder.go is active)
+//   go build                          (no tag — only builder.go is active)
 //
 // Static-lib build (this file):
-//   go build -tags txbuilder_cgo      (builder_cgo.go is active instead)
-//
-// Both files live in the same package; exactly one is compiled at a time.
+//   go build -tags txbuilder_cgo      (builder_cgo.go is compiled as well and
+//                                      registered as the "cgo" backend)
 //
 // Prerequisites for the static-lib build
 // ---------------------------------------
@@ -60,8 +63,12 @@ import "C"
 import (
 	"encoding/binary"
 	"encoding/json"
-	"fmt"
+	"math/big"
+	"runtime"
 	"unsafe"
+
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/pictorx/go-sui-sdk/typetag"
 )
 
 // ── Builder ───────────────────────────────────────────────────────────────────
@@ -72,6 +79,8 @@ type Builder struct {
 	ptr *C.TransactionBuilder // nil after Build() or Free()
 }
 
+var _ txbuilder.Cloner = (*Builder)(nil)
+
 // NewBuilder instantiates a fresh TransactionBuilder inside the static library.
 // The ctx parameter is accepted for API compatibility with the WASM version
 // but is not used — native calls are synchronous.
@@ -92,8 +101,10 @@ func (b *Builder) Free() {
 // ── Configuration ─────────────────────────────────────────────────────────────
 
 // SetConfig sets the sender address, gas budget, and gas price.
-// sender must be a 0x-prefixed 32-byte hex string.
-func (b *Builder) SetConfig(sender string, gasBudget, gasPrice uint64) error {
+func (b *Builder) SetConfig(sender txbuilder.Address, gasBudget, gasPrice uint64) error {
+	if b.ptr == nil {
+		return txbuilder.ErrConsumed
+	}
 	payload, _ := json.Marshal(map[string]any{
 		"sender":     sender,
 		"gas_budget": gasBudget,
@@ -101,9 +112,10 @@ func (b *Builder) SetConfig(sender string, gasBudget, gasPrice uint64) error {
 	})
 	cptr, clen := goBytesCopy(payload)
 	defer C.free(unsafe.Pointer(cptr))
-	code := C.set_config(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen))
+	defer lockThread()()
+	code := int64(C.set_config(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen)))
 	if code != 1 {
-		return fmt.Errorf("set_config failed (code %d) — check sender address format", code)
+		return lastError("set_config", code)
 	}
 	return nil
 }
@@ -111,8 +123,11 @@ func (b *Builder) SetConfig(sender string, gasBudget, gasPrice uint64) error {
 // ── Gas objects ───────────────────────────────────────────────────────────────
 
 // AddGasObject adds an owned gas coin identified by its object ID, version,
-// and base-58 digest string.
-func (b *Builder) AddGasObject(id string, version uint64, digest string) error {
+// and digest.
+func (b *Builder) AddGasObject(id txbuilder.ObjectID, version uint64, digest txbuilder.Digest) error {
+	if b.ptr == nil {
+		return txbuilder.ErrConsumed
+	}
 	payload, _ := json.Marshal(map[string]any{
 		"id":      id,
 		"version": version,
@@ -120,42 +135,45 @@ func (b *Builder) AddGasObject(id string, version uint64, digest string) error {
 	})
 	cptr, clen := goBytesCopy(payload)
 	defer C.free(unsafe.Pointer(cptr))
-	code := C.add_gas_object(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen))
-	switch code {
-	case 1:
-		return nil
-	case -2:
-		return fmt.Errorf("add_gas_object: invalid digest %q", digest)
-	default:
-		return fmt.Errorf("add_gas_object failed (code %d)", code)
+	defer lockThread()()
+	code := int64(C.add_gas_object(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen)))
+	if code != 1 {
+		return lastError("add_gas_object", code)
 	}
+	return nil
 }
 
 // ── Gas pseudo-input ──────────────────────────────────────────────────────────
 
 // GasArgument returns the Argument ID for the transaction's gas coin.
 // Idempotent — always returns the same ID within one builder.
-func (b *Builder) GasArgument() uint64 {
-	return uint64(C.gas_argument(b.ptr))
+func (b *Builder) GasArgument() (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
+	return uint64(C.gas_argument(b.ptr)), nil
 }
 
 // ── Object inputs ─────────────────────────────────────────────────────────────
 
 // ObjectKind describes how an object is used as an input.
-type ObjectKind string
+type ObjectKind = txbuilder.ObjectKind
 
 const (
-	ObjectKindOwned     ObjectKind = "owned"
-	ObjectKindImmutable ObjectKind = "immutable"
-	ObjectKindReceiving ObjectKind = "receiving"
-	ObjectKindShared    ObjectKind = "shared"
+	ObjectKindOwned     = txbuilder.ObjectKindOwned
+	ObjectKindImmutable = txbuilder.ObjectKindImmutable
+	ObjectKindReceiving = txbuilder.ObjectKindReceiving
+	ObjectKindShared    = txbuilder.ObjectKindShared
 )
 
 // InputObject pushes an object input and returns its Argument ID.
 //
 // For owned / immutable / receiving: supply id, version, digest, kind.
 // For shared: supply id, version, mutable, kind="shared" (digest is ignored).
-func (b *Builder) InputObject(id string, version uint64, digest string, kind ObjectKind, mutable bool) (uint64, error) {
+func (b *Builder) InputObject(id txbuilder.ObjectID, version uint64, digest txbuilder.Digest, kind ObjectKind, mutable bool) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
 	m := map[string]any{
 		"id":      id,
 		"version": version,
@@ -169,9 +187,30 @@ func (b *Builder) InputObject(id string, version uint64, digest string, kind Obj
 	payload, _ := json.Marshal(m)
 	cptr, clen := goBytesCopy(payload)
 	defer C.free(unsafe.Pointer(cptr))
+	defer lockThread()()
 	res := int64(C.input_object(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen)))
 	if res < 0 {
-		return 0, fmt.Errorf("input_object failed (code %d)", res)
+		return 0, lastError("input_object", res)
+	}
+	return uint64(res), nil
+}
+
+// InputFundsWithdrawal pushes a withdrawal from an address balance and
+// returns its Argument ID.  Every call adds a new input.
+func (b *Builder) InputFundsWithdrawal(w txbuilder.FundsWithdrawal) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
+	if err := txbuilder.CheckTypeTag("input_funds_withdrawal", -2, "coin_type", w.CoinType); err != nil {
+		return 0, err
+	}
+	payload, _ := json.Marshal(w)
+	cptr, clen := goBytesCopy(payload)
+	defer C.free(unsafe.Pointer(cptr))
+	defer lockThread()()
+	res := int64(C.input_funds_withdrawal(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen)))
+	if res < 0 {
+		return 0, lastError("input_funds_withdrawal", res)
 	}
 	return uint64(res), nil
 }
@@ -179,92 +218,164 @@ func (b *Builder) InputObject(id string, version uint64, digest string, kind Obj
 // ── Pure-value helpers ────────────────────────────────────────────────────────
 
 // PureBool pushes a BCS-encoded bool and returns its Argument ID.
-func (b *Builder) PureBool(v bool) uint64 {
+func (b *Builder) PureBool(v bool) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
 	var u C.uint8_t
 	if v {
 		u = 1
 	}
-	return uint64(C.pure_bool(b.ptr, u))
+	return uint64(C.pure_bool(b.ptr, u)), nil
 }
 
 // PureU8 pushes a BCS-encoded u8 and returns its Argument ID.
-func (b *Builder) PureU8(v uint8) uint64 {
-	return uint64(C.pure_u8(b.ptr, C.uint8_t(v)))
+func (b *Builder) PureU8(v uint8) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
+	return uint64(C.pure_u8(b.ptr, C.uint8_t(v))), nil
 }
 
 // PureU16 pushes a BCS-encoded u16 and returns its Argument ID.
-func (b *Builder) PureU16(v uint16) uint64 {
-	return uint64(C.pure_u16(b.ptr, C.uint16_t(v)))
+func (b *Builder) PureU16(v uint16) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
+	return uint64(C.pure_u16(b.ptr, C.uint16_t(v))), nil
 }
 
 // PureU32 pushes a BCS-encoded u32 and returns its Argument ID.
-func (b *Builder) PureU32(v uint32) uint64 {
-	return uint64(C.pure_u32(b.ptr, C.uint32_t(v)))
+func (b *Builder) PureU32(v uint32) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
+	return uint64(C.pure_u32(b.ptr, C.uint32_t(v))), nil
 }
 
 // PureU64 pushes a BCS-encoded u64 and returns its Argument ID.
-func (b *Builder) PureU64(v uint64) uint64 {
-	return uint64(C.pure_u64(b.ptr, C.uint64_t(v)))
+func (b *Builder) PureU64(v uint64) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
+	return uint64(C.pure_u64(b.ptr, C.uint64_t(v))), nil
 }
 
 // PureU128 pushes a BCS-encoded u128 (supplied as high/low uint64 halves)
 // and returns its Argument ID.
-func (b *Builder) PureU128(hi, lo uint64) uint64 {
+func (b *Builder) PureU128(hi, lo uint64) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
 	// CGo signature: pure_u128(builder, lo, hi) — lo first, matching Rust.
-	return uint64(C.pure_u128(b.ptr, C.uint64_t(lo), C.uint64_t(hi)))
+	return uint64(C.pure_u128(b.ptr, C.uint64_t(lo), C.uint64_t(hi))), nil
 }
 
-// PureAddress pushes a BCS-encoded Sui address (bare 0x-prefixed hex string)
-// and returns its Argument ID.
-func (b *Builder) PureAddress(addr string) (uint64, error) {
-	cptr, clen := goBytesCopy([]byte(addr))
+// PureAddress pushes a BCS-encoded Sui address and returns its Argument ID.
+func (b *Builder) PureAddress(addr txbuilder.Address) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
+	cptr, clen := goBytesCopy([]byte(addr.String()))
 	defer C.free(unsafe.Pointer(cptr))
+	defer lockThread()()
 	res := int64(C.pure_address(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen)))
 	if res < 0 {
-		return 0, fmt.Errorf("pure_address: invalid address %q", addr)
+		return 0, lastError("pure_address", res)
 	}
 	return uint64(res), nil
 }
 
 // PureRawBCS pushes already-BCS-encoded bytes as a pure argument and returns
 // its Argument ID.
-func (b *Builder) PureRawBCS(bcsBytes []byte) uint64 {
+func (b *Builder) PureRawBCS(bcsBytes []byte) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
 	cptr, clen := goBytesCopy(bcsBytes)
 	defer C.free(unsafe.Pointer(cptr))
-	return uint64(C.pure_raw_bcs(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen)))
+	return uint64(C.pure_raw_bcs(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen))), nil
+}
+
+// PureString pushes a UTF-8 Move String and returns its Argument ID.
+func (b *Builder) PureString(s string) (uint64, error) {
+	return txbuilder.PureString(b, s)
+}
+
+// PureASCIIString pushes a Move ascii::String and returns its Argument ID.
+func (b *Builder) PureASCIIString(s string) (uint64, error) {
+	return txbuilder.PureASCIIString(b, s)
+}
+
+// PureBytes pushes a vector<u8> and returns its Argument ID.
+func (b *Builder) PureBytes(v []byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeBytes(v))
+}
+
+// PureU256 pushes a u256 and returns its Argument ID.
+func (b *Builder) PureU256(v *big.Int) (uint64, error) {
+	return txbuilder.PureU256(b, v)
+}
+
+// PureID pushes an object ID (0x2::object::ID) and returns its Argument ID.
+func (b *Builder) PureID(id txbuilder.ObjectID) (uint64, error) {
+	return txbuilder.PureID(b, id)
+}
+
+// PureOption pushes an Option<T> built from T's BCS encoding (nil for None)
+// and returns its Argument ID.
+func (b *Builder) PureOption(value []byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeOption(value))
+}
+
+// PureVector pushes a vector<T> built from its elements' BCS encodings and
+// returns its Argument ID.  Use txbuilder.PureVec for vectors of primitives.
+func (b *Builder) PureVector(elems [][]byte) (uint64, error) {
+	return b.PureRawBCS(txbuilder.EncodeVectorOf(elems))
 }
 
 // ── Nested result ─────────────────────────────────────────────────────────────
 
 // NestedResult returns the Argument ID for the Nth sub-result of a
 // multi-output command (e.g. the Kth coin from SplitCoins).
-func (b *Builder) NestedResult(baseID, subIndex uint64) uint64 {
-	return uint64(C.nested_result(b.ptr, C.uint64_t(baseID), C.uint64_t(subIndex)))
+func (b *Builder) NestedResult(baseID, subIndex uint64) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
+	defer lockThread()()
+	res := int64(C.nested_result(b.ptr, C.uint64_t(baseID), C.uint64_t(subIndex)))
+	if res < 0 {
+		return 0, lastError("nested_result", res)
+	}
+	return uint64(res), nil
 }
 
 // ── Commands ──────────────────────────────────────────────────────────────────
 
 // MoveCallArg describes a single argument to a Move call.
 // Supply exactly one of ArgID (existing Argument) or PureBCS (raw bytes).
-type MoveCallArg struct {
-	ArgID   *uint64
-	PureBCS []byte
-}
+type MoveCallArg = txbuilder.MoveCallArg
 
 // ArgID is a convenience constructor for a MoveCallArg that references an
 // existing Argument by ID.
-func ArgID(id uint64) MoveCallArg { return MoveCallArg{ArgID: &id} }
+func ArgID(id uint64) MoveCallArg { return txbuilder.ArgID(id) }
 
 // ArgBCS is a convenience constructor for a MoveCallArg that passes raw
 // pre-encoded BCS bytes.
-func ArgBCS(bcs []byte) MoveCallArg { return MoveCallArg{PureBCS: bcs} }
+func ArgBCS(bcs []byte) MoveCallArg { return txbuilder.ArgBCS(bcs) }
 
 // MoveCall executes an entry or public Move function and returns the result
 // Argument ID.
-func (b *Builder) MoveCall(pkg, module, function string, typeArgs []string, args []MoveCallArg) (uint64, error) {
+func (b *Builder) MoveCall(pkg txbuilder.ObjectID, module, function string, typeArgs []typetag.TypeTag, args []MoveCallArg) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
+	if err := txbuilder.CheckTypeArgs("command_move_call", -1, typeArgs); err != nil {
+		return 0, err
+	}
 	type callArgJSON struct {
-		ID      *uint64 `json:"id,omitempty"`
-		PureBCS []byte  `json:"pure_bcs,omitempty"`
+		ID      *uint64         `json:"id,omitempty"`
+		PureBCS txbuilder.Bytes `json:"pure_bcs,omitempty"`
 	}
 	jsonArgs := make([]callArgJSON, len(args))
 	for i, a := range args {
@@ -283,9 +394,10 @@ func (b *Builder) MoveCall(pkg, module, function string, typeArgs []string, args
 	})
 	cptr, clen := goBytesCopy(payload)
 	defer C.free(unsafe.Pointer(cptr))
+	defer lockThread()()
 	res := int64(C.command_move_call(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen)))
 	if res < 0 {
-		return 0, fmt.Errorf("command_move_call failed (code %d)", res)
+		return 0, lastError("command_move_call", res)
 	}
 	return uint64(res), nil
 }
@@ -294,14 +406,18 @@ func (b *Builder) MoveCall(pkg, module, function string, typeArgs []string, args
 // amountArgIDs must be Argument IDs returned by PureU64.
 // Returns the base Argument ID; use NestedResult(base, i) to address coin i.
 func (b *Builder) SplitCoins(coinArgID uint64, amountArgIDs []uint64) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
 	if len(amountArgIDs) == 0 {
-		return 0, fmt.Errorf("SplitCoins: at least one amount required")
+		return 0, txbuilder.NewError("command_split_coins", -1, txbuilder.KindEmptyList, "at least one amount required").WithField("amounts")
 	}
 	cAmounts, cCount := goU64SliceCopy(amountArgIDs)
 	defer C.free(unsafe.Pointer(cAmounts))
+	defer lockThread()()
 	res := int64(C.command_split_coins(b.ptr, C.uint64_t(coinArgID), cAmounts, cCount))
 	if res < 0 {
-		return 0, fmt.Errorf("command_split_coins failed (code %d)", res)
+		return 0, lastError("command_split_coins", res)
 	}
 	return uint64(res), nil
 }
@@ -309,14 +425,18 @@ func (b *Builder) SplitCoins(coinArgID uint64, amountArgIDs []uint64) (uint64, e
 // MergeCoins merges sourceArgIDs into targetCoinArgID.
 // Produces no result; the target coin absorbs all sources.
 func (b *Builder) MergeCoins(targetCoinArgID uint64, sourceArgIDs []uint64) error {
+	if b.ptr == nil {
+		return txbuilder.ErrConsumed
+	}
 	if len(sourceArgIDs) == 0 {
-		return fmt.Errorf("MergeCoins: at least one source required")
+		return txbuilder.NewError("command_merge_coins", -1, txbuilder.KindEmptyList, "at least one source required").WithField("sources")
 	}
 	cSrcs, cCount := goU64SliceCopy(sourceArgIDs)
 	defer C.free(unsafe.Pointer(cSrcs))
-	code := int32(C.command_merge_coins(b.ptr, C.uint64_t(targetCoinArgID), cSrcs, cCount))
+	defer lockThread()()
+	code := int64(C.command_merge_coins(b.ptr, C.uint64_t(targetCoinArgID), cSrcs, cCount))
 	if code != 1 {
-		return fmt.Errorf("command_merge_coins failed (code %d)", code)
+		return lastError("command_merge_coins", code)
 	}
 	return nil
 }
@@ -324,26 +444,36 @@ func (b *Builder) MergeCoins(targetCoinArgID uint64, sourceArgIDs []uint64) erro
 // TransferObjects sends objectArgIDs to the address identified by recipientArgID.
 // recipientArgID must be an Argument ID returned by PureAddress.
 func (b *Builder) TransferObjects(objectArgIDs []uint64, recipientArgID uint64) error {
+	if b.ptr == nil {
+		return txbuilder.ErrConsumed
+	}
 	if len(objectArgIDs) == 0 {
-		return fmt.Errorf("TransferObjects: at least one object required")
+		return txbuilder.NewError("command_transfer_objects", -1, txbuilder.KindEmptyList, "at least one object required").WithField("objects")
 	}
 	cObjs, cCount := goU64SliceCopy(objectArgIDs)
 	defer C.free(unsafe.Pointer(cObjs))
-	code := int32(C.command_transfer_objects(b.ptr, cObjs, cCount, C.uint64_t(recipientArgID)))
+	defer lockThread()()
+	code := int64(C.command_transfer_objects(b.ptr, cObjs, cCount, C.uint64_t(recipientArgID)))
 	if code != 1 {
-		return fmt.Errorf("command_transfer_objects failed (code %d)", code)
+		return lastError("command_transfer_objects", code)
 	}
 	return nil
 }
 
 // MakeMoveVec constructs a Move vector<T> from elemArgIDs.
-// typeTag is the element type string (e.g. "0x2::sui::SUI"); pass "" to infer.
+// elemType is the element type; pass nil to infer.
 // Returns the result Argument ID.
-func (b *Builder) MakeMoveVec(typeTag string, elemArgIDs []uint64) (uint64, error) {
+func (b *Builder) MakeMoveVec(elemType *typetag.TypeTag, elemArgIDs []uint64) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
 	var ttPtr *C.uint8_t
 	var ttLen C.size_t
-	if typeTag != "" {
-		p, l := goBytesCopy([]byte(typeTag))
+	if elemType != nil {
+		if err := txbuilder.CheckTypeTag("command_make_move_vec", -2, "type_tag", *elemType); err != nil {
+			return 0, err
+		}
+		p, l := goBytesCopy([]byte(elemType.String()))
 		defer C.free(unsafe.Pointer(p))
 		ttPtr = (*C.uint8_t)(p)
 		ttLen = C.size_t(l)
@@ -358,27 +488,32 @@ func (b *Builder) MakeMoveVec(typeTag string, elemArgIDs []uint64) (uint64, erro
 		elemsCount = c
 	}
 
+	defer lockThread()()
 	res := int64(C.command_make_move_vec(b.ptr, ttPtr, ttLen, elemsPtr, elemsCount))
 	if res < 0 {
-		return 0, fmt.Errorf("command_make_move_vec failed (code %d)", res)
+		return 0, lastError("command_make_move_vec", res)
 	}
 	return uint64(res), nil
 }
 
 // Publish publishes a new Move package.
 // modules is a slice of compiled module bytecodes.
-// dependencies is a slice of 0x-prefixed package IDs this package depends on.
+// dependencies lists the IDs of the packages this package depends on.
 // Returns the UpgradeCap Argument ID.
-func (b *Builder) Publish(modules [][]byte, dependencies []string) (uint64, error) {
+func (b *Builder) Publish(modules [][]byte, dependencies []txbuilder.ObjectID) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
 	payload, _ := json.Marshal(map[string]any{
-		"modules":      modules,
+		"modules":      txbuilder.ModuleBytes(modules),
 		"dependencies": dependencies,
 	})
 	cptr, clen := goBytesCopy(payload)
 	defer C.free(unsafe.Pointer(cptr))
+	defer lockThread()()
 	res := int64(C.command_publish(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen)))
 	if res < 0 {
-		return 0, fmt.Errorf("command_publish failed (code %d)", res)
+		return 0, lastError("command_publish", res)
 	}
 	return uint64(res), nil
 }
@@ -387,34 +522,87 @@ func (b *Builder) Publish(modules [][]byte, dependencies []string) (uint64, erro
 // packageID is the on-chain ID of the package being upgraded.
 // ticketArgID is the Argument ID of the UpgradeTicket from authorize_upgrade.
 // Returns the UpgradeReceipt Argument ID.
-func (b *Builder) Upgrade(modules [][]byte, dependencies []string, packageID string, ticketArgID uint64) (uint64, error) {
+func (b *Builder) Upgrade(modules [][]byte, dependencies []txbuilder.ObjectID, packageID txbuilder.ObjectID, ticketArgID uint64) (uint64, error) {
+	if b.ptr == nil {
+		return 0, txbuilder.ErrConsumed
+	}
 	payload, _ := json.Marshal(map[string]any{
-		"modules":       modules,
+		"modules":       txbuilder.ModuleBytes(modules),
 		"dependencies":  dependencies,
 		"package":       packageID,
 		"ticket_arg_id": ticketArgID,
 	})
 	cptr, clen := goBytesCopy(payload)
 	defer C.free(unsafe.Pointer(cptr))
+	defer lockThread()()
 	res := int64(C.command_upgrade(b.ptr, (*C.uint8_t)(cptr), C.size_t(clen)))
 	if res < 0 {
-		return 0, fmt.Errorf("command_upgrade failed (code %d)", res)
+		return 0, lastError("command_upgrade", res)
 	}
 	return uint64(res), nil
 }
 
 // ── Finalisation ─────────────────────────────────────────────────────────────
 
+// Clone copies the unbuilt builder.  Argument IDs are the same in both; the
+// copy is built or freed on its own.
+func (b *Builder) Clone() (txbuilder.Builder, error) {
+	return b.clone()
+}
+
+func (b *Builder) clone() (*Builder, error) {
+	if b.ptr == nil {
+		return nil, txbuilder.ErrConsumed
+	}
+	defer lockThread()()
+	ptr := C.clone_builder(b.ptr)
+	if ptr == nil {
+		return nil, lastError("clone_builder", 0)
+	}
+	return &Builder{ptr: ptr}, nil
+}
+
+// BuildWith builds a copy with the gas budget and price replaced, and the
+// gas objects too when gas is non-nil.  b is not consumed.
+func (b *Builder) BuildWith(gasBudget, gasPrice uint64, gas []txbuilder.GasObject) ([]byte, error) {
+	c, err := b.clone()
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{"gas_budget": gasBudget, "gas_price": gasPrice}
+	if gas != nil {
+		fields["gas"] = gas
+	}
+	payload, _ := json.Marshal(fields)
+	cptr, clen := goBytesCopy(payload)
+	defer C.free(unsafe.Pointer(cptr))
+	unlock := lockThread()
+	code := int64(C.set_gas(c.ptr, (*C.uint8_t)(cptr), C.size_t(clen)))
+	if code != 1 {
+		err := lastError("set_gas", code)
+		unlock()
+		c.Free()
+		return nil, err
+	}
+	unlock()
+	return c.Build()
+}
+
 // Build serialises the transaction to BCS bytes and returns them.
 // The builder is consumed — do NOT call Free() after a successful Build().
-// Returns an error if any required field is missing or if build fails.
+// A failed build returns a *txbuilder.Error naming the missing field or
+// incomplete object.
 func (b *Builder) Build() ([]byte, error) {
+	if b.ptr == nil {
+		return nil, txbuilder.ErrConsumed
+	}
 	// build_transaction consumes the builder regardless of outcome.
+	defer lockThread()()
 	raw := C.build_transaction(b.ptr)
 	b.ptr = nil
 
 	if raw == nil {
-		return nil, fmt.Errorf("build_transaction failed — ensure sender, gas object, gas_budget, gas_price and at least one command are set")
+		return nil, lastError("build_transaction", 0)
 	}
 
 	// Buffer layout: [4-byte LE uint32 payload_len][payload_len bytes BCS]
@@ -431,6 +619,29 @@ func (b *Builder) Build() ([]byte, error) {
 
 // ── internal CGo helpers ──────────────────────────────────────────────────────
 
+// lockThread pins the goroutine to its OS thread until the returned func is
+// called.  The library keeps last_error per thread, so a failing call and
+// the lastError that reads it must run on the same one.
+func lockThread() func() {
+	runtime.LockOSThread()
+	return runtime.UnlockOSThread
+}
+
+// lastError reads the last_error record left by a failing call to fn.
+func lastError(fn string, code int64) error {
+	n := C.last_error_length()
+	if n == 0 {
+		return txbuilder.DecodeError(fn, code, nil)
+	}
+	buf := C.malloc(n)
+	defer C.free(buf)
+	w := int64(C.last_error_message((*C.uint8_t)(buf), n))
+	if w < 0 {
+		return txbuilder.DecodeError(fn, code, nil)
+	}
+	return txbuilder.DecodeError(fn, code, C.GoBytes(buf, C.int(w)))
+}
+
 // goBytesCopy copies a Go []byte into a C.malloc buffer.
 // The caller must C.free the returned pointer.
 func goBytesCopy(data []byte) (unsafe.Pointer, int) {
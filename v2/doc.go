@@ -0,0 +1,6 @@
+// Package v2 provides the CGo-backed transaction builder, linked against the
+// transaction_builder static library.
+//
+// The implementation is only compiled with the txbuilder_cgo build tag; see
+// builder_cgo.go for the prerequisites.
+package v2
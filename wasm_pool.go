@@ -0,0 +1,292 @@
+// wasm_pool.go
+//
+// Embedded transaction_builder.wasm, compiled once and shared by a pool of
+// module instances.
+//
+// A Builder owns its module instance until Build or Free, so one instance
+// can't serve two goroutines at once.  WASMPool instantiates up to Size
+// modules from a single compiled module and hands each builder an idle one;
+// Build and Free return it to the pool, or close it if the builder trapped.
+//
+//	pool, err := gosuisdk.NewWASMPool(ctx, gosuisdk.WASMPoolConfig{CacheDir: "/var/cache/sui-wasm"})
+//	defer pool.Close(ctx)
+//	resp, err := split.SignExecuteTx(conn, pool, account, ctx)
+//
+// The module is not committed: go generate builds it from transaction/ into
+// wasm/, and CI (.github/workflows/ci.yml) publishes it as a build artifact.
+// Without it, DefaultBackendKind falls back to the pure-Go builder.
+
+package gosuisdk
+
+//go:generate sh -c "cd transaction && cargo build --release --target wasm32-wasip1 --no-default-features && cp target/wasm32-wasip1/release/transaction_builder.wasm ../wasm/"
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+//go:embed wasm
+var wasmFS embed.FS
+
+const embeddedWASMPath = "wasm/transaction_builder.wasm"
+
+// EmbeddedWASM returns the transaction_builder.wasm compiled into the SDK.
+func EmbeddedWASM() ([]byte, error) {
+	b, err := fs.ReadFile(wasmFS, embeddedWASMPath)
+	if err != nil {
+		return nil, fmt.Errorf("no embedded %s (run go generate in the SDK root): %w", embeddedWASMPath, err)
+	}
+	return b, nil
+}
+
+// sharedCache is the in-memory compilation cache used by every pool without
+// a CacheDir, so the module is compiled once per process.
+var (
+	sharedCacheOnce sync.Once
+	sharedCache     wazero.CompilationCache
+)
+
+// WASMPoolConfig configures NewWASMPool.  The zero value is usable.
+type WASMPoolConfig struct {
+	// WASM is the module binary; the embedded module when nil.
+	WASM []byte
+	// CacheDir persists compiled code across processes.  When empty an
+	// in-memory cache shared by all pools is used.
+	CacheDir string
+	// Size caps the number of live module instances; GOMAXPROCS when 0.
+	Size int
+	// AcquireTimeout bounds how long NewBuilder waits for an instance when
+	// all Size are held by unbuilt builders; one minute when 0, no bound
+	// when negative.  NewBuilderContext waits on its context instead.
+	AcquireTimeout time.Duration
+}
+
+// DefaultAcquireTimeout is the AcquireTimeout used when none is set.
+const DefaultAcquireTimeout = time.Minute
+
+// ErrPoolExhausted is returned by NewBuilder when no instance was released
+// within AcquireTimeout, usually because builders are never built or freed.
+var ErrPoolExhausted = errors.New("wasm pool: timed out waiting for a free instance (are builders being built or freed?)")
+
+// WASMPool is a Backend handing out builders from a pool of module
+// instances.  It is safe for concurrent use.
+type WASMPool struct {
+	ctx      context.Context
+	rt       wazero.Runtime
+	cache    wazero.CompilationCache // owned; nil for the shared cache
+	compiled wazero.CompiledModule
+
+	idle    chan api.Module
+	slots   chan struct{} // one token per live instance
+	timeout time.Duration
+
+	// newBuilder creates a builder in an instance; NewBuilder outside
+	// tests.
+	newBuilder func(ctx context.Context, mod api.Module) (pooledInstance, error)
+}
+
+// pooledInstance is a builder living in a pooled module instance.
+type pooledInstance interface {
+	txbuilder.Cloner
+	// Poisoned reports the trap that left the instance unusable, if any.
+	Poisoned() *TrapError
+}
+
+func newPooledInstance(ctx context.Context, mod api.Module) (pooledInstance, error) {
+	b, err := NewBuilder(ctx, mod)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// NewWASMPool compiles the module and returns an empty pool.  Instances are
+// created on demand.  ctx is used for every guest call made by the pool's
+// builders.
+func NewWASMPool(ctx context.Context, cfg WASMPoolConfig) (*WASMPool, error) {
+	bin := cfg.WASM
+	if bin == nil {
+		var err error
+		if bin, err = EmbeddedWASM(); err != nil {
+			return nil, err
+		}
+	}
+	size := cfg.Size
+	if size <= 0 {
+		size = runtime.GOMAXPROCS(0)
+	}
+	timeout := cfg.AcquireTimeout
+	if timeout == 0 {
+		timeout = DefaultAcquireTimeout
+	}
+
+	p := &WASMPool{
+		ctx:     ctx,
+		idle:    make(chan api.Module, size),
+		slots:   make(chan struct{}, size),
+		timeout: timeout,
+
+		newBuilder: newPooledInstance,
+	}
+	var cache wazero.CompilationCache
+	if cfg.CacheDir != "" {
+		var err error
+		if p.cache, err = wazero.NewCompilationCacheWithDir(cfg.CacheDir); err != nil {
+			return nil, fmt.Errorf("wasm compilation cache: %w", err)
+		}
+		cache = p.cache
+	} else {
+		sharedCacheOnce.Do(func() { sharedCache = wazero.NewCompilationCache() })
+		cache = sharedCache
+	}
+
+	p.rt = wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCompilationCache(cache))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, p.rt); err != nil {
+		p.Close(ctx)
+		return nil, fmt.Errorf("wasi: %w", err)
+	}
+	var err error
+	if p.compiled, err = p.rt.CompileModule(ctx, bin); err != nil {
+		p.Close(ctx)
+		return nil, fmt.Errorf("compile transaction_builder.wasm: %w", err)
+	}
+	return p, nil
+}
+
+// Close releases the runtime and every instance.  Builders still in use
+// fail afterwards.
+func (p *WASMPool) Close(ctx context.Context) error {
+	err := p.rt.Close(ctx)
+	if p.cache != nil {
+		if cerr := p.cache.Close(ctx); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// acquire returns an idle instance, instantiates a new one while below
+// Size, or waits for one to be released until ctx or the pool's context is
+// done.
+func (p *WASMPool) acquire(ctx context.Context) (api.Module, error) {
+	select {
+	case mod := <-p.idle:
+		return mod, nil
+	default:
+	}
+	select {
+	case mod := <-p.idle:
+		return mod, nil
+	case p.slots <- struct{}{}:
+		// Anonymous, so any number of instances can coexist in the runtime.
+		mod, err := p.rt.InstantiateModule(p.ctx, p.compiled, wazero.NewModuleConfig().WithName(""))
+		if err != nil {
+			<-p.slots
+			return nil, fmt.Errorf("instantiate transaction_builder.wasm: %w", err)
+		}
+		return mod, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.ctx.Done():
+		return nil, p.ctx.Err()
+	}
+}
+
+func (p *WASMPool) release(mod api.Module) {
+	p.idle <- mod
+}
+
+// discard closes a poisoned instance and frees its slot.
+func (p *WASMPool) discard(mod api.Module) {
+	mod.Close(p.ctx) //nolint:errcheck
+	forgetInstance(mod)
+	<-p.slots
+}
+
+func (p *WASMPool) Kind() BackendKind { return BackendWASM }
+
+// NewBuilder takes an instance from the pool, blocking while all Size
+// instances are in use, for at most AcquireTimeout.  The instance returns
+// to the pool on Build or Free.
+func (p *WASMPool) NewBuilder() (TxBuilder, error) {
+	if p.timeout < 0 {
+		return p.NewBuilderContext(p.ctx)
+	}
+	ctx, cancel := context.WithTimeout(p.ctx, p.timeout)
+	defer cancel()
+	b, err := p.NewBuilderContext(ctx)
+	if errors.Is(err, context.DeadlineExceeded) && p.ctx.Err() == nil {
+		return nil, ErrPoolExhausted
+	}
+	return b, err
+}
+
+// NewBuilderContext is NewBuilder waiting for an instance until ctx is
+// done.  ctx only bounds the wait; guest calls use the pool's context.
+func (p *WASMPool) NewBuilderContext(ctx context.Context) (TxBuilder, error) {
+	mod, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := p.newBuilder(p.ctx, mod)
+	if err != nil {
+		p.discard(mod)
+		return nil, err
+	}
+	return &pooledBuilder{pooledInstance: b, pool: p, mod: mod, refs: new(int)}, nil
+}
+
+// pooledBuilder returns its module instance to the pool once it and every
+// clone made from it are consumed.
+type pooledBuilder struct {
+	pooledInstance
+	pool *WASMPool
+	mod  api.Module
+	refs *int // other live builders sharing mod
+}
+
+// Clone copies the builder into the same instance, which stays out of the
+// pool until both are consumed.  Like builders of one WASMBackend, the two
+// must not be used concurrently.
+func (b *pooledBuilder) Clone() (txbuilder.Builder, error) {
+	c, err := b.pooledInstance.Clone()
+	if err != nil {
+		return nil, err
+	}
+	*b.refs++
+	return &pooledBuilder{pooledInstance: c.(pooledInstance), pool: b.pool, mod: b.mod, refs: b.refs}, nil
+}
+
+func (b *pooledBuilder) Build() ([]byte, error) {
+	defer b.done()
+	return b.pooledInstance.Build()
+}
+
+func (b *pooledBuilder) Free() {
+	b.pooledInstance.Free()
+	b.done()
+}
+
+func (b *pooledBuilder) done() {
+	switch {
+	case b.mod == nil:
+	case *b.refs > 0:
+		*b.refs--
+	case b.Poisoned() != nil:
+		b.pool.discard(b.mod)
+	default:
+		b.pool.release(b.mod)
+	}
+	b.mod = nil
+}
@@ -0,0 +1,199 @@
+package gosuisdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pictorx/go-sui-sdk/gobuilder"
+	"github.com/pictorx/go-sui-sdk/txbuilder"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// emptyModule is the smallest valid WASM binary: instances of it can be
+// created, but export none of the builder functions.
+var emptyModule = []byte("\x00asm\x01\x00\x00\x00")
+
+// fakeInstance stands in for a WASM Builder in a pooled instance, sharing
+// the instance's poison state like the real one.
+type fakeInstance struct {
+	*gobuilder.Builder
+	in *wasmInstance
+}
+
+func (f *fakeInstance) Poisoned() *TrapError { return f.in.trap }
+
+func (f *fakeInstance) Clone() (txbuilder.Builder, error) {
+	c, err := f.Builder.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return &fakeInstance{Builder: c.(*gobuilder.Builder), in: f.in}, nil
+}
+
+// testPool returns a pool of empty modules.  Unless real is set, its
+// builders are fakeInstances.
+func testPool(t *testing.T, ctx context.Context, size int, timeout time.Duration, real bool) *WASMPool {
+	t.Helper()
+	p, err := NewWASMPool(ctx, WASMPoolConfig{WASM: emptyModule, Size: size, AcquireTimeout: timeout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close(context.Background()) })
+	if !real {
+		p.newBuilder = func(_ context.Context, mod api.Module) (pooledInstance, error) {
+			return &fakeInstance{Builder: gobuilder.NewBuilder(), in: instanceFor(mod)}, nil
+		}
+	}
+	return p
+}
+
+func instanceOf(t *testing.T, b TxBuilder) api.Module {
+	t.Helper()
+	return b.(*pooledBuilder).mod
+}
+
+func clone(t *testing.T, b TxBuilder) TxBuilder {
+	t.Helper()
+	c, err := b.(txbuilder.Cloner).Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// A builder that cannot be created gives its instance's slot back.
+func TestWASMPoolBuilderFails(t *testing.T) {
+	p := testPool(t, context.Background(), 1, 10*time.Millisecond, true)
+	for i := range 3 {
+		_, err := p.NewBuilder()
+		if err == nil || errors.Is(err, ErrPoolExhausted) || !strings.Contains(err.Error(), "new_builder") {
+			t.Fatalf("attempt %d: %v, want the builder error", i, err)
+		}
+	}
+}
+
+// The instance returns to the pool once the builder and all its clones are
+// consumed.
+func TestWASMPoolClones(t *testing.T) {
+	p := testPool(t, context.Background(), 1, 10*time.Millisecond, false)
+	b, err := p.NewBuilder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod := instanceOf(t, b)
+	c := clone(t, b)
+	d := clone(t, c)
+
+	b.Free()
+	d.Free()
+	if _, err := p.NewBuilder(); !errors.Is(err, ErrPoolExhausted) {
+		t.Fatalf("NewBuilder with a clone live: %v, want ErrPoolExhausted", err)
+	}
+	if _, err := c.Build(); err == nil {
+		t.Fatal("Build of an unconfigured clone succeeded")
+	}
+
+	again, err := p.NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder after every clone was consumed: %v", err)
+	}
+	defer again.Free()
+	if instanceOf(t, again) != mod {
+		t.Error("the released instance was not reused")
+	}
+}
+
+// A poisoned instance is closed, not returned, and its slot is reused for
+// a fresh instance.
+func TestWASMPoolPoisoned(t *testing.T) {
+	p := testPool(t, context.Background(), 1, 10*time.Millisecond, false)
+	b, err := p.NewBuilder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod := instanceOf(t, b)
+	c := clone(t, b)
+	instanceFor(mod).poison("test", errors.New("unreachable"))
+
+	b.Free()
+	if mod.IsClosed() {
+		t.Fatal("instance closed while a clone is live")
+	}
+	c.Free()
+	if !mod.IsClosed() {
+		t.Fatal("poisoned instance not closed")
+	}
+
+	fresh, err := p.NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder after discarding: %v", err)
+	}
+	defer fresh.Free()
+	if instanceOf(t, fresh) == mod || fresh.(*pooledBuilder).Poisoned() != nil {
+		t.Error("the poisoned instance was handed out again")
+	}
+}
+
+func TestWASMPoolAcquire(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		p := testPool(t, context.Background(), 1, 20*time.Millisecond, false)
+		held, err := p.NewBuilder()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer held.Free()
+		start := time.Now()
+		if _, err := p.NewBuilder(); !errors.Is(err, ErrPoolExhausted) {
+			t.Fatalf("NewBuilder = %v, want ErrPoolExhausted", err)
+		}
+		if waited := time.Since(start); waited < 20*time.Millisecond {
+			t.Errorf("gave up after %v, before AcquireTimeout", waited)
+		}
+	})
+	t.Run("context", func(t *testing.T) {
+		p := testPool(t, context.Background(), 1, 20*time.Millisecond, false)
+		held, err := p.NewBuilder()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer held.Free()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := p.NewBuilderContext(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("NewBuilderContext = %v, want context.Canceled", err)
+		}
+	})
+	t.Run("pool context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		p := testPool(t, ctx, 1, time.Minute, false)
+		held, err := p.NewBuilder()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer held.Free()
+		cancel()
+		if _, err := p.NewBuilder(); !errors.Is(err, context.Canceled) || errors.Is(err, ErrPoolExhausted) {
+			t.Fatalf("NewBuilder = %v, want context.Canceled", err)
+		}
+	})
+	t.Run("released while waiting", func(t *testing.T) {
+		// No bound: the wait ends when the held builder is freed.
+		p := testPool(t, context.Background(), 1, -1, false)
+		held, err := p.NewBuilder()
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			held.Free()
+		}()
+		b, err := p.NewBuilder()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Free()
+	})
+}